@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"rockets-backend/config"
+	"rockets-backend/database"
+	"rockets-backend/models"
+	"rockets-backend/repository"
+	"rockets-backend/service"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+const apiKeyUsage = "usage: rockets-backend apikey create <name> <ingest|read|admin>|list|revoke <id>|" +
+	"ratelimit <id> <messages per second> [burst]|ratelimit <id> default"
+
+// runAPIKeyCommand handles the apikey subcommand and returns the exit code. It is how
+// the first admin key is created, further keys can be managed through /admin/keys.
+func runAPIKeyCommand(args []string, cfg config.Config, logger log.Logger) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, apiKeyUsage)
+		return 2
+	}
+
+	db, err := database.NewConnection(context.Background(), cfg.Database.Connection(), logger)
+	if err != nil {
+		_ = level.Error(logger).Log("error", "failed to connect to database", "err", err)
+		return 1
+	}
+	defer db.Close()
+
+	svc := service.NewService(logger, repository.NewPostgresRocketRepository(db),
+		service.WithAuthRepository(repository.NewPostgresAuthRepository(db)))
+
+	ctx := context.Background()
+	switch args[0] {
+	case "create":
+		if len(args) != 3 {
+			fmt.Fprintln(os.Stderr, apiKeyUsage)
+			return 2
+		}
+
+		var created *models.CreatedAPIKey
+		created, err = svc.CreateAPIKey(ctx, args[1], args[2])
+		if err == nil {
+			fmt.Printf("Created %s key %q with ID %d. Store it now, it cannot be shown again:\n%s\n",
+				created.Role, created.Name, created.ID, created.Key)
+		}
+	case "list":
+		var keys []models.APIKey
+		keys, err = svc.GetAPIKeys(ctx)
+		if err == nil {
+			printAPIKeys(keys)
+		}
+	case "revoke":
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, apiKeyUsage)
+			return 2
+		}
+		id, parseErr := strconv.ParseInt(args[1], 10, 64)
+		if parseErr != nil {
+			fmt.Fprintln(os.Stderr, apiKeyUsage)
+			return 2
+		}
+
+		var revoked bool
+		revoked, err = svc.RevokeAPIKey(ctx, id)
+		if err == nil && !revoked {
+			err = fmt.Errorf("api key %d not found or already revoked", id)
+		}
+	case "ratelimit":
+		if len(args) < 3 || len(args) > 4 {
+			fmt.Fprintln(os.Stderr, apiKeyUsage)
+			return 2
+		}
+		id, parseErr := strconv.ParseInt(args[1], 10, 64)
+		if parseErr != nil {
+			fmt.Fprintln(os.Stderr, apiKeyUsage)
+			return 2
+		}
+
+		var limit *models.RateLimit
+		if args[2] != "default" {
+			limit = &models.RateLimit{}
+			if limit.Rate, parseErr = strconv.ParseFloat(args[2], 64); parseErr != nil {
+				fmt.Fprintln(os.Stderr, apiKeyUsage)
+				return 2
+			}
+			if len(args) == 4 {
+				if limit.Burst, parseErr = strconv.Atoi(args[3]); parseErr != nil {
+					fmt.Fprintln(os.Stderr, apiKeyUsage)
+					return 2
+				}
+			}
+		}
+
+		var updated bool
+		updated, err = svc.SetAPIKeyRateLimit(ctx, id, limit)
+		if err == nil && !updated {
+			err = fmt.Errorf("api key %d not found", id)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, apiKeyUsage)
+		return 2
+	}
+
+	if err != nil {
+		_ = level.Error(logger).Log("error", "apikey "+args[0]+" failed", "err", err)
+		return 1
+	}
+
+	return 0
+}
+
+func printAPIKeys(keys []models.APIKey) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tROLE\tPREFIX\tRATE LIMIT\tLAST USED\tREVOKED")
+	for _, key := range keys {
+		rateLimit, lastUsed, revoked := "default", "never", "-"
+		if key.RateLimit != nil {
+			rateLimit = fmt.Sprintf("%g/s burst %d", key.RateLimit.Rate, key.RateLimit.Burst)
+		}
+		if key.LastUsedAt != nil {
+			lastUsed = key.LastUsedAt.Format("2006-01-02 15:04:05")
+		}
+		if key.RevokedAt != nil {
+			revoked = key.RevokedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", key.ID, key.Name, key.Role, key.Prefix, rateLimit, lastUsed,
+			revoked)
+	}
+	w.Flush()
+}
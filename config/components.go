@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"rockets-backend/database"
+	"rockets-backend/ratelimit"
+	"rockets-backend/service"
+	"rockets-backend/tracing"
+	"rockets-backend/worker"
+	"time"
+)
+
+// Connection returns the database connection settings
+func (d DatabaseConfig) Connection() database.Config {
+	return database.Config{
+		DSN:               d.DSN(),
+		MaxOpenConns:      d.MaxOpenConns,
+		MaxIdleConns:      d.MaxIdleConns,
+		ConnMaxLifetime:   time.Duration(d.ConnMaxLifetime),
+		ConnMaxIdleTime:   time.Duration(d.ConnMaxIdleTime),
+		StatementTimeout:  time.Duration(d.StatementTimeout),
+		ConnectAttempts:   d.ConnectAttempts,
+		ConnectBackoff:    time.Duration(d.ConnectBackoff),
+		MaxConnectBackoff: time.Duration(d.MaxConnectBackoff),
+	}
+}
+
+// Processor returns the event processor settings
+func (w WorkerConfig) Processor() worker.Config {
+	return worker.Config{
+		PollInterval: time.Duration(w.PollInterval),
+		BatchSize:    w.BatchSize,
+		WorkerCount:  w.WorkerCount,
+		ClaimTimeout: time.Duration(w.ClaimTimeout),
+	}
+}
+
+// Detector returns the stale rocket detector settings
+func (s StaleConfig) Detector() worker.StaleDetectorConfig {
+	return worker.StaleDetectorConfig{
+		CheckInterval: time.Duration(s.CheckInterval),
+		Threshold:     time.Duration(s.Threshold),
+	}
+}
+
+// Limits returns the ingestion limits
+func (i IngestConfig) Limits() service.IngestLimits {
+	return service.IngestLimits{
+		Client:           ratelimit.Limit{Rate: i.ClientRate, Burst: i.ClientBurst},
+		Channel:          ratelimit.Limit{Rate: i.ChannelRate, Burst: i.ChannelBurst},
+		MaxPendingEvents: i.MaxPendingEvents,
+	}
+}
+
+// Policy returns the event retention policy
+func (r RetentionConfig) Policy() service.RetentionPolicy {
+	const day = 24 * time.Hour
+	return service.RetentionPolicy{
+		Processed: time.Duration(r.ProcessedDays) * day,
+		Failed:    time.Duration(r.FailedDays) * day,
+		BatchSize: r.BatchSize,
+	}
+}
+
+// Offsets returns the path of the file holding the committed consumer offset
+func (c ConsumerConfig) Offsets() string {
+	if c.OffsetFile != "" {
+		return c.OffsetFile
+	}
+	return c.File + ".offset"
+}
+
+// Tracer returns the tracing settings
+func (t TracingConfig) Tracer() tracing.Config {
+	return tracing.Config{Exporter: t.Exporter, SampleRatio: t.SampleRatio}
+}
+
+// Instance returns the name this instance holds leases under
+func (l LeaderConfig) Instance() string {
+	if l.InstanceID != "" {
+		return l.InstanceID
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
@@ -0,0 +1,335 @@
+// Package config loads and validates the service configuration. Settings come from
+// the defaults, then an optional YAML or JSON file, then environment variables.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"rockets-backend/tracing"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secrets in printed configurations
+const redacted = "REDACTED"
+
+// Duration is a time.Duration written as a Go duration string such as "1s" or "5m"
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid duration %q, use a value such as 500ms, 10s or 5m", text)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Config is the complete service configuration
+type Config struct {
+	HTTP       HTTPConfig       `yaml:"http" json:"http"`
+	Log        LogConfig        `yaml:"log" json:"log"`
+	Storage    string           `yaml:"storage" json:"storage"` // memory or postgres
+	Database   DatabaseConfig   `yaml:"database" json:"database"`
+	Worker     WorkerConfig     `yaml:"worker" json:"worker"`
+	Stale      StaleConfig      `yaml:"stale" json:"stale"`
+	Readiness  ReadinessConfig  `yaml:"readiness" json:"readiness"`
+	Auth       AuthConfig       `yaml:"auth" json:"auth"`
+	Signatures SignaturesConfig `yaml:"signatures" json:"signatures"`
+	Ingest     IngestConfig     `yaml:"ingest" json:"ingest"`
+	Tracing    TracingConfig    `yaml:"tracing" json:"tracing"`
+	Shutdown   ShutdownConfig   `yaml:"shutdown" json:"shutdown"`
+	Leader     LeaderConfig     `yaml:"leader" json:"leader"`
+	Retention  RetentionConfig  `yaml:"retention" json:"retention"`
+	Snapshots  SnapshotsConfig  `yaml:"snapshots" json:"snapshots"`
+	Partitions PartitionsConfig `yaml:"partitions" json:"partitions"`
+	Consumer   ConsumerConfig   `yaml:"consumer" json:"consumer"`
+	GRPC       GRPCConfig       `yaml:"grpc" json:"grpc"`
+	TCP        TCPConfig        `yaml:"tcp" json:"tcp"`
+}
+
+type HTTPConfig struct {
+	Addr string `yaml:"addr" json:"addr"`
+}
+
+type LogConfig struct {
+	Level string `yaml:"level" json:"level"` // debug, info or error
+}
+
+// DatabaseConfig holds the connection settings, URL takes precedence over the individual fields
+type DatabaseConfig struct {
+	URL      string `yaml:"url" json:"url"`
+	Host     string `yaml:"host" json:"host"`
+	Port     int    `yaml:"port" json:"port"`
+	User     string `yaml:"user" json:"user"`
+	Password string `yaml:"password" json:"password"`
+	Name     string `yaml:"name" json:"name"`
+	SSLMode  string `yaml:"sslMode" json:"sslMode"`
+
+	MigrateOnStart bool `yaml:"migrateOnStart" json:"migrateOnStart"`
+
+	MaxOpenConns      int      `yaml:"maxOpenConns" json:"maxOpenConns"`
+	MaxIdleConns      int      `yaml:"maxIdleConns" json:"maxIdleConns"`
+	ConnMaxLifetime   Duration `yaml:"connMaxLifetime" json:"connMaxLifetime"`
+	ConnMaxIdleTime   Duration `yaml:"connMaxIdleTime" json:"connMaxIdleTime"`
+	StatementTimeout  Duration `yaml:"statementTimeout" json:"statementTimeout"` // 0 disables it
+	ConnectAttempts   int      `yaml:"connectAttempts" json:"connectAttempts"`
+	ConnectBackoff    Duration `yaml:"connectBackoff" json:"connectBackoff"`
+	MaxConnectBackoff Duration `yaml:"maxConnectBackoff" json:"maxConnectBackoff"`
+}
+
+// WorkerConfig configures the event processor
+type WorkerConfig struct {
+	PollInterval Duration `yaml:"pollInterval" json:"pollInterval"`
+	BatchSize    int      `yaml:"batchSize" json:"batchSize"`
+	WorkerCount  int      `yaml:"workerCount" json:"workerCount"`
+	ClaimTimeout Duration `yaml:"claimTimeout" json:"claimTimeout"`
+}
+
+// StaleConfig configures the stale rocket detector
+type StaleConfig struct {
+	CheckInterval Duration `yaml:"checkInterval" json:"checkInterval"`
+	Threshold     Duration `yaml:"threshold" json:"threshold"`
+}
+
+// ReadinessConfig holds the event backlog above which the service reports degraded, 0 disables a threshold
+type ReadinessConfig struct {
+	MaxPendingEvents int      `yaml:"maxPendingEvents" json:"maxPendingEvents"`
+	MaxPendingAge    Duration `yaml:"maxPendingAge" json:"maxPendingAge"`
+}
+
+type AuthConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+}
+
+type SignaturesConfig struct {
+	Required     bool     `yaml:"required" json:"required"`
+	MaxSkew      Duration `yaml:"maxSkew" json:"maxSkew"`
+	BindChannels bool     `yaml:"bindChannels" json:"bindChannels"`
+}
+
+// IngestConfig holds the ingestion rate limits, 0 disables a limit
+type IngestConfig struct {
+	ClientRate       float64 `yaml:"clientRate" json:"clientRate"`
+	ClientBurst      int     `yaml:"clientBurst" json:"clientBurst"`
+	ChannelRate      float64 `yaml:"channelRate" json:"channelRate"`
+	ChannelBurst     int     `yaml:"channelBurst" json:"channelBurst"`
+	MaxPendingEvents int     `yaml:"maxPendingEvents" json:"maxPendingEvents"`
+}
+
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" json:"exporter"` // none or stdout
+	SampleRatio float64 `yaml:"sampleRatio" json:"sampleRatio"`
+}
+
+// ShutdownConfig bounds the whole graceful shutdown, HTTP drain and event processor included
+type ShutdownConfig struct {
+	Timeout Duration `yaml:"timeout" json:"timeout"`
+}
+
+// LeaderConfig configures the election of the instance that runs the singleton jobs
+type LeaderConfig struct {
+	InstanceID string   `yaml:"instanceId" json:"instanceId"` // defaults to <hostname>-<pid>
+	LeaseTTL   Duration `yaml:"leaseTTL" json:"leaseTTL"`
+}
+
+// RetentionConfig holds how many days events stay in rocket_events per status before
+// they are archived, 0 keeps them
+type RetentionConfig struct {
+	ProcessedDays int      `yaml:"processedDays" json:"processedDays"`
+	FailedDays    int      `yaml:"failedDays" json:"failedDays"`
+	Interval      Duration `yaml:"interval" json:"interval"`
+	BatchSize     int      `yaml:"batchSize" json:"batchSize"`
+}
+
+// SnapshotsConfig sets how many messages apart rocket snapshots are taken, 0 disables them
+type SnapshotsConfig struct {
+	Every int `yaml:"every" json:"every"`
+}
+
+// PartitionsConfig configures the maintenance of the daily rocket_events partitions
+// (PostgreSQL storage only)
+type PartitionsConfig struct {
+	DaysAhead     int      `yaml:"daysAhead" json:"daysAhead"`
+	CheckInterval Duration `yaml:"checkInterval" json:"checkInterval"`
+}
+
+// ConsumerConfig configures ingestion from a tailed file of newline-delimited JSON messages,
+// an empty File disables it. OffsetFile defaults to File with an .offset suffix.
+type ConsumerConfig struct {
+	File         string   `yaml:"file" json:"file"`
+	OffsetFile   string   `yaml:"offsetFile" json:"offsetFile"`
+	PollInterval Duration `yaml:"pollInterval" json:"pollInterval"`
+}
+
+// GRPCConfig configures the gRPC API, an empty Addr disables it. WatchInterval is how
+// often WatchRockets streams check the rockets for changes.
+type GRPCConfig struct {
+	Addr          string   `yaml:"addr" json:"addr"`
+	WatchInterval Duration `yaml:"watchInterval" json:"watchInterval"`
+}
+
+// TCPConfig configures raw TCP ingestion, an empty Addr disables it
+type TCPConfig struct {
+	Addr           string   `yaml:"addr" json:"addr"`
+	MaxConnections int      `yaml:"maxConnections" json:"maxConnections"`
+	IdleTimeout    Duration `yaml:"idleTimeout" json:"idleTimeout"` // close connections without a frame for this long
+	MaxFrameBytes  int      `yaml:"maxFrameBytes" json:"maxFrameBytes"`
+}
+
+// Default returns the configuration used when nothing is overridden
+func Default() Config {
+	return Config{
+		HTTP:    HTTPConfig{Addr: ":8088"},
+		Log:     LogConfig{Level: "debug"},
+		Storage: "postgres",
+		Database: DatabaseConfig{
+			Host:              "localhost",
+			Port:              5432,
+			User:              "postgres",
+			Password:          "postgres",
+			Name:              "rockets",
+			SSLMode:           "disable",
+			MigrateOnStart:    true,
+			MaxOpenConns:      25,
+			MaxIdleConns:      5,
+			ConnMaxLifetime:   Duration(5 * time.Minute),
+			ConnMaxIdleTime:   Duration(time.Minute),
+			StatementTimeout:  Duration(30 * time.Second),
+			ConnectAttempts:   10,
+			ConnectBackoff:    Duration(500 * time.Millisecond),
+			MaxConnectBackoff: Duration(10 * time.Second),
+		},
+		Worker: WorkerConfig{
+			PollInterval: Duration(time.Second),
+			BatchSize:    10,
+			WorkerCount:  2,
+			ClaimTimeout: Duration(5 * time.Minute),
+		},
+		Stale: StaleConfig{
+			CheckInterval: Duration(10 * time.Second),
+			Threshold:     Duration(time.Minute),
+		},
+		Readiness: ReadinessConfig{
+			MaxPendingEvents: 1000,
+			MaxPendingAge:    Duration(time.Minute),
+		},
+		Signatures: SignaturesConfig{MaxSkew: Duration(5 * time.Minute)},
+		Tracing:    TracingConfig{Exporter: tracing.ExporterNone, SampleRatio: 1},
+		Shutdown:   ShutdownConfig{Timeout: Duration(30 * time.Second)},
+		Leader:     LeaderConfig{LeaseTTL: Duration(15 * time.Second)},
+		Retention:  RetentionConfig{Interval: Duration(time.Hour), BatchSize: 1000},
+		Snapshots:  SnapshotsConfig{Every: 100},
+		Partitions: PartitionsConfig{DaysAhead: 7, CheckInterval: Duration(time.Hour)},
+		Consumer:   ConsumerConfig{PollInterval: Duration(500 * time.Millisecond)},
+		GRPC:       GRPCConfig{Addr: ":9088", WatchInterval: Duration(time.Second)},
+		TCP:        TCPConfig{MaxConnections: 100, IdleTimeout: Duration(time.Minute), MaxFrameBytes: 1 << 20},
+	}
+}
+
+// Load reads the configuration file at path, if any, applies the environment
+// overrides and validates the result. All problems are reported together.
+func Load(path string) (Config, error) {
+	config := Default()
+
+	if path != "" {
+		if err := config.readFile(path); err != nil {
+			return config, err
+		}
+	}
+	envErr := config.applyEnv(os.LookupEnv)
+	return config, errors.Join(envErr, config.Validate())
+}
+
+// readFile decodes a YAML or JSON file over the current values. Unknown fields are
+// rejected so typos do not silently fall back to defaults.
+func (c *Config) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(c)
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err = decoder.Decode(c); errors.Is(err, io.EOF) {
+			err = nil // empty file
+		}
+	default:
+		return fmt.Errorf("unsupported config file %s, use .yaml, .yml or .json", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// DSN returns the connection string for lib/pq
+func (d DatabaseConfig) DSN() string {
+	if d.URL != "" {
+		return d.URL
+	}
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		d.Host, d.Port, d.User, d.Password, d.Name, d.SSLMode)
+}
+
+// Redacted returns a copy that is safe to print, with passwords replaced
+func (c Config) Redacted() Config {
+	if c.Database.Password != "" {
+		c.Database.Password = redacted
+	}
+	if c.Database.URL != "" {
+		c.Database.URL = redactURL(c.Database.URL)
+	}
+	return c
+}
+
+// redactURL hides the password of a postgres:// URL or of a key=value connection string
+func redactURL(dsn string) string {
+	if parsed, err := url.Parse(dsn); err == nil && parsed.Scheme != "" {
+		if _, ok := parsed.User.Password(); ok {
+			parsed.User = url.UserPassword(parsed.User.Username(), redacted)
+		}
+		query := parsed.Query()
+		if query.Has("password") {
+			query.Set("password", redacted)
+			parsed.RawQuery = query.Encode()
+		}
+		return parsed.String()
+	}
+
+	fields := strings.Fields(dsn)
+	for i, field := range fields {
+		if key, _, ok := strings.Cut(field, "="); ok && key == "password" {
+			fields[i] = "password=" + redacted
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// YAML renders the configuration as YAML, as accepted by Load
+func (c Config) YAML() (string, error) {
+	out, err := yaml.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to render config: %w", err)
+	}
+	return string(out), nil
+}
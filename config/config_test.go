@@ -0,0 +1,129 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"rockets-backend/config"
+	"rockets-backend/testutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	testutil.AssertNoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestDefaultsAreValid(t *testing.T) {
+	testutil.AssertNoError(t, config.Default().Validate())
+}
+
+func TestLoadFileWithEnvOverrides(t *testing.T) {
+	path := writeFile(t, "rockets.yaml", `
+http:
+  addr: ":9000"
+storage: memory
+worker:
+  pollInterval: 250ms
+  batchSize: 50
+ingest:
+  channelRate: 5
+`)
+	t.Setenv("POLLING_BATCH_SIZE", "20")
+	t.Setenv("STALE_THRESHOLD_SECONDS", "90")
+
+	cfg, err := config.Load(path)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, ":9000", cfg.HTTP.Addr)
+	testutil.AssertEqual(t, "memory", cfg.Storage)
+	testutil.AssertEqual(t, 250*time.Millisecond, time.Duration(cfg.Worker.PollInterval))
+	testutil.AssertEqual(t, 20, cfg.Worker.BatchSize)
+	testutil.AssertEqual(t, 90*time.Second, time.Duration(cfg.Stale.Threshold))
+	testutil.AssertEqual(t, 5.0, cfg.Ingest.Limits().Channel.Rate)
+
+	// Fields missing from the file keep their defaults
+	testutil.AssertEqual(t, 2, cfg.Worker.WorkerCount)
+}
+
+func TestLoadJSON(t *testing.T) {
+	path := writeFile(t, "rockets.json", `{"log": {"level": "info"}, "database": {"connectBackoff": "1s", "maxConnectBackoff": "1m"}}`)
+
+	cfg, err := config.Load(path)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "info", cfg.Log.Level)
+	testutil.AssertEqual(t, time.Second, cfg.Database.Connection().ConnectBackoff)
+}
+
+func TestLoadRejectsUnknownFields(t *testing.T) {
+	for name, content := range map[string]string{
+		"rockets.yaml": "worker:\n  pollIntervall: 1s\n",
+		"rockets.json": `{"worker": {"pollIntervall": "1s"}}`,
+	} {
+		_, err := config.Load(writeFile(t, name, content))
+		if err == nil || !strings.Contains(err.Error(), "pollIntervall") {
+			t.Errorf("%s: expected the unknown field to be reported, got %v", name, err)
+		}
+	}
+}
+
+func TestLoadReportsEveryProblem(t *testing.T) {
+	t.Setenv("POLLING_WORKER_COUNT", "two")
+	t.Setenv("AUTH_ENABLED", "yes please")
+
+	_, err := config.Load("")
+	if err == nil {
+		t.Fatal("Expected invalid environment variables to be rejected")
+	}
+	for _, want := range []string{"POLLING_WORKER_COUNT", "AUTH_ENABLED"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %s in %q", want, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := config.Default()
+	cfg.Worker.PollInterval = 0
+	cfg.Log.Level = "verbose"
+	cfg.Tracing.SampleRatio = 2
+	cfg.Database.MaxIdleConns = 50
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Expected the config to be invalid")
+	}
+	for _, want := range []string{"worker.pollInterval", "log.level", "tracing.sampleRatio", "database.maxIdleConns"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %s in %q", want, err)
+		}
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := config.Default()
+	cfg.Database.Password = "hunter2"
+
+	out, err := cfg.Redacted().YAML()
+	testutil.AssertNoError(t, err)
+	if strings.Contains(out, "hunter2") {
+		t.Fatalf("Expected the password to be redacted:\n%s", out)
+	}
+	if !strings.Contains(out, "pollInterval: 1s") {
+		t.Errorf("Expected durations to be printed as duration strings:\n%s", out)
+	}
+
+	for _, url := range []string{
+		"postgres://rockets:hunter2@db:5432/rockets?sslmode=disable",
+		"postgres://db/rockets?password=hunter2",
+		"host=db user=rockets password=hunter2 dbname=rockets",
+	} {
+		cfg.Database.URL = url
+		if redacted := cfg.Redacted().Database.URL; strings.Contains(redacted, "hunter2") {
+			t.Errorf("Expected the password in %q to be redacted, got %q", url, redacted)
+		}
+	}
+}
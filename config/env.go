@@ -0,0 +1,165 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// envOverride sets a field from an environment variable
+type envOverride struct {
+	name  string
+	apply func(value string) error
+}
+
+// envOverrides binds the environment variables to the fields of c. Durations keep
+// the unit in their variable name for compatibility, and also accept duration strings.
+func (c *Config) envOverrides() []envOverride {
+	return []envOverride{
+		stringEnv("HTTP_PORT", &c.HTTP.Addr),
+		stringEnv("LOG_LEVEL", &c.Log.Level),
+		stringEnv("STORAGE", &c.Storage),
+
+		stringEnv("DATABASE_URL", &c.Database.URL),
+		stringEnv("DB_HOST", &c.Database.Host),
+		intEnv("DB_PORT", &c.Database.Port),
+		stringEnv("DB_USER", &c.Database.User),
+		stringEnv("DB_PASSWORD", &c.Database.Password),
+		stringEnv("DB_NAME", &c.Database.Name),
+		stringEnv("DB_SSLMODE", &c.Database.SSLMode),
+		boolEnv("MIGRATE_ON_START", &c.Database.MigrateOnStart),
+		intEnv("DB_MAX_OPEN_CONNS", &c.Database.MaxOpenConns),
+		intEnv("DB_MAX_IDLE_CONNS", &c.Database.MaxIdleConns),
+		durationEnv("DB_CONN_MAX_LIFETIME_SECONDS", time.Second, &c.Database.ConnMaxLifetime),
+		durationEnv("DB_CONN_MAX_IDLE_TIME_SECONDS", time.Second, &c.Database.ConnMaxIdleTime),
+		durationEnv("DB_STATEMENT_TIMEOUT_MS", time.Millisecond, &c.Database.StatementTimeout),
+		intEnv("DB_CONNECT_ATTEMPTS", &c.Database.ConnectAttempts),
+		durationEnv("DB_CONNECT_BACKOFF_MS", time.Millisecond, &c.Database.ConnectBackoff),
+		durationEnv("DB_MAX_CONNECT_BACKOFF_MS", time.Millisecond, &c.Database.MaxConnectBackoff),
+
+		// POLLING_INTEVAL_SECONDS is the original, misspelled name
+		durationEnv("POLLING_INTEVAL_SECONDS", time.Second, &c.Worker.PollInterval),
+		durationEnv("POLLING_INTERVAL_SECONDS", time.Second, &c.Worker.PollInterval),
+		intEnv("POLLING_BATCH_SIZE", &c.Worker.BatchSize),
+		intEnv("POLLING_WORKER_COUNT", &c.Worker.WorkerCount),
+		durationEnv("POLLING_CLAIM_TIMEOUT_SECONDS", time.Second, &c.Worker.ClaimTimeout),
+
+		durationEnv("STALE_CHECK_INTERVAL_SECONDS", time.Second, &c.Stale.CheckInterval),
+		durationEnv("STALE_THRESHOLD_SECONDS", time.Second, &c.Stale.Threshold),
+
+		intEnv("READY_MAX_PENDING_EVENTS", &c.Readiness.MaxPendingEvents),
+		durationEnv("READY_MAX_PENDING_AGE_SECONDS", time.Second, &c.Readiness.MaxPendingAge),
+
+		boolEnv("AUTH_ENABLED", &c.Auth.Enabled),
+
+		boolEnv("SIGNATURES_REQUIRED", &c.Signatures.Required),
+		durationEnv("SIGNATURE_MAX_SKEW_SECONDS", time.Second, &c.Signatures.MaxSkew),
+		boolEnv("SIGNATURE_BIND_CHANNELS", &c.Signatures.BindChannels),
+
+		floatEnv("INGEST_CLIENT_RATE", &c.Ingest.ClientRate),
+		intEnv("INGEST_CLIENT_BURST", &c.Ingest.ClientBurst),
+		floatEnv("INGEST_CHANNEL_RATE", &c.Ingest.ChannelRate),
+		intEnv("INGEST_CHANNEL_BURST", &c.Ingest.ChannelBurst),
+		intEnv("INGEST_MAX_PENDING_EVENTS", &c.Ingest.MaxPendingEvents),
+
+		stringEnv("TRACING_EXPORTER", &c.Tracing.Exporter),
+		floatEnv("TRACING_SAMPLE_RATIO", &c.Tracing.SampleRatio),
+
+		durationEnv("SHUTDOWN_TIMEOUT_SECONDS", time.Second, &c.Shutdown.Timeout),
+
+		stringEnv("INSTANCE_ID", &c.Leader.InstanceID),
+		durationEnv("LEADER_LEASE_TTL_SECONDS", time.Second, &c.Leader.LeaseTTL),
+
+		intEnv("RETENTION_PROCESSED_DAYS", &c.Retention.ProcessedDays),
+		intEnv("RETENTION_FAILED_DAYS", &c.Retention.FailedDays),
+		durationEnv("RETENTION_INTERVAL_SECONDS", time.Second, &c.Retention.Interval),
+		intEnv("RETENTION_BATCH_SIZE", &c.Retention.BatchSize),
+
+		intEnv("SNAPSHOT_EVERY_MESSAGES", &c.Snapshots.Every),
+
+		intEnv("PARTITION_DAYS_AHEAD", &c.Partitions.DaysAhead),
+		durationEnv("PARTITION_CHECK_INTERVAL_SECONDS", time.Second, &c.Partitions.CheckInterval),
+
+		stringEnv("CONSUMER_FILE", &c.Consumer.File),
+		stringEnv("CONSUMER_OFFSET_FILE", &c.Consumer.OffsetFile),
+		durationEnv("CONSUMER_POLL_INTERVAL_MS", time.Millisecond, &c.Consumer.PollInterval),
+		stringEnv("GRPC_PORT", &c.GRPC.Addr),
+		durationEnv("GRPC_WATCH_INTERVAL_MS", time.Millisecond, &c.GRPC.WatchInterval),
+		stringEnv("TCP_PORT", &c.TCP.Addr),
+		intEnv("TCP_MAX_CONNECTIONS", &c.TCP.MaxConnections),
+		durationEnv("TCP_IDLE_TIMEOUT_SECONDS", time.Second, &c.TCP.IdleTimeout),
+		intEnv("TCP_MAX_FRAME_BYTES", &c.TCP.MaxFrameBytes),
+	}
+}
+
+// applyEnv applies the variables that are set and not empty
+func (c *Config) applyEnv(lookup func(name string) (string, bool)) error {
+	var errs []error
+	for _, override := range c.envOverrides() {
+		value, ok := lookup(override.name)
+		if !ok || value == "" {
+			continue
+		}
+		if err := override.apply(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", override.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func stringEnv(name string, field *string) envOverride {
+	return envOverride{name: name, apply: func(value string) error {
+		*field = value
+		return nil
+	}}
+}
+
+func intEnv(name string, field *int) envOverride {
+	return envOverride{name: name, apply: func(value string) error {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		*field = parsed
+		return nil
+	}}
+}
+
+func floatEnv(name string, field *float64) envOverride {
+	return envOverride{name: name, apply: func(value string) error {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		*field = parsed
+		return nil
+	}}
+}
+
+func boolEnv(name string, field *bool) envOverride {
+	return envOverride{name: name, apply: func(value string) error {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q, use true or false", value)
+		}
+		*field = parsed
+		return nil
+	}}
+}
+
+// durationEnv reads a whole number of units, or a duration string such as "1m30s"
+func durationEnv(name string, unit time.Duration, field *Duration) envOverride {
+	return envOverride{name: name, apply: func(value string) error {
+		if count, err := strconv.Atoi(value); err == nil {
+			*field = Duration(time.Duration(count) * unit)
+			return nil
+		}
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", value)
+		}
+		*field = Duration(parsed)
+		return nil
+	}}
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"rockets-backend/tracing"
+	"rockets-backend/transport/tcp_transport"
+	"time"
+)
+
+// Accepted values of the enumerated settings
+var (
+	logLevels        = []string{"debug", "info", "error"}
+	storages         = []string{"memory", "postgres"}
+	tracingExporters = []string{tracing.ExporterNone, tracing.ExporterStdout}
+)
+
+// validator collects every invalid field instead of stopping at the first
+type validator struct {
+	errs []error
+}
+
+func (v *validator) check(ok bool, field string, format string, args ...interface{}) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf("%s %s", field, fmt.Sprintf(format, args...)))
+	}
+}
+
+func (v *validator) oneOf(field, value string, allowed []string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.check(false, field, "must be one of %v, got %q", allowed, value)
+}
+
+func (v *validator) positive(field string, value int) {
+	v.check(value > 0, field, "must be positive, got %d", value)
+}
+
+func (v *validator) notNegative(field string, value int) {
+	v.check(value >= 0, field, "must not be negative, got %d", value)
+}
+
+func (v *validator) positiveDuration(field string, value Duration) {
+	v.check(value > 0, field, "must be positive, got %s", time.Duration(value))
+}
+
+func (v *validator) notNegativeDuration(field string, value Duration) {
+	v.check(value >= 0, field, "must not be negative, got %s", time.Duration(value))
+}
+
+// Validate checks every field and returns all problems joined into one error
+func (c Config) Validate() error {
+	v := &validator{}
+
+	_, _, err := net.SplitHostPort(c.HTTP.Addr)
+	v.check(err == nil, "http.addr", "must be a listen address such as :8088, got %q", c.HTTP.Addr)
+	v.oneOf("log.level", c.Log.Level, logLevels)
+	v.oneOf("storage", c.Storage, storages)
+
+	if c.Storage == "postgres" {
+		db := c.Database
+		if db.URL == "" {
+			v.check(db.Host != "", "database.host", "must be set")
+			v.check(db.Port > 0 && db.Port < 65536, "database.port", "must be a port number, got %d", db.Port)
+			v.check(db.Name != "", "database.name", "must be set")
+		}
+		v.positive("database.maxOpenConns", db.MaxOpenConns)
+		v.notNegative("database.maxIdleConns", db.MaxIdleConns)
+		v.check(db.MaxIdleConns <= db.MaxOpenConns, "database.maxIdleConns",
+			"must not exceed maxOpenConns (%d), got %d", db.MaxOpenConns, db.MaxIdleConns)
+		v.notNegativeDuration("database.connMaxLifetime", db.ConnMaxLifetime)
+		v.notNegativeDuration("database.connMaxIdleTime", db.ConnMaxIdleTime)
+		v.notNegativeDuration("database.statementTimeout", db.StatementTimeout)
+		v.positive("database.connectAttempts", db.ConnectAttempts)
+		v.positiveDuration("database.connectBackoff", db.ConnectBackoff)
+		v.check(db.MaxConnectBackoff >= db.ConnectBackoff, "database.maxConnectBackoff",
+			"must not be less than connectBackoff (%s), got %s",
+			time.Duration(db.ConnectBackoff), time.Duration(db.MaxConnectBackoff))
+	}
+
+	v.positiveDuration("worker.pollInterval", c.Worker.PollInterval)
+	v.positive("worker.batchSize", c.Worker.BatchSize)
+	v.positive("worker.workerCount", c.Worker.WorkerCount)
+	v.positiveDuration("worker.claimTimeout", c.Worker.ClaimTimeout)
+
+	v.positiveDuration("stale.checkInterval", c.Stale.CheckInterval)
+	v.positiveDuration("stale.threshold", c.Stale.Threshold)
+
+	v.notNegative("readiness.maxPendingEvents", c.Readiness.MaxPendingEvents)
+	v.notNegativeDuration("readiness.maxPendingAge", c.Readiness.MaxPendingAge)
+
+	v.positiveDuration("signatures.maxSkew", c.Signatures.MaxSkew)
+
+	v.check(c.Ingest.ClientRate >= 0, "ingest.clientRate", "must not be negative, got %g", c.Ingest.ClientRate)
+	v.notNegative("ingest.clientBurst", c.Ingest.ClientBurst)
+	v.check(c.Ingest.ChannelRate >= 0, "ingest.channelRate", "must not be negative, got %g", c.Ingest.ChannelRate)
+	v.notNegative("ingest.channelBurst", c.Ingest.ChannelBurst)
+	v.notNegative("ingest.maxPendingEvents", c.Ingest.MaxPendingEvents)
+
+	v.oneOf("tracing.exporter", c.Tracing.Exporter, tracingExporters)
+	v.check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sampleRatio",
+		"must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+
+	v.positiveDuration("shutdown.timeout", c.Shutdown.Timeout)
+
+	v.check(time.Duration(c.Leader.LeaseTTL) >= time.Second, "leader.leaseTTL", "must be at least 1s, got %s",
+		time.Duration(c.Leader.LeaseTTL))
+
+	v.notNegative("retention.processedDays", c.Retention.ProcessedDays)
+	v.notNegative("retention.failedDays", c.Retention.FailedDays)
+	v.positiveDuration("retention.interval", c.Retention.Interval)
+	v.positive("retention.batchSize", c.Retention.BatchSize)
+
+	v.notNegative("snapshots.every", c.Snapshots.Every)
+
+	// A run must come before the last created partition is reached
+	v.positive("partitions.daysAhead", c.Partitions.DaysAhead)
+	v.positiveDuration("partitions.checkInterval", c.Partitions.CheckInterval)
+	v.check(time.Duration(c.Partitions.CheckInterval) < time.Duration(c.Partitions.DaysAhead)*24*time.Hour,
+		"partitions.checkInterval", "must be shorter than partitions.daysAhead days, got %s",
+		time.Duration(c.Partitions.CheckInterval))
+
+	v.positiveDuration("consumer.pollInterval", c.Consumer.PollInterval)
+	v.check(c.Consumer.OffsetFile == "" || c.Consumer.File != "", "consumer.offsetFile",
+		"requires consumer.file")
+
+	if c.GRPC.Addr != "" {
+		_, _, err := net.SplitHostPort(c.GRPC.Addr)
+		v.check(err == nil, "grpc.addr", "must be a listen address such as :9088, got %q", c.GRPC.Addr)
+		v.check(err != nil || c.GRPC.Addr != c.HTTP.Addr, "grpc.addr", "must differ from http.addr, got %q",
+			c.GRPC.Addr)
+	}
+	v.positiveDuration("grpc.watchInterval", c.GRPC.WatchInterval)
+
+	if c.TCP.Addr != "" {
+		_, _, err := net.SplitHostPort(c.TCP.Addr)
+		v.check(err == nil, "tcp.addr", "must be a listen address such as :9089, got %q", c.TCP.Addr)
+		v.check(err != nil || (c.TCP.Addr != c.HTTP.Addr && c.TCP.Addr != c.GRPC.Addr), "tcp.addr",
+			"must differ from http.addr and grpc.addr, got %q", c.TCP.Addr)
+	}
+	v.positive("tcp.maxConnections", c.TCP.MaxConnections)
+	v.positiveDuration("tcp.idleTimeout", c.TCP.IdleTimeout)
+	v.positive("tcp.maxFrameBytes", c.TCP.MaxFrameBytes)
+	v.check(c.TCP.MaxFrameBytes <= tcp_transport.MaxFrameLimit, "tcp.maxFrameBytes", "must be at most %d, got %d",
+		tcp_transport.MaxFrameLimit, c.TCP.MaxFrameBytes)
+
+	return errors.Join(v.errs...)
+}
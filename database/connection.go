@@ -1,32 +1,107 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"rockets-backend/pkg"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
 	_ "github.com/lib/pq"
 )
 
-func NewConnection() (*sql.DB, error) {
-	host := pkg.GetEnv("DB_HOST", "localhost")
-	port := pkg.GetEnv("DB_PORT", "5432")
-	user := pkg.GetEnv("DB_USER", "postgres")
-	password := pkg.GetEnv("DB_PASSWORD", "postgres")
-	dbname := pkg.GetEnv("DB_NAME", "rockets")
-	sslmode := pkg.GetEnv("DB_SSLMODE", "disable")
+// Config holds the connection settings, pool sizing and startup retry policy.
+// ConnectAttempts bounds the number of pings on startup; the wait between
+// attempts starts at ConnectBackoff and doubles up to MaxConnectBackoff.
+type Config struct {
+	DSN               string
+	MaxOpenConns      int
+	MaxIdleConns      int
+	ConnMaxLifetime   time.Duration
+	ConnMaxIdleTime   time.Duration
+	StatementTimeout  time.Duration
+	ConnectAttempts   int
+	ConnectBackoff    time.Duration
+	MaxConnectBackoff time.Duration
+}
 
-	psqlInfo := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		host, port, user, password, dbname, sslmode)
+// NewConnection opens a connection pool and waits for postgres to accept
+// connections, retrying with exponential backoff.
+func NewConnection(ctx context.Context, config Config, logger log.Logger) (*sql.DB, error) {
+	dsn, err := withStatementTimeout(config.DSN, config.StatementTimeout)
+	if err != nil {
+		return nil, err
+	}
 
-	db, err := sql.Open("postgres", psqlInfo)
+	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
 	}
 
-	if err = db.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetMaxIdleConns(config.MaxIdleConns)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+
+	attempts := config.ConnectAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := config.ConnectBackoff
+
+	for attempt := 1; ; attempt++ {
+		err = db.PingContext(ctx)
+		if err == nil {
+			return db, nil
+		}
+		if attempt >= attempts {
+			break
+		}
+
+		_ = level.Warn(logger).Log("msg", "postgres not ready, retrying", "attempt", attempt,
+			"retryIn", backoff, "error", err)
+
+		select {
+		case <-ctx.Done():
+			db.Close()
+			return nil, fmt.Errorf("failed to ping postgres: %w", ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if config.MaxConnectBackoff > 0 && backoff > config.MaxConnectBackoff {
+			backoff = config.MaxConnectBackoff
+		}
+	}
+
+	db.Close()
+	return nil, fmt.Errorf("failed to ping postgres after %d attempts: %w", attempts, err)
+}
+
+// withStatementTimeout adds a statement_timeout runtime parameter to the DSN,
+// which lib/pq passes through to the server for every connection. A timeout
+// already present in the DSN is left alone.
+func withStatementTimeout(dsn string, timeout time.Duration) (string, error) {
+	if timeout <= 0 || strings.Contains(dsn, "statement_timeout") {
+		return dsn, nil
+	}
+	ms := strconv.FormatInt(timeout.Milliseconds(), 10)
+
+	if !strings.HasPrefix(dsn, "postgres://") && !strings.HasPrefix(dsn, "postgresql://") {
+		return dsn + " statement_timeout=" + ms, nil
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", fmt.Errorf("invalid DATABASE_URL: %w", err)
 	}
+	query := u.Query()
+	query.Set("statement_timeout", ms)
+	u.RawQuery = query.Encode()
 
-	return db, nil
+	return u.String(), nil
 }
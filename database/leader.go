@@ -0,0 +1,154 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"rockets-backend/models"
+	"sort"
+	"sync"
+	"time"
+)
+
+// leaseTimeout bounds each lease query, a renewal must not outlive the lease
+const leaseTimeout = 5 * time.Second
+
+// LeaseStore hands out named leases, each owned by one holder at a time until it
+// expires. They elect the instance that runs a singleton background job.
+type LeaseStore interface {
+	// TryAcquire takes the lease for holder if it is free or expired, or renews it if
+	// holder owns it. Returns whether holder owns the lease for the next ttl.
+	TryAcquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	// Release gives up the lease if holder owns it, so another holder can take over at once
+	Release(ctx context.Context, name, holder string) error
+	// Leases returns every lease, including expired ones
+	Leases(ctx context.Context) ([]models.LeaderLease, error)
+}
+
+// PostgresLeaseStore keeps the leases in the leader_leases table
+type PostgresLeaseStore struct {
+	db *sql.DB
+}
+
+func NewPostgresLeaseStore(db *sql.DB) *PostgresLeaseStore {
+	return &PostgresLeaseStore{db: db}
+}
+
+func (s *PostgresLeaseStore) TryAcquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, leaseTimeout)
+	defer cancel()
+
+	// The upsert only changes a lease that is expired or already ours, row locking
+	// makes concurrent attempts take turns so only one of them gets it
+	query := `
+		INSERT INTO leader_leases (name, holder, acquired_at, renewed_at, expires_at)
+		VALUES ($1, $2, now(), now(), now() + $3 * interval '1 millisecond')
+		ON CONFLICT (name) DO UPDATE
+		SET holder = EXCLUDED.holder,
+		    acquired_at = CASE WHEN leader_leases.holder = EXCLUDED.holder
+		                       THEN leader_leases.acquired_at ELSE EXCLUDED.acquired_at END,
+		    renewed_at = EXCLUDED.renewed_at,
+		    expires_at = EXCLUDED.expires_at
+		WHERE leader_leases.holder = EXCLUDED.holder OR leader_leases.expires_at < now()
+		RETURNING holder`
+
+	var owner string
+	err := s.db.QueryRowContext(ctx, query, name, holder, ttl.Milliseconds()).Scan(&owner)
+	if err == sql.ErrNoRows {
+		return false, nil // held by someone else
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lease %s: %w", name, err)
+	}
+	return owner == holder, nil
+}
+
+func (s *PostgresLeaseStore) Release(ctx context.Context, name, holder string) error {
+	ctx, cancel := context.WithTimeout(ctx, leaseTimeout)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, `DELETE FROM leader_leases WHERE name = $1 AND holder = $2`, name, holder)
+	if err != nil {
+		return fmt.Errorf("failed to release lease %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *PostgresLeaseStore) Leases(ctx context.Context) ([]models.LeaderLease, error) {
+	ctx, cancel := context.WithTimeout(ctx, leaseTimeout)
+	defer cancel()
+
+	query := `
+		SELECT name, holder, acquired_at, renewed_at, expires_at, expires_at < now()
+		FROM leader_leases
+		ORDER BY name`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query leases: %w", err)
+	}
+	defer rows.Close()
+
+	leases := []models.LeaderLease{}
+	for rows.Next() {
+		var lease models.LeaderLease
+		if err := rows.Scan(&lease.Name, &lease.Holder, &lease.AcquiredAt, &lease.RenewedAt, &lease.ExpiresAt,
+			&lease.Expired); err != nil {
+			return nil, fmt.Errorf("failed to scan lease: %w", err)
+		}
+		leases = append(leases, lease)
+	}
+	return leases, rows.Err()
+}
+
+// MemoryLeaseStore keeps the leases in memory, for a single instance with in-memory storage
+type MemoryLeaseStore struct {
+	mu     sync.Mutex
+	leases map[string]models.LeaderLease
+}
+
+func NewMemoryLeaseStore() *MemoryLeaseStore {
+	return &MemoryLeaseStore{leases: make(map[string]models.LeaderLease)}
+}
+
+func (s *MemoryLeaseStore) TryAcquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	lease, ok := s.leases[name]
+	if ok && lease.Holder != holder && !now.After(lease.ExpiresAt) {
+		return false, nil
+	}
+	if !ok || lease.Holder != holder {
+		lease = models.LeaderLease{Name: name, Holder: holder, AcquiredAt: now}
+	}
+	lease.RenewedAt = now
+	lease.ExpiresAt = now.Add(ttl)
+	s.leases[name] = lease
+	return true, nil
+}
+
+func (s *MemoryLeaseStore) Release(ctx context.Context, name, holder string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if lease, ok := s.leases[name]; ok && lease.Holder == holder {
+		delete(s.leases, name)
+	}
+	return nil
+}
+
+func (s *MemoryLeaseStore) Leases(ctx context.Context) ([]models.LeaderLease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	leases := make([]models.LeaderLease, 0, len(s.leases))
+	for _, lease := range s.leases {
+		lease.Expired = now.After(lease.ExpiresAt)
+		leases = append(leases, lease)
+	}
+	sort.Slice(leases, func(i, j int) bool { return leases[i].Name < leases[j].Name })
+	return leases, nil
+}
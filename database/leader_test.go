@@ -0,0 +1,101 @@
+package database_test
+
+import (
+	"context"
+	"rockets-backend/database"
+	"rockets-backend/testutil"
+	"testing"
+	"time"
+)
+
+func TestMemoryLeaseStore(t *testing.T) {
+	testLeaseStoreContract(t, func(t *testing.T) database.LeaseStore {
+		return database.NewMemoryLeaseStore()
+	})
+}
+
+func TestPostgresLeaseStoreDB(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+
+	db := testutil.SetupTestDB(t)
+	t.Cleanup(func() { db.Close() })
+
+	testLeaseStoreContract(t, func(t *testing.T) database.LeaseStore {
+		testutil.CleanupTestDB(t, db)
+		return database.NewPostgresLeaseStore(db)
+	})
+}
+
+// testLeaseStoreContract checks the behaviour both lease stores must share
+func testLeaseStoreContract(t *testing.T, newStore func(t *testing.T) database.LeaseStore) {
+	ctx := context.Background()
+
+	t.Run("OneHolderAtATime", func(t *testing.T) {
+		store := newStore(t)
+
+		acquired, err := store.TryAcquire(ctx, "stale-detector", "a", time.Minute)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, true, acquired)
+
+		acquired, err = store.TryAcquire(ctx, "stale-detector", "b", time.Minute)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, false, acquired)
+
+		// Renewing keeps the original acquisition time
+		leases, err := store.Leases(ctx)
+		testutil.AssertNoError(t, err)
+		acquiredAt := leases[0].AcquiredAt
+		acquired, err = store.TryAcquire(ctx, "stale-detector", "a", time.Minute)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, true, acquired)
+
+		leases, err = store.Leases(ctx)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, len(leases))
+		testutil.AssertEqual(t, "a", leases[0].Holder)
+		testutil.AssertEqual(t, false, leases[0].Expired)
+		if !leases[0].AcquiredAt.Equal(acquiredAt) {
+			t.Errorf("renewal moved acquiredAt from %v to %v", acquiredAt, leases[0].AcquiredAt)
+		}
+
+		// Other names are independent
+		acquired, err = store.TryAcquire(ctx, "retention", "b", time.Minute)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, true, acquired)
+	})
+
+	t.Run("ExpiredLeaseIsTakenOver", func(t *testing.T) {
+		store := newStore(t)
+
+		acquired, err := store.TryAcquire(ctx, "stale-detector", "a", 20*time.Millisecond)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, true, acquired)
+		time.Sleep(50 * time.Millisecond)
+
+		leases, err := store.Leases(ctx)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, true, leases[0].Expired)
+
+		acquired, err = store.TryAcquire(ctx, "stale-detector", "b", time.Minute)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, true, acquired)
+	})
+
+	t.Run("ReleaseHandsOver", func(t *testing.T) {
+		store := newStore(t)
+
+		_, err := store.TryAcquire(ctx, "stale-detector", "a", time.Minute)
+		testutil.AssertNoError(t, err)
+
+		// Only the holder can release
+		testutil.AssertNoError(t, store.Release(ctx, "stale-detector", "b"))
+		acquired, err := store.TryAcquire(ctx, "stale-detector", "b", time.Minute)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, false, acquired)
+
+		testutil.AssertNoError(t, store.Release(ctx, "stale-detector", "a"))
+		acquired, err = store.TryAcquire(ctx, "stale-detector", "b", time.Minute)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, true, acquired)
+	})
+}
@@ -0,0 +1,61 @@
+package database_test
+
+import (
+	"context"
+	"rockets-backend/database"
+	"rockets-backend/testutil"
+	"testing"
+)
+
+func TestMigratorUpDownStatusDB(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	migrator, err := database.NewMigrator(db)
+	testutil.AssertNoError(t, err)
+
+	// SetupTestDB has already applied everything
+	applied, err := migrator.Up(ctx)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 0, len(applied))
+
+	statuses, err := migrator.Status(ctx)
+	testutil.AssertNoError(t, err)
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			t.Errorf("migration %d should be applied", status.Version)
+		}
+	}
+
+	// Roll everything back, then forward again, leaving the schema in place
+	// for the other database tests
+	rolledBack, err := migrator.Down(ctx, len(statuses))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(statuses), len(rolledBack))
+	testutil.AssertEqual(t, statuses[len(statuses)-1].Version, rolledBack[0].Version)
+
+	statuses, err = migrator.Status(ctx)
+	testutil.AssertNoError(t, err)
+	for _, status := range statuses {
+		if status.AppliedAt != nil {
+			t.Errorf("migration %d should be pending after rollback", status.Version)
+		}
+	}
+
+	// Without the migrations table everything is pending, and checking does not create it
+	_, err = db.ExecContext(ctx, "DROP TABLE schema_migrations")
+	testutil.AssertNoError(t, err)
+	pending, err := migrator.Pending(ctx)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(statuses), len(pending))
+	var exists bool
+	testutil.AssertNoError(t, db.QueryRowContext(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists))
+	testutil.AssertEqual(t, false, exists)
+
+	applied, err = migrator.Up(ctx)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(statuses), len(applied))
+}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestEmbeddedMigrationsAreSequential(t *testing.T) {
+	migrations, err := LoadMigrations(migrationFiles)
+	if err != nil {
+		t.Fatalf("LoadMigrations() error = %v", err)
+	}
+
+	if len(migrations) == 0 {
+		t.Fatal("expected embedded migrations")
+	}
+
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			t.Errorf("migration %d_%s: expected version %d", migration.Version, migration.Name, i+1)
+		}
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("migration %d_%s: up and down must not be empty", migration.Version, migration.Name)
+		}
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		want    []int
+		wantErr string
+	}{
+		{
+			name: "ordered by version",
+			files: fstest.MapFS{
+				"migrations/0010_later.up.sql":   {Data: []byte("SELECT 10")},
+				"migrations/0010_later.down.sql": {Data: []byte("SELECT -10")},
+				"migrations/0002_first.up.sql":   {Data: []byte("SELECT 2")},
+				"migrations/0002_first.down.sql": {Data: []byte("SELECT -2")},
+			},
+			want: []int{2, 10},
+		},
+		{
+			name: "missing down",
+			files: fstest.MapFS{
+				"migrations/0001_init.up.sql": {Data: []byte("SELECT 1")},
+			},
+			wantErr: "must have both up and down files",
+		},
+		{
+			name: "invalid file name",
+			files: fstest.MapFS{
+				"migrations/init.sql": {Data: []byte("SELECT 1")},
+			},
+			wantErr: "invalid migration file name",
+		},
+		{
+			name: "conflicting names",
+			files: fstest.MapFS{
+				"migrations/0001_init.up.sql":    {Data: []byte("SELECT 1")},
+				"migrations/0001_other.down.sql": {Data: []byte("SELECT -1")},
+			},
+			wantErr: "conflicting names",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := LoadMigrations(tt.files)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadMigrations() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadMigrations() error = %v", err)
+			}
+
+			if len(migrations) != len(tt.want) {
+				t.Fatalf("expected %d migrations, got %d", len(tt.want), len(migrations))
+			}
+			for i, version := range tt.want {
+				if migrations[i].Version != version {
+					t.Errorf("migrations[%d].Version = %d, want %d", i, migrations[i].Version, version)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+ALTER TABLE rockets
+    DROP COLUMN IF EXISTS altitude,
+    DROP COLUMN IF EXISTS latitude,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS fuel_level;
@@ -0,0 +1,5 @@
+ALTER TABLE rockets
+    ADD COLUMN IF NOT EXISTS altitude INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION NULL,
+    ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION NULL,
+    ADD COLUMN IF NOT EXISTS fuel_level DOUBLE PRECISION NULL; -- percentage, 0-100
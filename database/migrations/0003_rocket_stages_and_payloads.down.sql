@@ -0,0 +1,4 @@
+ALTER TABLE rockets
+    DROP COLUMN IF EXISTS active_stage,
+    DROP COLUMN IF EXISTS stages,
+    DROP COLUMN IF EXISTS payloads;
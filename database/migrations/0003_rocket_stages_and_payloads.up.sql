@@ -0,0 +1,4 @@
+ALTER TABLE rockets
+    ADD COLUMN IF NOT EXISTS active_stage INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS stages JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS payloads JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,35 @@
+-- Alert rules evaluated against rocket state after each processed event
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    metric VARCHAR(50) NOT NULL, -- speed, speedDelta, altitude, fuelLevel
+    operator VARCHAR(2) NOT NULL, -- >, >=, <, <=
+    threshold DOUBLE PRECISION NOT NULL,
+    rocket_type VARCHAR(100) NULL, -- applies to all rocket types when NULL
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Fired alerts with a copy of the triggering event
+CREATE TABLE IF NOT EXISTS alerts (
+    id SERIAL PRIMARY KEY,
+    rule_id INTEGER NULL REFERENCES alert_rules(id) ON DELETE SET NULL,
+    rule_name VARCHAR(255) NOT NULL,
+    rocket_id UUID NOT NULL,
+    event_id INTEGER NOT NULL,
+    message_number INTEGER NOT NULL,
+    message_type VARCHAR(50) NOT NULL,
+    message_data JSONB NOT NULL,
+    metric VARCHAR(50) NOT NULL,
+    operator VARCHAR(2) NOT NULL,
+    threshold DOUBLE PRECISION NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- open, acknowledged, resolved
+    triggered_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    acknowledged_at TIMESTAMP NULL,
+    resolved_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_alerts_status ON alerts(status);
+CREATE INDEX IF NOT EXISTS idx_alerts_rocket_id ON alerts(rocket_id);
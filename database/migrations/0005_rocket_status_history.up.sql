@@ -0,0 +1,12 @@
+-- Rocket status transitions, in order
+CREATE TABLE IF NOT EXISTS rocket_status_history (
+    id SERIAL PRIMARY KEY,
+    rocket_id UUID NOT NULL,
+    from_status VARCHAR(50) NULL, -- NULL for the initial status
+    to_status VARCHAR(50) NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    message_number INTEGER NULL, -- NULL when not caused by a message
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_rocket_status_history_rocket_id ON rocket_status_history(rocket_id);
@@ -0,0 +1,10 @@
+ALTER TABLE rocket_events
+    DROP COLUMN IF EXISTS message_time;
+
+ALTER TABLE rockets
+    DROP COLUMN IF EXISTS peak_speed,
+    DROP COLUMN IF EXISTS time_at_max_speed,
+    DROP COLUMN IF EXISTS average_speed,
+    DROP COLUMN IF EXISTS speed_samples,
+    DROP COLUMN IF EXISTS acceleration,
+    DROP COLUMN IF EXISTS last_speed_at;
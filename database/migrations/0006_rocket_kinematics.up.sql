@@ -0,0 +1,11 @@
+ALTER TABLE rockets
+    ADD COLUMN IF NOT EXISTS peak_speed INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS time_at_max_speed TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS average_speed DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS speed_samples INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS acceleration DOUBLE PRECISION NULL,
+    ADD COLUMN IF NOT EXISTS last_speed_at TIMESTAMP NULL;
+
+-- Message time from the metadata, in UTC
+ALTER TABLE rocket_events
+    ADD COLUMN IF NOT EXISTS message_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
@@ -0,0 +1,3 @@
+ALTER TABLE rocket_events
+    DROP COLUMN IF EXISTS request_id,
+    DROP COLUMN IF EXISTS trace_parent;
@@ -0,0 +1,4 @@
+-- Request ID and W3C traceparent of the ingest request, continued by the event processor
+ALTER TABLE rocket_events
+    ADD COLUMN IF NOT EXISTS request_id TEXT NULL,
+    ADD COLUMN IF NOT EXISTS trace_parent VARCHAR(55) NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS source_channels;
+DROP TABLE IF EXISTS message_sources;
@@ -0,0 +1,18 @@
+-- Telemetry sources signing POST /messages bodies. The secret is the HMAC key, so it
+-- has to be stored as is.
+CREATE TABLE IF NOT EXISTS message_sources (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    secret VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP NULL
+);
+
+-- Channels bound to the source that first signed a message for them
+CREATE TABLE IF NOT EXISTS source_channels (
+    channel UUID PRIMARY KEY,
+    source_id INTEGER NOT NULL REFERENCES message_sources(id) ON DELETE CASCADE,
+    bound_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_source_channels_source_id ON source_channels(source_id);
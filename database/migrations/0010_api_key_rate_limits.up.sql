@@ -0,0 +1,3 @@
+-- Optional per key ingestion rate limit, NULL uses the configured default
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS rate_limit DOUBLE PRECISION NULL; -- messages per second
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS rate_burst INTEGER NULL;
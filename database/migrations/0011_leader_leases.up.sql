@@ -0,0 +1,9 @@
+-- Leases electing the replica that runs each singleton background job. Times are
+-- taken from the database clock, so replicas with skewed clocks agree on expiry.
+CREATE TABLE IF NOT EXISTS leader_leases (
+    name VARCHAR(100) PRIMARY KEY,
+    holder VARCHAR(255) NOT NULL,
+    acquired_at TIMESTAMPTZ NOT NULL,
+    renewed_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,21 @@
+-- Processed and failed events moved out of rocket_events by the retention job.
+-- Partitioned by the month of received_at, the job creates the partitions it needs,
+-- so whole months can later be detached or dropped cheaply.
+CREATE TABLE IF NOT EXISTS rocket_events_archive (
+    id INTEGER NOT NULL,
+    channel UUID NOT NULL,
+    message_number INTEGER NOT NULL,
+    message_type VARCHAR(50) NOT NULL,
+    message_data JSONB NOT NULL,
+    message_time TIMESTAMP NOT NULL,
+    received_at TIMESTAMP NOT NULL,
+    processed_at TIMESTAMP NULL,
+    status VARCHAR(20) NOT NULL,
+    error_message TEXT NULL,
+    request_id TEXT NULL,
+    trace_parent VARCHAR(55) NULL,
+    archived_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id, received_at)
+) PARTITION BY RANGE (received_at);
+
+CREATE INDEX IF NOT EXISTS idx_rocket_events_archive_channel ON rocket_events_archive(channel, message_number);
@@ -0,0 +1,10 @@
+-- Rocket state after every Nth message, replay starts from the latest one instead of
+-- folding the whole event log. checksum is the SHA-256 of the encoded state.
+CREATE TABLE IF NOT EXISTS rocket_snapshots (
+    rocket_id UUID NOT NULL,
+    message_number INTEGER NOT NULL,
+    state JSONB NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (rocket_id, message_number)
+);
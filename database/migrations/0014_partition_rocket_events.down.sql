@@ -0,0 +1,37 @@
+ALTER TABLE rocket_events RENAME TO rocket_events_partitioned;
+ALTER TABLE rocket_events_partitioned RENAME CONSTRAINT rocket_events_pkey TO rocket_events_partitioned_pkey;
+ALTER INDEX idx_rocket_events_status RENAME TO idx_rocket_events_partitioned_status;
+ALTER INDEX idx_rocket_events_channel RENAME TO idx_rocket_events_partitioned_channel;
+ALTER SEQUENCE rocket_events_id_seq OWNED BY NONE;
+
+CREATE TABLE rocket_events (
+    id INTEGER PRIMARY KEY DEFAULT nextval('rocket_events_id_seq'),
+    channel UUID NOT NULL,
+    message_number INTEGER NOT NULL,
+    message_type VARCHAR(50) NOT NULL,
+    message_data JSONB NOT NULL,
+    message_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMP NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, processing, processed, failed
+    error_message TEXT NULL,
+    request_id TEXT NULL,
+    trace_parent VARCHAR(55) NULL,
+    UNIQUE(channel, message_number)
+);
+
+INSERT INTO rocket_events (id, channel, message_number, message_type, message_data, message_time,
+                           received_at, processed_at, status, error_message, request_id, trace_parent)
+SELECT id, channel, message_number, message_type, message_data, message_time,
+       received_at, processed_at, status, error_message, request_id, trace_parent
+FROM rocket_events_partitioned;
+
+ALTER SEQUENCE rocket_events_id_seq AS INTEGER OWNED BY rocket_events.id;
+DROP TABLE rocket_events_partitioned;
+DROP TABLE IF EXISTS rocket_event_keys;
+
+CREATE INDEX IF NOT EXISTS idx_rocket_events_status ON rocket_events(status);
+CREATE INDEX IF NOT EXISTS idx_rocket_events_channel ON rocket_events(channel);
+CREATE INDEX IF NOT EXISTS idx_rocket_events_received_at ON rocket_events(received_at);
+
+ALTER TABLE rocket_events_archive ALTER COLUMN id TYPE INTEGER;
@@ -0,0 +1,70 @@
+-- rocket_events becomes range partitioned by received_at, one partition per day named
+-- rocket_events_YYYY_MM_DD. The partition maintenance job creates the days ahead and drops
+-- past days once retention has emptied them. Existing events are copied, which holds an
+-- exclusive lock on the table for the duration of the copy.
+--
+-- A unique constraint on a partitioned table must include received_at, so the
+-- UNIQUE(channel, message_number) deduplication moves to rocket_event_keys. The key also
+-- records the received_at of each event ID, lookups by ID use it to read one partition.
+ALTER TABLE rocket_events RENAME TO rocket_events_unpartitioned;
+ALTER TABLE rocket_events_unpartitioned RENAME CONSTRAINT rocket_events_pkey TO rocket_events_unpartitioned_pkey;
+ALTER SEQUENCE rocket_events_id_seq AS BIGINT OWNED BY NONE;
+
+CREATE TABLE rocket_events (
+    id BIGINT NOT NULL DEFAULT nextval('rocket_events_id_seq'),
+    channel UUID NOT NULL,
+    message_number INTEGER NOT NULL,
+    message_type VARCHAR(50) NOT NULL,
+    message_data JSONB NOT NULL,
+    message_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMP NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, processing, processed, failed
+    error_message TEXT NULL,
+    request_id TEXT NULL,
+    trace_parent VARCHAR(55) NULL,
+    PRIMARY KEY (id, received_at)
+) PARTITION BY RANGE (received_at);
+
+ALTER SEQUENCE rocket_events_id_seq OWNED BY rocket_events.id;
+
+-- A partition for every day with events, and for the coming week
+DO $$
+DECLARE
+    day DATE;
+BEGIN
+    FOR day IN
+        SELECT DISTINCT received_at::date FROM rocket_events_unpartitioned
+        UNION
+        SELECT generate_series(LOCALTIMESTAMP::date, LOCALTIMESTAMP::date + 7, interval '1 day')::date
+    LOOP
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF rocket_events FOR VALUES FROM (%L) TO (%L)',
+            'rocket_events_' || to_char(day, 'YYYY_MM_DD'), day, day + 1);
+    END LOOP;
+END $$;
+
+INSERT INTO rocket_events (id, channel, message_number, message_type, message_data, message_time,
+                           received_at, processed_at, status, error_message, request_id, trace_parent)
+SELECT id, channel, message_number, message_type, message_data, message_time,
+       received_at, processed_at, status, error_message, request_id, trace_parent
+FROM rocket_events_unpartitioned;
+
+CREATE TABLE IF NOT EXISTS rocket_event_keys (
+    channel UUID NOT NULL,
+    message_number INTEGER NOT NULL,
+    event_id BIGINT NOT NULL,
+    received_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (channel, message_number)
+);
+
+INSERT INTO rocket_event_keys (channel, message_number, event_id, received_at)
+SELECT channel, message_number, id, received_at FROM rocket_events_unpartitioned;
+
+DROP TABLE rocket_events_unpartitioned;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_rocket_event_keys_event_id ON rocket_event_keys(event_id);
+CREATE INDEX IF NOT EXISTS idx_rocket_events_status ON rocket_events(status, received_at, id);
+CREATE INDEX IF NOT EXISTS idx_rocket_events_channel ON rocket_events(channel, message_number);
+
+-- Archived events keep their IDs
+ALTER TABLE rocket_events_archive ALTER COLUMN id TYPE BIGINT;
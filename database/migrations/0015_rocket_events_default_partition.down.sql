@@ -0,0 +1,8 @@
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM rocket_events_default) THEN
+        RAISE EXCEPTION 'rocket_events_default holds events, move them to their day partitions first';
+    END IF;
+END $$;
+
+DROP TABLE IF EXISTS rocket_events_default;
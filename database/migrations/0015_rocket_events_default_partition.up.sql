@@ -0,0 +1,3 @@
+-- Events received on a day without a partition land in rocket_events_default instead of
+-- being rejected. Partition maintenance moves them to a partition created for their day.
+CREATE TABLE IF NOT EXISTS rocket_events_default PARTITION OF rocket_events DEFAULT;
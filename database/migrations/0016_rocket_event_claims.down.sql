@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_rocket_events_claimed_at;
+
+ALTER TABLE rocket_events
+    DROP COLUMN IF EXISTS claimed_at;
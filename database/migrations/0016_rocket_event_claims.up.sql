@@ -0,0 +1,8 @@
+-- When a processing event was claimed, a claim older than the worker claim timeout has
+-- expired and the event is claimed again. Events already processing start their timeout now.
+ALTER TABLE rocket_events
+    ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMP NULL;
+
+UPDATE rocket_events SET claimed_at = LOCALTIMESTAMP WHERE status = 'processing';
+
+CREATE INDEX IF NOT EXISTS idx_rocket_events_claimed_at ON rocket_events(claimed_at) WHERE status = 'processing';
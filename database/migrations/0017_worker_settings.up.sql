@@ -0,0 +1,10 @@
+-- Event processor settings changed through the admin API, shared by every instance. A
+-- single row, until it is written each instance runs with its configured settings.
+CREATE TABLE IF NOT EXISTS worker_settings (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    poll_interval_ms BIGINT NOT NULL,
+    batch_size INTEGER NOT NULL,
+    worker_count INTEGER NOT NULL,
+    paused BOOLEAN NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
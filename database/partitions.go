@@ -0,0 +1,253 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"rockets-backend/models"
+	"strings"
+	"time"
+)
+
+const (
+	// partitionTimeout bounds each partition maintenance step
+	partitionTimeout = 30 * time.Second
+
+	// partitionLockTimeout gives up on DDL that waits for a lock on rocket_events, so
+	// ingestion does not queue up behind it. The next run tries again.
+	partitionLockTimeout = "5s"
+
+	partitionPrefix = "rocket_events_"
+	partitionLayout = "2006_01_02"
+
+	// defaultPartition holds the events received on a day without a partition
+	defaultPartition = "rocket_events_default"
+)
+
+// EventPartitions maintains the daily partitions of rocket_events. Partitions are named
+// rocket_events_YYYY_MM_DD after the day of received_at they hold.
+type EventPartitions struct {
+	db *sql.DB
+}
+
+func NewEventPartitions(db *sql.DB) *EventPartitions {
+	return &EventPartitions{db: db}
+}
+
+// Ensure creates the partitions for today and the daysAhead following days that do not
+// exist yet, and returns their names. Days follow the database session time zone, like
+// received_at. Events in the default partition, received on a day that had no partition,
+// are moved to a partition created for their day.
+func (p *EventPartitions) Ensure(ctx context.Context, daysAhead int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, partitionTimeout)
+	defer cancel()
+
+	today, err := p.today(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := p.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exists := make(map[string]bool, len(existing))
+	for _, partition := range existing {
+		exists[partition.Name] = true
+	}
+	stranded, err := p.defaultDays(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var days []time.Time
+	for i := 0; i <= daysAhead; i++ {
+		days = append(days, today.AddDate(0, 0, i))
+	}
+	for _, day := range stranded {
+		if day.Before(today) || day.After(today.AddDate(0, 0, daysAhead)) {
+			days = append(days, day)
+		}
+	}
+	isStranded := make(map[string]bool, len(stranded))
+	for _, day := range stranded {
+		isStranded[day.Format(partitionLayout)] = true
+	}
+
+	var created []string
+	for _, day := range days {
+		name := partitionPrefix + day.Format(partitionLayout)
+		if exists[name] {
+			continue
+		}
+
+		// Identifiers cannot be parameters, the name and bounds only come from the date
+		from, to := day.Format("2006-01-02"), day.AddDate(0, 0, 1).Format("2006-01-02")
+		statements := []string{
+			fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s PARTITION OF rocket_events FOR VALUES FROM ('%s') TO ('%s')`,
+				name, from, to),
+		}
+		if isStranded[day.Format(partitionLayout)] {
+			// PostgreSQL refuses to create a partition for rows in the default partition, so
+			// the default partition is detached while they move
+			statements = []string{
+				`ALTER TABLE rocket_events DETACH PARTITION ` + defaultPartition,
+				statements[0],
+				fmt.Sprintf(`WITH moved AS (DELETE FROM %s WHERE received_at >= '%s' AND received_at < '%s' RETURNING *)
+					INSERT INTO %s SELECT * FROM moved`, defaultPartition, from, to, name),
+				`ALTER TABLE rocket_events ATTACH PARTITION ` + defaultPartition + ` DEFAULT`,
+			}
+		}
+		if err := p.withLockTimeout(ctx, func(tx *sql.Tx) error {
+			for _, statement := range statements {
+				if _, err := tx.ExecContext(ctx, statement); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return created, fmt.Errorf("failed to create partition %s: %w", name, err)
+		}
+		created = append(created, name)
+	}
+
+	return created, nil
+}
+
+// defaultDays returns the days of the events in the default partition, oldest first
+func (p *EventPartitions) defaultDays(ctx context.Context) ([]time.Time, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT DISTINCT to_char(received_at, 'YYYY-MM-DD') AS day FROM `+defaultPartition+` ORDER BY day`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check the default partition: %w", err)
+	}
+	defer rows.Close()
+
+	var days []time.Time
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return nil, fmt.Errorf("failed to check the default partition: %w", err)
+		}
+		day, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check the default partition: %w", err)
+		}
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to check the default partition: %w", err)
+	}
+
+	return days, nil
+}
+
+// DropEmpty drops the partitions of past days that hold no events, typically after
+// retention moved them to the archive. New events always go to today's partition, so
+// an empty past partition stays empty.
+func (p *EventPartitions) DropEmpty(ctx context.Context) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, partitionTimeout)
+	defer cancel()
+
+	today, err := p.today(ctx)
+	if err != nil {
+		return nil, err
+	}
+	partitions, err := p.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var dropped []string
+	for _, partition := range partitions {
+		if partition.To.IsZero() || partition.To.After(today) {
+			continue // not one of ours, or today and later
+		}
+
+		var empty bool
+		err := p.withLockTimeout(ctx, func(tx *sql.Tx) error {
+			// The lock keeps the partition empty between the check and the drop
+			if _, err := tx.ExecContext(ctx, `LOCK TABLE `+partition.Name+` IN ACCESS EXCLUSIVE MODE`); err != nil {
+				return err
+			}
+			if err := tx.QueryRowContext(ctx, `SELECT NOT EXISTS (SELECT 1 FROM `+partition.Name+`)`).Scan(&empty); err != nil {
+				return err
+			}
+			if !empty {
+				return nil
+			}
+			_, err := tx.ExecContext(ctx, `DROP TABLE `+partition.Name)
+			return err
+		})
+		if err != nil {
+			return dropped, fmt.Errorf("failed to drop partition %s: %w", partition.Name, err)
+		}
+		if empty {
+			dropped = append(dropped, partition.Name)
+		}
+	}
+
+	return dropped, nil
+}
+
+// List returns the partitions of rocket_events by name. From and To are zero for
+// partitions that do not follow the naming scheme.
+func (p *EventPartitions) List(ctx context.Context) ([]models.EventPartition, error) {
+	ctx, cancel := context.WithTimeout(ctx, partitionTimeout)
+	defer cancel()
+
+	query := `
+		SELECT c.relname, GREATEST(c.reltuples, 0)::bigint
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'rocket_events'::regclass
+		ORDER BY c.relname`
+
+	rows, err := p.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query partitions: %w", err)
+	}
+	defer rows.Close()
+
+	partitions := []models.EventPartition{}
+	for rows.Next() {
+		var partition models.EventPartition
+		if err := rows.Scan(&partition.Name, &partition.EstimatedRows); err != nil {
+			return nil, fmt.Errorf("failed to scan partition: %w", err)
+		}
+		if day, err := time.Parse(partitionLayout, strings.TrimPrefix(partition.Name, partitionPrefix)); err == nil {
+			partition.From = day
+			partition.To = day.AddDate(0, 0, 1)
+		}
+		partitions = append(partitions, partition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query partitions: %w", err)
+	}
+
+	return partitions, nil
+}
+
+// today returns the current day of the database session as midnight UTC
+func (p *EventPartitions) today(ctx context.Context) (time.Time, error) {
+	var today string
+	if err := p.db.QueryRowContext(ctx, `SELECT to_char(LOCALTIMESTAMP, 'YYYY-MM-DD')`).Scan(&today); err != nil {
+		return time.Time{}, fmt.Errorf("failed to get the database date: %w", err)
+	}
+	return time.Parse("2006-01-02", today)
+}
+
+func (p *EventPartitions) withLockTimeout(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SET LOCAL lock_timeout = '`+partitionLockTimeout+`'`); err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
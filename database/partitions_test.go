@@ -0,0 +1,97 @@
+package database_test
+
+import (
+	"context"
+	"rockets-backend/database"
+	"rockets-backend/models"
+	"rockets-backend/testutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestEventPartitionsDB(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+
+	ctx := context.Background()
+	db := testutil.SetupTestDB(t)
+	t.Cleanup(func() { db.Close() })
+	testutil.CleanupTestDB(t, db)
+
+	partitions := database.NewEventPartitions(db)
+
+	// The migration creates the coming week, so ensuring it again is a no-op
+	_, err := partitions.Ensure(ctx, 7)
+	testutil.AssertNoError(t, err)
+	created, err := partitions.Ensure(ctx, 7)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 0, len(created))
+
+	created, err = partitions.Ensure(ctx, 8)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, len(created))
+
+	list, err := partitions.List(ctx)
+	testutil.AssertNoError(t, err)
+	var last models.EventPartition
+	for _, partition := range list {
+		if partition.Name == created[0] {
+			last = partition
+		}
+	}
+	testutil.AssertEqual(t, 24*time.Hour, last.To.Sub(last.From))
+
+	// A past partition with events is kept, once emptied it is dropped
+	channel := uuid.New().String()
+	day := last.From.AddDate(0, 0, -30)
+	past := "rocket_events_" + day.Format("2006_01_02")
+	_, err = db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+past+` PARTITION OF rocket_events FOR VALUES FROM ('`+
+		day.Format("2006-01-02")+`') TO ('`+day.AddDate(0, 0, 1).Format("2006-01-02")+`')`)
+	testutil.AssertNoError(t, err)
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO rocket_events (channel, message_number, message_time, message_type, message_data, received_at)
+		VALUES ($2, 1, $1, 'RocketLaunched', '{}', $1)`, day.Add(time.Hour), channel)
+	testutil.AssertNoError(t, err)
+
+	dropped, err := partitions.DropEmpty(ctx)
+	testutil.AssertNoError(t, err)
+	for _, name := range dropped {
+		if name == past {
+			t.Fatalf("Expected %s to be kept while it holds events", past)
+		}
+	}
+
+	_, err = db.ExecContext(ctx, `DELETE FROM rocket_events WHERE channel = $1`, channel)
+	testutil.AssertNoError(t, err)
+	dropped, err = partitions.DropEmpty(ctx)
+	testutil.AssertNoError(t, err)
+	found := false
+	for _, name := range dropped {
+		found = found || name == past
+	}
+	testutil.AssertEqual(t, true, found)
+
+	// An event received on a day without a partition lands in the default partition, Ensure
+	// creates the partition of its day and moves it there
+	future := last.To.AddDate(0, 0, 30)
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO rocket_events (channel, message_number, message_time, message_type, message_data, received_at)
+		VALUES ($2, 1, $1, 'RocketLaunched', '{}', $1)`, future.Add(time.Hour), channel)
+	testutil.AssertNoError(t, err)
+	created, err = partitions.Ensure(ctx, 8)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, len(created))
+	testutil.AssertEqual(t, "rocket_events_"+future.Format("2006_01_02"), created[0])
+
+	var inDefault, inDay int
+	testutil.AssertNoError(t, db.QueryRowContext(ctx, `SELECT count(*) FROM rocket_events_default`).Scan(&inDefault))
+	testutil.AssertNoError(t, db.QueryRowContext(ctx, `SELECT count(*) FROM `+created[0]).Scan(&inDay))
+	testutil.AssertEqual(t, 0, inDefault)
+	testutil.AssertEqual(t, 1, inDay)
+
+	_, err = db.ExecContext(ctx, `DELETE FROM rocket_events WHERE channel = $1`, channel)
+	testutil.AssertNoError(t, err)
+	_, err = db.ExecContext(ctx, `DROP TABLE `+created[0])
+	testutil.AssertNoError(t, err)
+}
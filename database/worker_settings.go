@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"rockets-backend/models"
+	"sync"
+	"time"
+)
+
+// settingsTimeout bounds each settings query, they run on every poll
+const settingsTimeout = 5 * time.Second
+
+// WorkerSettingsStore keeps the event processor settings shared by every instance, so
+// a change through the admin API applies to all of them
+type WorkerSettingsStore interface {
+	// Get returns the stored settings, or nil if they were never changed
+	Get(ctx context.Context) (*models.WorkerSettings, error)
+	// Save replaces the stored settings
+	Save(ctx context.Context, settings models.WorkerSettings) error
+}
+
+// PostgresWorkerSettingsStore keeps the settings in the single row of the worker_settings table
+type PostgresWorkerSettingsStore struct {
+	db *sql.DB
+}
+
+func NewPostgresWorkerSettingsStore(db *sql.DB) *PostgresWorkerSettingsStore {
+	return &PostgresWorkerSettingsStore{db: db}
+}
+
+func (s *PostgresWorkerSettingsStore) Get(ctx context.Context) (*models.WorkerSettings, error) {
+	ctx, cancel := context.WithTimeout(ctx, settingsTimeout)
+	defer cancel()
+
+	query := `SELECT poll_interval_ms, batch_size, worker_count, paused FROM worker_settings`
+
+	var settings models.WorkerSettings
+	err := s.db.QueryRowContext(ctx, query).Scan(&settings.PollIntervalMs, &settings.BatchSize, &settings.WorkerCount,
+		&settings.Paused)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worker settings: %w", err)
+	}
+	return &settings, nil
+}
+
+func (s *PostgresWorkerSettingsStore) Save(ctx context.Context, settings models.WorkerSettings) error {
+	ctx, cancel := context.WithTimeout(ctx, settingsTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO worker_settings (id, poll_interval_ms, batch_size, worker_count, paused, updated_at)
+		VALUES (TRUE, $1, $2, $3, $4, now())
+		ON CONFLICT (id) DO UPDATE
+		SET poll_interval_ms = EXCLUDED.poll_interval_ms,
+		    batch_size = EXCLUDED.batch_size,
+		    worker_count = EXCLUDED.worker_count,
+		    paused = EXCLUDED.paused,
+		    updated_at = EXCLUDED.updated_at`
+
+	_, err := s.db.ExecContext(ctx, query, settings.PollIntervalMs, settings.BatchSize, settings.WorkerCount,
+		settings.Paused)
+	if err != nil {
+		return fmt.Errorf("failed to save worker settings: %w", err)
+	}
+	return nil
+}
+
+// MemoryWorkerSettingsStore keeps the settings in memory, for a single instance with in-memory storage
+type MemoryWorkerSettingsStore struct {
+	mu       sync.Mutex
+	settings *models.WorkerSettings
+}
+
+func NewMemoryWorkerSettingsStore() *MemoryWorkerSettingsStore {
+	return &MemoryWorkerSettingsStore{}
+}
+
+func (s *MemoryWorkerSettingsStore) Get(ctx context.Context) (*models.WorkerSettings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.settings == nil {
+		return nil, nil
+	}
+	settings := *s.settings
+	return &settings, nil
+}
+
+func (s *MemoryWorkerSettingsStore) Save(ctx context.Context, settings models.WorkerSettings) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.settings = &settings
+	return nil
+}
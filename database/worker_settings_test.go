@@ -0,0 +1,51 @@
+package database_test
+
+import (
+	"context"
+	"rockets-backend/database"
+	"rockets-backend/models"
+	"rockets-backend/testutil"
+	"testing"
+)
+
+func TestMemoryWorkerSettingsStore(t *testing.T) {
+	testWorkerSettingsStoreContract(t, database.NewMemoryWorkerSettingsStore())
+}
+
+func TestPostgresWorkerSettingsStoreDB(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+
+	db := testutil.SetupTestDB(t)
+	t.Cleanup(func() { db.Close() })
+	testutil.CleanupTestDB(t, db)
+
+	testWorkerSettingsStoreContract(t, database.NewPostgresWorkerSettingsStore(db))
+}
+
+// testWorkerSettingsStoreContract checks the behaviour both settings stores must share
+func testWorkerSettingsStoreContract(t *testing.T, store database.WorkerSettingsStore) {
+	ctx := context.Background()
+
+	// Nothing is stored until the settings are changed
+	settings, err := store.Get(ctx)
+	testutil.AssertNoError(t, err)
+	if settings != nil {
+		t.Fatalf("Expected no stored settings, got %+v", *settings)
+	}
+
+	paused := models.WorkerSettings{PollIntervalMs: 250, BatchSize: 50, WorkerCount: 4, Paused: true}
+	testutil.AssertNoError(t, store.Save(ctx, paused))
+	settings, err = store.Get(ctx)
+	testutil.AssertNoError(t, err)
+	if settings == nil {
+		t.Fatal("Expected the saved settings, got none")
+	}
+	testutil.AssertEqual(t, paused, *settings)
+
+	// Saving again replaces them
+	resumed := models.WorkerSettings{PollIntervalMs: 1000, BatchSize: 10, WorkerCount: 2}
+	testutil.AssertNoError(t, store.Save(ctx, resumed))
+	settings, err = store.Get(ctx)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, resumed, *settings)
+}
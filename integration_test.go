@@ -49,16 +49,16 @@ func TestRocketLifecycleIntegrationDB(t *testing.T) {
 	}
 
 	// Test event creation in database
-	err := repo.CreateRocketEvent(launchEvent)
+	err := repo.CreateRocketEvent(ctx, launchEvent)
 	testutil.AssertNoError(t, err)
 	testutil.AssertNotEqual(t, 0, launchEvent.ID) // Should have auto-generated ID
 
 	// Process the event
-	err = svc.ProcessEvent(ctx, launchEvent)
+	_, err = svc.ProcessEvent(ctx, launchEvent)
 	testutil.AssertNoError(t, err)
 
 	// Verify rocket was created in database
-	rocket, err := repo.GetRocket(rocketChannel)
+	rocket, err := repo.GetRocket(ctx, rocketChannel)
 	testutil.AssertNoError(t, err)
 	testutil.AssertNotNil(t, rocket)
 	testutil.AssertEqual(t, "Falcon-9", rocket.Type)
@@ -68,7 +68,7 @@ func TestRocketLifecycleIntegrationDB(t *testing.T) {
 	testutil.AssertEqual(t, 1, rocket.LastMessageNumber)
 
 	// Verify event status was updated in database
-	savedEvent, err := repo.GetRocketEvent(launchEvent.ID)
+	savedEvent, err := repo.GetRocketEvent(ctx, launchEvent.ID)
 	testutil.AssertNoError(t, err)
 	testutil.AssertEqual(t, models.EventStatusProcessed, savedEvent.Status)
 	testutil.AssertNotNil(t, savedEvent.ProcessedAt)
@@ -88,14 +88,14 @@ func TestRocketLifecycleIntegrationDB(t *testing.T) {
 		ReceivedAt:    time.Now(),
 	}
 
-	err = repo.CreateRocketEvent(speedEvent)
+	err = repo.CreateRocketEvent(ctx, speedEvent)
 	testutil.AssertNoError(t, err)
 
-	err = svc.ProcessEvent(ctx, speedEvent)
+	_, err = svc.ProcessEvent(ctx, speedEvent)
 	testutil.AssertNoError(t, err)
 
 	// Verify speed was updated in database
-	rocket, err = repo.GetRocket(rocketChannel)
+	rocket, err = repo.GetRocket(ctx, rocketChannel)
 	testutil.AssertNoError(t, err)
 	testutil.AssertEqual(t, 800, rocket.CurrentSpeed) // 500 + 300
 	testutil.AssertEqual(t, 2, rocket.LastMessageNumber)
@@ -115,14 +115,14 @@ func TestRocketLifecycleIntegrationDB(t *testing.T) {
 		ReceivedAt:    time.Now(),
 	}
 
-	err = repo.CreateRocketEvent(outOfOrderEvent)
+	err = repo.CreateRocketEvent(ctx, outOfOrderEvent)
 	testutil.AssertNoError(t, err)
 
-	err = svc.ProcessEvent(ctx, outOfOrderEvent)
+	_, err = svc.ProcessEvent(ctx, outOfOrderEvent)
 	testutil.AssertNoError(t, err)
 
 	// Verify rocket state unchanged in database
-	rocket, err = repo.GetRocket(rocketChannel)
+	rocket, err = repo.GetRocket(ctx, rocketChannel)
 	testutil.AssertNoError(t, err)
 	testutil.AssertEqual(t, 800, rocket.CurrentSpeed)    // Should remain unchanged
 	testutil.AssertEqual(t, 2, rocket.LastMessageNumber) // Should remain unchanged
@@ -142,14 +142,14 @@ func TestRocketLifecycleIntegrationDB(t *testing.T) {
 		ReceivedAt:    time.Now(),
 	}
 
-	err = repo.CreateRocketEvent(explosionEvent)
+	err = repo.CreateRocketEvent(ctx, explosionEvent)
 	testutil.AssertNoError(t, err)
 
-	err = svc.ProcessEvent(ctx, explosionEvent)
+	_, err = svc.ProcessEvent(ctx, explosionEvent)
 	testutil.AssertNoError(t, err)
 
 	// Verify explosion was recorded in database
-	rocket, err = repo.GetRocket(rocketChannel)
+	rocket, err = repo.GetRocket(ctx, rocketChannel)
 	testutil.AssertNoError(t, err)
 	testutil.AssertEqual(t, "exploded", rocket.Status)
 	testutil.AssertEqual(t, 0, rocket.CurrentSpeed) // Speed reset to 0
@@ -161,13 +161,13 @@ func TestRocketLifecycleIntegrationDB(t *testing.T) {
 	t.Log("Phase 5: Testing database query functionality")
 
 	// Test GetAllRockets
-	rockets, err := repo.GetAllRockets("")
+	rockets, err := repo.GetAllRockets(ctx, "", models.RocketFilter{})
 	testutil.AssertNoError(t, err)
 	testutil.AssertEqual(t, 1, len(rockets))
 	testutil.AssertEqual(t, rocketChannel, rockets[0].ID)
 
 	// Test GetPendingEvents (should have none pending)
-	pendingEvents, err := repo.GetPendingEvents(10)
+	pendingEvents, err := repo.GetPendingEvents(ctx, 10)
 	testutil.AssertNoError(t, err)
 	testutil.AssertEqual(t, 0, len(pendingEvents)) // All events should be processed
 
@@ -219,14 +219,14 @@ func TestMultipleRocketsIntegrationDB(t *testing.T) {
 			ReceivedAt:    time.Now(),
 		}
 
-		err := repo.CreateRocketEvent(event)
+		err := repo.CreateRocketEvent(ctx, event)
 		testutil.AssertNoError(t, err)
 
-		err = svc.ProcessEvent(ctx, event)
+		_, err = svc.ProcessEvent(ctx, event)
 		testutil.AssertNoError(t, err)
 
 		// Verify each rocket in database
-		savedRocket, err := repo.GetRocket(r.channel)
+		savedRocket, err := repo.GetRocket(ctx, r.channel)
 		testutil.AssertNoError(t, err)
 		testutil.AssertEqual(t, r.rType, savedRocket.Type)
 		testutil.AssertEqual(t, r.speed, savedRocket.CurrentSpeed)
@@ -235,12 +235,12 @@ func TestMultipleRocketsIntegrationDB(t *testing.T) {
 	}
 
 	// Verify all rockets exist in database
-	allRockets, err := repo.GetAllRockets("")
+	allRockets, err := repo.GetAllRockets(ctx, "", models.RocketFilter{})
 	testutil.AssertNoError(t, err)
 	testutil.AssertEqual(t, 3, len(allRockets))
 
 	// Test sorting
-	sortedRockets, err := repo.GetAllRockets("speed")
+	sortedRockets, err := repo.GetAllRockets(ctx, "speed", models.RocketFilter{})
 	testutil.AssertNoError(t, err)
 	testutil.AssertEqual(t, 3, len(sortedRockets))
 	// Should be sorted by speed: Falcon-9 (500), Falcon-Heavy (800), Starship (1200)
@@ -260,6 +260,7 @@ func TestDatabaseConstraintsDB(t *testing.T) {
 	defer testutil.CleanupTestDB(t, db)
 
 	repo := repository.NewPostgresRocketRepository(db)
+	ctx := context.Background()
 	channel := uuid.New().String()
 
 	// Test unique constraint on (channel, message_number)
@@ -282,11 +283,11 @@ func TestDatabaseConstraintsDB(t *testing.T) {
 	}
 
 	// First event should succeed
-	err := repo.CreateRocketEvent(event1)
+	err := repo.CreateRocketEvent(ctx, event1)
 	testutil.AssertNoError(t, err)
 
 	// Second event with same channel+message_number should succeed due to ON CONFLICT DO UPDATE
-	err = repo.CreateRocketEvent(event2)
+	err = repo.CreateRocketEvent(ctx, event2)
 	testutil.AssertNoError(t, err)
 
 	// Both events should have same ID due to upsert
@@ -294,3 +295,353 @@ func TestDatabaseConstraintsDB(t *testing.T) {
 
 	t.Log("Database constraints test completed successfully")
 }
+
+// TestTelemetryIntegrationDB tests altitude, position and fuel messages with filtering and sorting
+func TestTelemetryIntegrationDB(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	defer testutil.CleanupTestDB(t, db)
+
+	logger := log.NewNopLogger()
+	repo := repository.NewPostgresRocketRepository(db)
+	svc := service.NewService(logger, repo)
+
+	ctx := context.Background()
+	low := uuid.New().String()
+	high := uuid.New().String()
+
+	messages := []struct {
+		channel       string
+		messageNumber int
+		messageType   string
+		data          string
+	}{
+		{low, 1, "RocketLaunched", `{"type":"Falcon-9","launchSpeed":500,"mission":"ARTEMIS"}`},
+		{low, 2, "RocketAltitudeChanged", `{"altitude":1500}`},
+		{low, 3, "RocketFuelLevelReported", `{"fuelLevel":80.5}`},
+		{high, 1, "RocketLaunched", `{"type":"Starship","launchSpeed":800,"mission":"MARS"}`},
+		{high, 2, "RocketPositionReported", `{"latitude":28.5,"longitude":-80.6,"altitude":42000}`},
+		{high, 3, "RocketFuelLevelReported", `{"fuelLevel":20}`},
+	}
+
+	for _, m := range messages {
+		event := &models.RocketEvent{
+			Channel:       m.channel,
+			MessageNumber: m.messageNumber,
+			MessageType:   m.messageType,
+			MessageData:   []byte(m.data),
+			Status:        models.EventStatusPending,
+		}
+		testutil.AssertNoError(t, repo.CreateRocketEvent(ctx, event))
+		_, err := svc.ProcessEvent(ctx, event)
+		testutil.AssertNoError(t, err)
+	}
+
+	rocket, err := repo.GetRocket(ctx, high)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 42000, rocket.Altitude)
+	testutil.AssertNotNil(t, rocket.Latitude)
+	testutil.AssertEqual(t, 28.5, *rocket.Latitude)
+	testutil.AssertEqual(t, -80.6, *rocket.Longitude)
+	testutil.AssertEqual(t, 20.0, *rocket.FuelLevel)
+
+	// Invalid fuel level should fail the event and leave the rocket untouched
+	invalid := &models.RocketEvent{
+		Channel:       high,
+		MessageNumber: 4,
+		MessageType:   "RocketFuelLevelReported",
+		MessageData:   []byte(`{"fuelLevel":150}`),
+		Status:        models.EventStatusPending,
+	}
+	testutil.AssertNoError(t, repo.CreateRocketEvent(ctx, invalid))
+	if _, err := svc.ProcessEvent(ctx, invalid); err == nil {
+		t.Fatal("Expected invalid fuel level to be rejected")
+	}
+
+	sorted, err := repo.GetAllRockets(ctx, "altitude", models.RocketFilter{})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 2, len(sorted))
+	testutil.AssertEqual(t, low, sorted[0].ID)
+
+	minAltitude := 10000
+	filtered, err := repo.GetAllRockets(ctx, "", models.RocketFilter{MinAltitude: &minAltitude})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, len(filtered))
+	testutil.AssertEqual(t, high, filtered[0].ID)
+
+	minFuel := 50.0
+	filtered, err = repo.GetAllRockets(ctx, "", models.RocketFilter{MinFuelLevel: &minFuel})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, len(filtered))
+	testutil.AssertEqual(t, low, filtered[0].ID)
+}
+
+// TestStagingAndPayloadsIntegrationDB tests stage separation, payload deployment and mission completion
+func TestStagingAndPayloadsIntegrationDB(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	defer testutil.CleanupTestDB(t, db)
+
+	logger := log.NewNopLogger()
+	repo := repository.NewPostgresRocketRepository(db)
+	svc := service.NewService(logger, repo)
+
+	ctx := context.Background()
+	channel := uuid.New().String()
+
+	process := func(messageNumber int, messageType, data string) error {
+		event := &models.RocketEvent{
+			Channel:       channel,
+			MessageNumber: messageNumber,
+			MessageType:   messageType,
+			MessageData:   []byte(data),
+			Status:        models.EventStatusPending,
+		}
+		testutil.AssertNoError(t, repo.CreateRocketEvent(ctx, event))
+		_, err := svc.ProcessEvent(ctx, event)
+		return err
+	}
+
+	testutil.AssertNoError(t, process(1, "RocketLaunched",
+		`{"type":"Falcon-9","launchSpeed":500,"mission":"ARTEMIS","stages":2,"payloads":["SAT-1","SAT-2"]}`))
+
+	rocket, err := repo.GetRocket(ctx, channel)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, rocket.ActiveStage)
+	testutil.AssertEqual(t, 2, len(rocket.Stages))
+	testutil.AssertEqual(t, 2, len(rocket.Payloads))
+
+	// Only the active stage can separate
+	if err := process(2, "RocketStageSeparated", `{"stage":2}`); err == nil {
+		t.Fatal("Expected separation of an inactive stage to fail")
+	}
+
+	testutil.AssertNoError(t, process(3, "RocketStageSeparated", `{"stage":1}`))
+	testutil.AssertNoError(t, process(4, "RocketPayloadDeployed", `{"payload":"SAT-1"}`))
+
+	rocket, err = repo.GetRocket(ctx, channel)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 2, rocket.ActiveStage)
+	testutil.AssertNotNil(t, rocket.Stages[0].SeparatedAt)
+	testutil.AssertEqual(t, true, rocket.Payloads[0].Deployed)
+	testutil.AssertEqual(t, models.RocketStatusActive, rocket.Status)
+
+	testutil.AssertNoError(t, process(5, "RocketPayloadDeployed", `{"payload":"SAT-2"}`))
+
+	rocket, err = repo.GetRocket(ctx, channel)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, models.RocketStatusMissionComplete, rocket.Status)
+
+	// Unknown payloads are rejected
+	if err := process(6, "RocketPayloadDeployed", `{"payload":"SAT-3"}`); err == nil {
+		t.Fatal("Expected deployment of an undeclared payload to fail")
+	}
+}
+
+// TestAlertRulesIntegrationDB tests alert rule evaluation and the alert lifecycle
+func TestAlertRulesIntegrationDB(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	defer testutil.CleanupTestDB(t, db)
+
+	logger := log.NewNopLogger()
+	repo := repository.NewPostgresRocketRepository(db)
+	alertRepo := repository.NewPostgresAlertRepository(db)
+	svc := service.NewService(logger, repo, service.WithAlertRepository(alertRepo))
+
+	ctx := context.Background()
+	channel := uuid.New().String()
+
+	falcon := "Falcon-9"
+	_, err := svc.CreateAlertRule(ctx, models.AlertRule{
+		Name: "speed jump", Metric: models.AlertMetricSpeedDelta, Operator: ">", Threshold: 5000, Enabled: true,
+	})
+	testutil.AssertNoError(t, err)
+	_, err = svc.CreateAlertRule(ctx, models.AlertRule{
+		Name: "falcon overspeed", Metric: models.AlertMetricSpeed, Operator: ">", Threshold: 10000,
+		RocketType: &falcon, Enabled: true,
+	})
+	testutil.AssertNoError(t, err)
+
+	_, err = svc.CreateAlertRule(ctx, models.AlertRule{Name: "bad", Metric: "temperature", Operator: ">"})
+	if err == nil {
+		t.Fatal("Expected rule with unknown metric to be rejected")
+	}
+
+	messages := []struct {
+		messageType string
+		data        string
+	}{
+		{"RocketLaunched", `{"type":"Falcon-9","launchSpeed":500,"mission":"ARTEMIS"}`},
+		{"RocketSpeedIncreased", `{"by":6000}`}, // speed jump
+		{"RocketSpeedIncreased", `{"by":4000}`}, // crosses 10000
+		{"RocketSpeedIncreased", `{"by":1000}`}, // still above 10000, no new alert
+	}
+	for i, m := range messages {
+		event := &models.RocketEvent{
+			Channel:       channel,
+			MessageNumber: i + 1,
+			MessageType:   m.messageType,
+			MessageData:   []byte(m.data),
+			Status:        models.EventStatusPending,
+		}
+		testutil.AssertNoError(t, repo.CreateRocketEvent(ctx, event))
+		_, err := svc.ProcessEvent(ctx, event)
+		testutil.AssertNoError(t, err)
+	}
+
+	alerts, err := svc.GetAlerts(ctx, models.AlertFilter{RocketID: channel})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 2, len(alerts))
+
+	// Newest first
+	testutil.AssertEqual(t, "falcon overspeed", alerts[0].RuleName)
+	testutil.AssertEqual(t, 10500.0, alerts[0].Value)
+	testutil.AssertEqual(t, 3, alerts[0].MessageNumber)
+	testutil.AssertEqual(t, "speed jump", alerts[1].RuleName)
+	testutil.AssertEqual(t, 6000.0, alerts[1].Value)
+
+	acked, err := svc.AcknowledgeAlert(ctx, alerts[0].ID)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, models.AlertStatusAcknowledged, acked.Status)
+	testutil.AssertNotNil(t, acked.AcknowledgedAt)
+
+	resolved, err := svc.ResolveAlert(ctx, alerts[0].ID)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, models.AlertStatusResolved, resolved.Status)
+
+	if _, err := svc.AcknowledgeAlert(ctx, alerts[0].ID); err == nil {
+		t.Fatal("Expected acknowledging a resolved alert to fail")
+	}
+
+	open, err := svc.GetAlerts(ctx, models.AlertFilter{Status: models.AlertStatusOpen})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, len(open))
+}
+
+// TestStaleRocketDetectionIntegrationDB tests lost contact flagging, recovery and status history
+func TestStaleRocketDetectionIntegrationDB(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	defer testutil.CleanupTestDB(t, db)
+
+	logger := log.NewNopLogger()
+	repo := repository.NewPostgresRocketRepository(db)
+	svc := service.NewService(logger, repo)
+
+	ctx := context.Background()
+	channel := uuid.New().String()
+
+	process := func(messageNumber int, messageType, data string) {
+		event := &models.RocketEvent{
+			Channel:       channel,
+			MessageNumber: messageNumber,
+			MessageType:   messageType,
+			MessageData:   []byte(data),
+			Status:        models.EventStatusPending,
+		}
+		testutil.AssertNoError(t, repo.CreateRocketEvent(ctx, event))
+		_, err := svc.ProcessEvent(ctx, event)
+		testutil.AssertNoError(t, err)
+	}
+
+	process(1, "RocketLaunched", `{"type":"Falcon-9","launchSpeed":500,"mission":"ARTEMIS"}`)
+
+	// A generous threshold leaves the rocket alone
+	flagged, err := svc.DetectStaleRockets(ctx, time.Hour)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 0, flagged)
+
+	time.Sleep(10 * time.Millisecond)
+	flagged, err = svc.DetectStaleRockets(ctx, time.Millisecond)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, flagged)
+
+	lost, err := repo.GetAllRockets(ctx, "", models.RocketFilter{Status: models.RocketStatusLostContact})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, len(lost))
+
+	stats, err := svc.GetRocketStats(ctx)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, stats.Total)
+	testutil.AssertEqual(t, 1, stats.LostContact)
+
+	// Next message restores contact
+	process(2, "RocketSpeedIncreased", `{"by":100}`)
+
+	rocket, err := repo.GetRocket(ctx, channel)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, models.RocketStatusActive, rocket.Status)
+	testutil.AssertEqual(t, 600, rocket.CurrentSpeed)
+
+	history, err := svc.GetRocketHistory(ctx, channel)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 3, len(history))
+	testutil.AssertEqual(t, models.RocketStatusActive, history[0].ToStatus)
+	testutil.AssertEqual(t, models.RocketStatusLostContact, history[1].ToStatus)
+	testutil.AssertEqual(t, models.RocketStatusActive, history[2].ToStatus)
+	testutil.AssertEqual(t, "contact restored", history[2].Reason)
+}
+
+// TestKinematicsIntegrationDB tests peak speed, average speed and acceleration derived from message times
+func TestKinematicsIntegrationDB(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	defer testutil.CleanupTestDB(t, db)
+
+	logger := log.NewNopLogger()
+	repo := repository.NewPostgresRocketRepository(db)
+	svc := service.NewService(logger, repo)
+
+	ctx := context.Background()
+	channel := uuid.New().String()
+	launchedAt := time.Date(2022, 2, 2, 18, 39, 5, 0, time.UTC)
+
+	messages := []struct {
+		offset      time.Duration
+		messageType string
+		data        string
+	}{
+		{0, "RocketLaunched", `{"type":"Falcon-9","launchSpeed":500,"mission":"ARTEMIS"}`},
+		{10 * time.Second, "RocketSpeedIncreased", `{"by":1000}`}, // 1500, +100/s
+		{20 * time.Second, "RocketMissionChanged", `{"newMission":"MARS"}`},
+		{30 * time.Second, "RocketSpeedDecreased", `{"by":500}`}, // 1000, -25/s
+	}
+	for i, m := range messages {
+		event := &models.RocketEvent{
+			Channel:       channel,
+			MessageNumber: i + 1,
+			MessageType:   m.messageType,
+			MessageData:   []byte(m.data),
+			MessageTime:   launchedAt.Add(m.offset),
+			Status:        models.EventStatusPending,
+		}
+		testutil.AssertNoError(t, repo.CreateRocketEvent(ctx, event))
+		_, err := svc.ProcessEvent(ctx, event)
+		testutil.AssertNoError(t, err)
+	}
+
+	rocket, err := repo.GetRocket(ctx, channel)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, true, rocket.LaunchTime.Equal(launchedAt))
+	testutil.AssertEqual(t, 1500, rocket.PeakSpeed)
+	testutil.AssertNotNil(t, rocket.TimeAtMaxSpeed)
+	testutil.AssertEqual(t, true, rocket.TimeAtMaxSpeed.Equal(launchedAt.Add(10*time.Second)))
+	testutil.AssertEqual(t, 3, rocket.SpeedSamples)
+	testutil.AssertEqual(t, 1000.0, rocket.AverageSpeed)
+	testutil.AssertNotNil(t, rocket.Acceleration)
+	testutil.AssertEqual(t, -25.0, *rocket.Acceleration)
+
+	_, err = repo.GetAllRockets(ctx, "peakSpeed", models.RocketFilter{})
+	testutil.AssertNoError(t, err)
+}
@@ -2,53 +2,278 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"rockets-backend/config"
 	"rockets-backend/database"
-	"rockets-backend/pkg"
+	"rockets-backend/metrics"
+	"rockets-backend/models"
 	"rockets-backend/repository"
 	"rockets-backend/service"
+	"rockets-backend/tracing"
 	"rockets-backend/transport"
+	"rockets-backend/transport/consumer_transport"
+	"rockets-backend/transport/grpc_transport"
 	"rockets-backend/transport/http_transport"
+	"rockets-backend/transport/tcp_transport"
 	"rockets-backend/worker"
 	"syscall"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 )
 
 func main() {
-	httpAddr := pkg.GetEnv("HTTP_PORT", ":8088")
-	logLevel := pkg.GetEnv("LOG_LEVEL", "debug")
+	flags := flag.NewFlagSet("rockets-backend", flag.ExitOnError)
+	configFile := flags.String("config", os.Getenv("CONFIG_FILE"), "YAML or JSON config file, environment variables override it")
+	checkConfig := flags.Bool("check-config", false, "validate the configuration, print it with secrets redacted and exit")
+	_ = flags.Parse(os.Args[1:])
+	args := flags.Args()
 
-	logger := getLogger(logLevel)
+	cfg, err := config.Load(*configFile)
+	if *checkConfig {
+		os.Exit(runCheckConfig(cfg, err))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", err)
+		os.Exit(1)
+	}
+
+	logger := getLogger(cfg.Log.Level)
+	m := metrics.New()
+
+	if len(args) > 0 && args[0] == "migrate" {
+		os.Exit(runMigrateCommand(args[1:], cfg, logger))
+	}
+	if len(args) > 0 && args[0] == "apikey" {
+		os.Exit(runAPIKeyCommand(args[1:], cfg, logger))
+	}
 
-	// Initialize database connection
-	db, err := database.NewConnection()
+	tracingConfig := cfg.Tracing.Tracer()
+	spanExporter, err := tracing.NewExporter(tracingConfig)
 	if err != nil {
-		_ = level.Error(logger).Log("error", "failed to connect to database", "err", err)
+		_ = level.Error(logger).Log("error", "failed to create tracing exporter", "err", err)
+		os.Exit(1)
+	}
+	shutdownTracing := tracing.Setup(spanExporter, tracingConfig)
+
+	// Initialize repositories
+	var rocketRepository repository.RocketRepository
+	var alertRepository repository.AlertRepository
+	var authRepository repository.AuthRepository
+	var sourceRepository repository.SourceRepository
+	var leaseStore database.LeaseStore
+	var settingsStore database.WorkerSettingsStore
+	var partitions *database.EventPartitions
+	var serviceOptions []service.Option
+	switch cfg.Storage {
+	case "memory":
+		_ = level.Warn(logger).Log("msg", "using in-memory storage, data is lost on restart")
+		rocketRepository = repository.NewMemoryRocketRepository()
+		alertRepository = repository.NewMemoryAlertRepository()
+		authRepository = repository.NewMemoryAuthRepository()
+		sourceRepository = repository.NewMemorySourceRepository()
+		leaseStore = database.NewMemoryLeaseStore()
+		settingsStore = database.NewMemoryWorkerSettingsStore()
+	case "postgres":
+		db, err := database.NewConnection(context.Background(), cfg.Database.Connection(), logger)
+		if err != nil {
+			_ = level.Error(logger).Log("error", "failed to connect to database", "err", err)
+			os.Exit(1)
+		}
+		defer db.Close()
+
+		migrator, err := database.NewMigrator(db)
+		if err != nil {
+			_ = level.Error(logger).Log("error", "failed to load migrations", "err", err)
+			os.Exit(1)
+		}
+		if cfg.Database.MigrateOnStart {
+			if err := migrateUp(migrator, logger); err != nil {
+				_ = level.Error(logger).Log("error", "failed to run database migrations", "err", err)
+				os.Exit(1)
+			}
+		}
+
+		rocketRepository = repository.NewPostgresRocketRepository(db)
+		alertRepository = repository.NewPostgresAlertRepository(db)
+		authRepository = repository.NewPostgresAuthRepository(db)
+		sourceRepository = repository.NewPostgresSourceRepository(db)
+		leaseStore = database.NewPostgresLeaseStore(db)
+		settingsStore = database.NewPostgresWorkerSettingsStore(db)
+		partitions = database.NewEventPartitions(db)
+
+		serviceOptions = append(serviceOptions,
+			service.WithPoolStats(db.Stats),
+			service.WithReadinessCheck("database", db.PingContext),
+			service.WithReadinessCheck("migrations", migrationsCurrentCheck(migrator)),
+		)
+		m.MustRegister(collectors.NewDBStatsCollector(db, "rockets"))
+	default:
+		_ = level.Error(logger).Log("error", "unknown storage", "storage", cfg.Storage)
 		os.Exit(1)
 	}
-	defer db.Close()
 
-	// Initialize repository and service
-	rocketRepository := repository.NewPostgresRocketRepository(db)
-	svc := service.NewService(logger, rocketRepository)
+	// Initialize service, the workers are started after it but checked by its readiness probe
+	var eventProcessor *worker.EventProcessor
+	var staleDetector, retentionJob, reclaimJob, partitionJob *worker.Singleton
+	var consumer *consumer_transport.Consumer
+	instance := cfg.Leader.Instance()
+	serviceOptions = append(serviceOptions,
+		service.WithAlertRepository(alertRepository),
+		service.WithAuthRepository(authRepository),
+		service.WithMessageSources(sourceRepository, time.Duration(cfg.Signatures.MaxSkew), cfg.Signatures.BindChannels),
+		service.WithReadinessCheck("eventProcessor", workerRunningCheck("event processor", func() bool {
+			return eventProcessor != nil && eventProcessor.IsRunning()
+		})),
+		// Only the leader runs the detector, every instance takes part in the election
+		service.WithReadinessCheck("staleDetector", workerRunningCheck("stale rocket detector election", func() bool {
+			return staleDetector != nil && staleDetector.IsRunning()
+		})),
+		service.WithReadinessCheck("retention", workerRunningCheck("event retention election", func() bool {
+			return retentionJob != nil && retentionJob.IsRunning()
+		})),
+		service.WithReadinessCheck("reclaim", workerRunningCheck("event reclaim election", func() bool {
+			return reclaimJob != nil && reclaimJob.IsRunning()
+		})),
+		service.WithWorkerControl(func() service.WorkerController {
+			if eventProcessor == nil {
+				return nil
+			}
+			return eventProcessor
+		}),
+		service.WithLeaderLeases(instance, leaseStore.Leases),
+		service.WithRetention(cfg.Retention.Policy()),
+		service.WithSnapshots(cfg.Snapshots.Every),
+		service.WithQueueBacklogThresholds(cfg.Readiness.MaxPendingEvents, time.Duration(cfg.Readiness.MaxPendingAge)),
+		service.WithIngestLimits(cfg.Ingest.Limits()),
+	)
+	if partitions != nil {
+		serviceOptions = append(serviceOptions,
+			service.WithEventPartitions(partitions, cfg.Partitions.DaysAhead),
+			service.WithReadinessCheck("partitions", workerRunningCheck("partition maintenance election", func() bool {
+				return partitionJob != nil && partitionJob.IsRunning()
+			})),
+		)
+	}
+	if cfg.Consumer.File != "" {
+		serviceOptions = append(serviceOptions,
+			service.WithReadinessCheck("consumer", workerRunningCheck("consumer", func() bool {
+				return consumer != nil && consumer.IsRunning()
+			})),
+		)
+	}
+	m.MustRegister(metrics.NewEventQueueCollector(rocketRepository.GetEventQueueStats))
+
+	var svc service.Service
+	svc = service.NewService(logger, rocketRepository, serviceOptions...)
+	svc = service.InstrumentingMiddleware(m.EventsIngested, m.EventsRateLimited, m.EventsArchived)(svc)
+	svc = service.TracingMiddleware()(svc)
 	endpoints := transport.MakeEndpoints(svc)
-	h := http_transport.NewHttpService(endpoints)
+
+	httpOptions := []http_transport.Option{
+		http_transport.WithSignatureVerifier(svc.VerifyMessageSignature, cfg.Signatures.Required),
+	}
+	if cfg.Auth.Enabled {
+		httpOptions = append(httpOptions, http_transport.WithAuthenticator(svc.AuthenticateAPIKey))
+
+		// Keys cannot be created with the apikey command for in-memory storage
+		if cfg.Storage == "memory" {
+			created, err := svc.CreateAPIKey(context.Background(), "bootstrap", models.RoleAdmin)
+			if err != nil {
+				_ = level.Error(logger).Log("error", "failed to create bootstrap api key", "err", err)
+				os.Exit(1)
+			}
+			_ = level.Warn(logger).Log("msg", "created bootstrap admin api key", "key", created.Key)
+		}
+	} else {
+		_ = level.Warn(logger).Log("msg", "API key authentication is disabled, all endpoints are open")
+	}
+	h := http_transport.NewHttpService(endpoints, m, logger, httpOptions...)
 	server := &http.Server{
-		Addr:    httpAddr,
+		Addr:    cfg.HTTP.Addr,
 		Handler: h,
 	}
 
-	_ = level.Info(logger).Log("msg", "rockets backend starting", "addr", httpAddr)
+	var grpcServer *grpc_transport.Server
+	if cfg.GRPC.Addr != "" {
+		grpcOptions := []grpc_transport.Option{
+			grpc_transport.WithSignaturesRequired(cfg.Signatures.Required),
+			grpc_transport.WithWatchInterval(time.Duration(cfg.GRPC.WatchInterval)),
+		}
+		if cfg.Auth.Enabled {
+			grpcOptions = append(grpcOptions, grpc_transport.WithAuthenticator(svc.AuthenticateAPIKey))
+		}
+		grpcServer = grpc_transport.NewGRPCServer(endpoints, logger, grpcOptions...)
+	}
+	var tcpServer *tcp_transport.Server
+	if cfg.TCP.Addr != "" {
+		tcpOptions := []tcp_transport.Option{
+			tcp_transport.WithSignaturesRequired(cfg.Signatures.Required),
+			tcp_transport.WithLimits(cfg.TCP.MaxConnections, time.Duration(cfg.TCP.IdleTimeout), cfg.TCP.MaxFrameBytes),
+		}
+		if cfg.Auth.Enabled {
+			tcpOptions = append(tcpOptions, tcp_transport.WithAuthenticator(svc.AuthenticateAPIKey))
+		}
+		tcpServer = tcp_transport.NewServer(endpoints.ProcessMessage, m, logger, tcpOptions...)
+	}
+
+	_ = level.Info(logger).Log("msg", "rockets backend starting", "addr", cfg.HTTP.Addr,
+		"config", *configFile, "instance", instance)
 	// Initialize background workers and server
-	eventProcessor := initializeWorkers(svc, rocketRepository, logger)
+	eventProcessor, staleDetector, retentionJob, reclaimJob = initializeWorkers(svc, rocketRepository, leaseStore,
+		settingsStore, instance, logger, m, cfg)
+	if partitions != nil {
+		partitionJob = startPartitionJob(svc, leaseStore, instance, logger, cfg)
+	}
+	if cfg.Consumer.File != "" {
+		consumer = startConsumer(endpoints, m, logger, cfg)
+	}
 	startServer(server, logger)
+	if grpcServer != nil {
+		startGRPCServer(grpcServer, cfg.GRPC.Addr, logger)
+	}
+	if tcpServer != nil {
+		startTCPServer(tcpServer, cfg.TCP.Addr, logger)
+	}
+
+	gracefulShutdown(drainable{
+		server:         server,
+		grpcServer:     grpcServer,
+		tcpServer:      tcpServer,
+		consumer:       consumer,
+		eventProcessor: eventProcessor,
+		singletons:     []*worker.Singleton{staleDetector, retentionJob, reclaimJob, partitionJob},
+	}, logger, time.Duration(cfg.Shutdown.Timeout))
+
+	// Flush the spans of the last requests and events
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		_ = level.Error(logger).Log("Error", "failed to flush traces", "err", err)
+	}
+}
+
+// runCheckConfig prints the effective configuration with secrets redacted, or what is wrong with it
+func runCheckConfig(cfg config.Config, loadErr error) int {
+	if loadErr != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", loadErr)
+		return 1
+	}
 
-	gracefulShutdown(server, logger, eventProcessor)
+	out, err := cfg.Redacted().YAML()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Print(out)
+	return 0
 }
 
 func getLogger(logLevel string) log.Logger {
@@ -72,6 +297,30 @@ func getLogger(logLevel string) log.Logger {
 	return logger
 }
 
+// workerRunningCheck fails readiness while a background worker is not running
+func workerRunningCheck(name string, running func() bool) service.ReadinessCheck {
+	return func(ctx context.Context) error {
+		if !running() {
+			return fmt.Errorf("%s is not running", name)
+		}
+		return nil
+	}
+}
+
+// migrationsCurrentCheck fails readiness while the database schema is behind the binary
+func migrationsCurrentCheck(migrator *database.Migrator) service.ReadinessCheck {
+	return func(ctx context.Context) error {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("%d pending migrations, first is %04d_%s", len(pending), pending[0].Version, pending[0].Name)
+		}
+		return nil
+	}
+}
+
 func startServer(server *http.Server, logger log.Logger) {
 	go func() {
 		_ = level.Info(logger).Log("Transport", "HTTP", "Addr", server.Addr)
@@ -81,9 +330,42 @@ func startServer(server *http.Server, logger log.Logger) {
 	}()
 }
 
-func initializeWorkers(svc service.Service, repo repository.RocketRepository, logger log.Logger) *worker.EventProcessor {
-	workerConfig := worker.DefaultConfig()
-	eventProcessor := worker.NewEventProcessor(svc, repo, logger, workerConfig)
+// startGRPCServer serves the gRPC API on addr, exiting when the address cannot be listened on
+func startGRPCServer(server *grpc_transport.Server, addr string, logger log.Logger) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		_ = level.Error(logger).Log("Error", "grpc server failed to start", "err", err)
+		os.Exit(1)
+	}
+	go func() {
+		_ = level.Info(logger).Log("Transport", "gRPC", "Addr", addr)
+		if err := server.Serve(listener); err != nil {
+			_ = level.Error(logger).Log("Error", "grpc server failed", "err", err)
+		}
+	}()
+}
+
+// startTCPServer accepts ingestion connections on addr, exiting when the address cannot be listened on
+func startTCPServer(server *tcp_transport.Server, addr string, logger log.Logger) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		_ = level.Error(logger).Log("Error", "tcp server failed to start", "err", err)
+		os.Exit(1)
+	}
+	go func() {
+		_ = level.Info(logger).Log("Transport", "TCP", "Addr", addr)
+		if err := server.Serve(listener); err != nil && err != tcp_transport.ErrServerClosed {
+			_ = level.Error(logger).Log("Error", "tcp server failed", "err", err)
+		}
+	}()
+}
+
+// initializeWorkers starts the event processor on every instance, and the singleton jobs
+// on the instance elected for each of them
+func initializeWorkers(svc service.Service, repo repository.RocketRepository, leases database.LeaseStore,
+	settings database.WorkerSettingsStore, instance string, logger log.Logger, m *metrics.Metrics,
+	cfg config.Config) (*worker.EventProcessor, *worker.Singleton, *worker.Singleton, *worker.Singleton) {
+	eventProcessor := worker.NewEventProcessor(svc, repo, settings, logger, m, cfg.Worker.Processor())
 
 	// Start background worker
 	ctx := context.Background()
@@ -93,32 +375,133 @@ func initializeWorkers(svc service.Service, repo repository.RocketRepository, lo
 		os.Exit(1)
 	}
 
-	return eventProcessor
+	staleDetector := worker.NewSingleton("stale-detector",
+		worker.NewStaleDetectorJob(svc, logger, cfg.Stale.Detector()),
+		leases, instance, time.Duration(cfg.Leader.LeaseTTL), logger)
+	if err := staleDetector.Start(ctx); err != nil {
+		_ = level.Error(logger).Log("error", "failed to start stale rocket detector", "err", err)
+		os.Exit(1)
+	}
+
+	retentionJob := worker.NewSingleton("retention",
+		worker.NewRetentionJob(svc, logger, time.Duration(cfg.Retention.Interval)),
+		leases, instance, time.Duration(cfg.Leader.LeaseTTL), logger)
+	if err := retentionJob.Start(ctx); err != nil {
+		_ = level.Error(logger).Log("error", "failed to start event retention", "err", err)
+		os.Exit(1)
+	}
+
+	reclaimJob := worker.NewSingleton("reclaim",
+		worker.NewReclaimJob(repo, logger, time.Duration(cfg.Worker.ClaimTimeout)),
+		leases, instance, time.Duration(cfg.Leader.LeaseTTL), logger)
+	if err := reclaimJob.Start(ctx); err != nil {
+		_ = level.Error(logger).Log("error", "failed to start event reclaim", "err", err)
+		os.Exit(1)
+	}
+
+	return eventProcessor, staleDetector, retentionJob, reclaimJob
 }
 
-func gracefulShutdown(server *http.Server, logger log.Logger, eventProcessor *worker.EventProcessor) {
+// startPartitionJob starts the rocket_events partition maintenance on the elected instance,
+// only PostgreSQL storage is partitioned
+func startPartitionJob(svc service.Service, leases database.LeaseStore, instance string, logger log.Logger,
+	cfg config.Config) *worker.Singleton {
+	partitionJob := worker.NewSingleton("partitions",
+		worker.NewPartitionJob(svc, logger, time.Duration(cfg.Partitions.CheckInterval)),
+		leases, instance, time.Duration(cfg.Leader.LeaseTTL), logger)
+	if err := partitionJob.Start(context.Background()); err != nil {
+		_ = level.Error(logger).Log("error", "failed to start partition maintenance", "err", err)
+		os.Exit(1)
+	}
+	return partitionJob
+}
+
+// startConsumer starts ingesting the messages appended to the consumer file
+func startConsumer(endpoints transport.Endpoints, m *metrics.Metrics, logger log.Logger,
+	cfg config.Config) *consumer_transport.Consumer {
+	source, err := consumer_transport.NewFileSource(cfg.Consumer.File, cfg.Consumer.Offsets(),
+		time.Duration(cfg.Consumer.PollInterval))
+	if err != nil {
+		_ = level.Error(logger).Log("error", "failed to open consumer file", "err", err)
+		os.Exit(1)
+	}
+	consumer := consumer_transport.NewConsumer(source, endpoints.ProcessMessage, m, logger)
+	if err := consumer.Start(context.Background()); err != nil {
+		_ = level.Error(logger).Log("error", "failed to start consumer", "err", err)
+		os.Exit(1)
+	}
+	return consumer
+}
+
+// drainable holds what gracefulShutdown stops, the optional transports and jobs may be nil
+type drainable struct {
+	server         *http.Server
+	grpcServer     *grpc_transport.Server
+	tcpServer      *tcp_transport.Server
+	consumer       *consumer_transport.Consumer
+	eventProcessor *worker.EventProcessor
+	singletons     []*worker.Singleton
+}
+
+// gracefulShutdown waits for a signal, then stops accepting requests and drains the
+// servers and the consumer before the workers, so no message is ingested once they are
+// gone. Workers finish only the event they are processing and release the rest of their
+// claimed batch. Everything shares one deadline, what is left when it passes is logged.
+func gracefulShutdown(d drainable, logger log.Logger, timeout time.Duration) {
 	// Wait for interrupt signal to gracefully shutdown the server
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, syscall.SIGINT, syscall.SIGTERM)
 	<-c
 
-	_ = level.Info(logger).Log("Message", "shutting down server gracefully")
-
-	// Stopping the background worker first
-	if err := eventProcessor.Stop(); err != nil {
-		_ = level.Error(logger).Log("Error", "failed to stop event processor", "err", err)
-	} else {
-		_ = level.Info(logger).Log("Message", "event processor stopped gracefully")
-	}
+	_ = level.Info(logger).Log("Message", "shutting down server gracefully", "timeout", timeout)
 
 	// Create a deadline for shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	// graceful shutdown of HTTP server
-	if err := server.Shutdown(ctx); err != nil {
-		_ = level.Error(logger).Log("Error", "server forced to shutdown", "err", err)
+	// Stop accepting connections and wait for in-flight requests
+	if err := d.server.Shutdown(ctx); err != nil {
+		_ = level.Error(logger).Log("Error", "server forced to shutdown, in-flight requests were abandoned", "err", err)
+		_ = d.server.Close()
 	} else {
 		_ = level.Info(logger).Log("Message", "server exited gracefully")
 	}
+	if d.grpcServer != nil {
+		if err := d.grpcServer.Shutdown(ctx); err != nil {
+			_ = level.Error(logger).Log("Error", "grpc server forced to shutdown, in-flight calls were abandoned", "err", err)
+		} else {
+			_ = level.Info(logger).Log("Message", "grpc server exited gracefully")
+		}
+	}
+	if d.tcpServer != nil {
+		if err := d.tcpServer.Shutdown(ctx); err != nil {
+			_ = level.Error(logger).Log("Error", "tcp server forced to shutdown, open connections were closed", "err", err)
+		} else {
+			_ = level.Info(logger).Log("Message", "tcp server exited gracefully")
+		}
+	}
+
+	// Likewise stop ingesting from the consumer, what it has not stored is read again on start
+	if d.consumer != nil {
+		if err := d.consumer.Stop(); err != nil {
+			_ = level.Error(logger).Log("Error", "failed to stop consumer", "err", err)
+		}
+	}
+
+	// Then the background workers, handing the singleton jobs over to another instance
+	for _, singleton := range d.singletons {
+		if singleton == nil {
+			continue
+		}
+		if err := singleton.Stop(); err != nil {
+			_ = level.Error(logger).Log("Error", "failed to stop singleton job", "err", err)
+		}
+	}
+	report, err := d.eventProcessor.Shutdown(ctx)
+	if err != nil || len(report.Abandoned) > 0 {
+		_ = level.Error(logger).Log("Error", "event processor did not drain", "released", report.Released,
+			"abandoned", len(report.Abandoned), "err", err)
+	} else {
+		_ = level.Info(logger).Log("Message", "event processor stopped gracefully", "released", report.Released)
+	}
 }
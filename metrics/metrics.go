@@ -0,0 +1,171 @@
+package metrics
+
+import (
+	"net/http"
+
+	kitmetrics "github.com/go-kit/kit/metrics"
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "rockets"
+
+// Metrics holds the service metrics and the registry they are exposed from.
+// Each Metrics has its own registry, so tests can create as many as they need.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	// HTTP requests, labelled by method, route template and status code
+	HTTPRequests kitmetrics.Counter
+	// HTTP request latency, labelled by method and route template
+	HTTPRequestDuration kitmetrics.Histogram
+
+	// Ingested events, labelled by message type
+	EventsIngested kitmetrics.Counter
+	// Messages rejected by ingestion rate limits, labelled by scope
+	EventsRateLimited kitmetrics.Counter
+	// Events handled by the event processor, labelled by outcome
+	EventsProcessed kitmetrics.Counter
+	// Event processing latency, labelled by outcome
+	EventProcessingDuration kitmetrics.Histogram
+	// Events moved to the archive by retention, labelled by status
+	EventsArchived kitmetrics.Counter
+	// Records read by the ingestion consumers, labelled by source and result
+	ConsumerRecords kitmetrics.Counter
+	// Open TCP ingestion connections, and the messages they sent labelled by result
+	TCPConnections kitmetrics.Gauge
+	TCPMessages    kitmetrics.Counter
+
+	// Event processor settings, which can change at runtime
+	WorkerCount        kitmetrics.Gauge
+	WorkerBatchSize    kitmetrics.Gauge
+	WorkerPollInterval kitmetrics.Gauge
+	WorkerPaused       kitmetrics.Gauge
+	// Running worker goroutines, above WorkerCount while removed workers finish
+	WorkersActive kitmetrics.Gauge
+}
+
+// New creates the metrics on a fresh registry that also exports Go runtime and process metrics
+func New() *Metrics {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	httpRequests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of HTTP requests handled.",
+	}, []string{"method", "route", "code"})
+	httpRequestDuration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+	eventsIngested := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "events",
+		Name:      "ingested_total",
+		Help:      "Number of ingested events.",
+	}, []string{"message_type"})
+	eventsRateLimited := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "events",
+		Name:      "rate_limited_total",
+		Help:      "Number of messages rejected by ingestion rate limits or queue backpressure.",
+	}, []string{"scope"})
+	eventsProcessed := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "events",
+		Name:      "processed_total",
+		Help:      "Number of events handled by the event processor.",
+	}, []string{"outcome"})
+	eventProcessingDuration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "events",
+		Name:      "processing_duration_seconds",
+		Help:      "Event processing latency in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"outcome"})
+
+	eventsArchived := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "events",
+		Name:      "archived_total",
+		Help:      "Number of events moved to the archive by retention.",
+	}, []string{"status"})
+
+	consumerRecords := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "consumer",
+		Name:      "records_total",
+		Help:      "Number of records read by the ingestion consumers.",
+	}, []string{"source", "result"})
+
+	tcpConnections := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "tcp",
+		Name:      "connections",
+		Help:      "Number of open TCP ingestion connections.",
+	}, nil)
+	tcpMessages := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "tcp",
+		Name:      "messages_total",
+		Help:      "Number of messages received over TCP ingestion connections.",
+	}, []string{"result"})
+
+	workerGauge := func(name, help string) *prometheus.GaugeVec {
+		return prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "worker",
+			Name:      name,
+			Help:      help,
+		}, nil)
+	}
+	workerCount := workerGauge("count", "Configured number of event processor workers.")
+	workerBatchSize := workerGauge("batch_size", "Configured number of events a worker fetches per poll.")
+	workerPollInterval := workerGauge("poll_interval_seconds", "Configured interval between event polls.")
+	workerPaused := workerGauge("paused", "1 while event processing is paused.")
+	workersActive := workerGauge("active", "Number of running event processor worker goroutines.")
+
+	registry.MustRegister(httpRequests, httpRequestDuration, eventsIngested, eventsRateLimited, eventsProcessed,
+		eventProcessingDuration, eventsArchived, consumerRecords, tcpConnections, tcpMessages, workerCount, workerBatchSize, workerPollInterval,
+		workerPaused, workersActive)
+
+	return &Metrics{
+		registry:                registry,
+		HTTPRequests:            kitprometheus.NewCounter(httpRequests),
+		HTTPRequestDuration:     kitprometheus.NewHistogram(httpRequestDuration),
+		EventsIngested:          kitprometheus.NewCounter(eventsIngested),
+		EventsRateLimited:       kitprometheus.NewCounter(eventsRateLimited),
+		EventsProcessed:         kitprometheus.NewCounter(eventsProcessed),
+		EventProcessingDuration: kitprometheus.NewHistogram(eventProcessingDuration),
+		EventsArchived:          kitprometheus.NewCounter(eventsArchived),
+		ConsumerRecords:         kitprometheus.NewCounter(consumerRecords),
+		TCPConnections:          kitprometheus.NewGauge(tcpConnections),
+		TCPMessages:             kitprometheus.NewCounter(tcpMessages),
+		WorkerCount:             kitprometheus.NewGauge(workerCount),
+		WorkerBatchSize:         kitprometheus.NewGauge(workerBatchSize),
+		WorkerPollInterval:      kitprometheus.NewGauge(workerPollInterval),
+		WorkerPaused:            kitprometheus.NewGauge(workerPaused),
+		WorkersActive:           kitprometheus.NewGauge(workersActive),
+	}
+}
+
+// MustRegister adds collectors, such as database or queue collectors, to the registry
+func (m *Metrics) MustRegister(cs ...prometheus.Collector) {
+	m.registry.MustRegister(cs...)
+}
+
+// Handler serves the registry in the Prometheus text exposition format.
+// A failing collector does not fail the whole scrape.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError})
+}
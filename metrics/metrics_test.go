@@ -0,0 +1,64 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"rockets-backend/metrics"
+	"rockets-backend/models"
+	"strings"
+	"testing"
+)
+
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(recorder.Body)
+	if err != nil {
+		t.Fatalf("failed to read metrics: %v", err)
+	}
+	return string(body)
+}
+
+func TestMetricsExposition(t *testing.T) {
+	m := metrics.New()
+	m.MustRegister(metrics.NewEventQueueCollector(func(ctx context.Context) (*models.EventQueueStats, error) {
+		return &models.EventQueueStats{Pending: 3, OldestPendingAgeSeconds: 12.5}, nil
+	}))
+
+	m.EventsIngested.With("message_type", "RocketLaunched").Add(1)
+	m.EventsRateLimited.With("scope", "channel").Add(1)
+	m.EventsProcessed.With("outcome", "ignored").Add(2)
+	m.HTTPRequests.With("method", "GET", "route", "/rockets/{id}", "code", "200").Add(1)
+
+	body := scrape(t, m)
+	for _, want := range []string{
+		`rockets_events_ingested_total{message_type="RocketLaunched"} 1`,
+		`rockets_events_rate_limited_total{scope="channel"} 1`,
+		`rockets_events_processed_total{outcome="ignored"} 2`,
+		`rockets_http_requests_total{code="200",method="GET",route="/rockets/{id}"} 1`,
+		`rockets_event_queue_pending 3`,
+		`rockets_event_queue_oldest_pending_age_seconds 12.5`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected metrics to contain %q", want)
+		}
+	}
+}
+
+func TestQueueCollectorErrorDoesNotFailScrape(t *testing.T) {
+	m := metrics.New()
+	m.MustRegister(metrics.NewEventQueueCollector(func(ctx context.Context) (*models.EventQueueStats, error) {
+		return nil, errors.New("database unavailable")
+	}))
+	m.EventsIngested.With("message_type", "RocketLaunched").Add(1)
+
+	body := scrape(t, m)
+	if !strings.Contains(body, `rockets_events_ingested_total{message_type="RocketLaunched"} 1`) {
+		t.Errorf("expected the remaining metrics to be exposed, got:\n%s", body)
+	}
+}
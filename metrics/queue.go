@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"context"
+	"rockets-backend/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// EventQueueStatsFunc returns the current event backlog, e.g. RocketRepository.GetEventQueueStats
+type EventQueueStatsFunc func(ctx context.Context) (*models.EventQueueStats, error)
+
+// eventQueueCollector queries the event backlog on every scrape, so the
+// values are never older than the scrape itself
+type eventQueueCollector struct {
+	stats     EventQueueStatsFunc
+	pending   *prometheus.Desc
+	oldestAge *prometheus.Desc
+}
+
+// NewEventQueueCollector reports the number of pending events and the age of the oldest one
+func NewEventQueueCollector(stats EventQueueStatsFunc) prometheus.Collector {
+	return &eventQueueCollector{
+		stats: stats,
+		pending: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "event_queue", "pending"),
+			"Number of events waiting to be processed.", nil, nil),
+		oldestAge: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "event_queue", "oldest_pending_age_seconds"),
+			"Age of the oldest event waiting to be processed, 0 when the queue is empty.", nil, nil),
+	}
+}
+
+func (c *eventQueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.pending
+	ch <- c.oldestAge
+}
+
+func (c *eventQueueCollector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := c.stats(context.Background())
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.pending, err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.pending, prometheus.GaugeValue, float64(stats.Pending))
+	ch <- prometheus.MustNewConstMetric(c.oldestAge, prometheus.GaugeValue, stats.OldestPendingAgeSeconds)
+}
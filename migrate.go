@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"rockets-backend/config"
+	"rockets-backend/database"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+const migrateUsage = "usage: rockets-backend migrate up|down [steps]|status"
+
+// runMigrateCommand handles the migrate subcommand and returns the exit code.
+func runMigrateCommand(args []string, cfg config.Config, logger log.Logger) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	db, err := database.NewConnection(context.Background(), cfg.Database.Connection(), logger)
+	if err != nil {
+		_ = level.Error(logger).Log("error", "failed to connect to database", "err", err)
+		return 1
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		_ = level.Error(logger).Log("error", "failed to load migrations", "err", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		err = migrateUp(migrator, logger)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				fmt.Fprintln(os.Stderr, migrateUsage)
+				return 2
+			}
+		}
+
+		var rolledBack []database.Migration
+		rolledBack, err = migrator.Down(ctx, steps)
+		for _, migration := range rolledBack {
+			_ = level.Info(logger).Log("msg", "rolled back migration", "version", migration.Version, "name", migration.Name)
+		}
+	case "status":
+		var statuses []database.MigrationStatus
+		statuses, err = migrator.Status(ctx)
+		if err == nil {
+			printMigrationStatus(statuses)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	if err != nil {
+		_ = level.Error(logger).Log("error", "migrate "+args[0]+" failed", "err", err)
+		return 1
+	}
+
+	return 0
+}
+
+// migrateUp applies all pending migrations, logging each one.
+func migrateUp(migrator *database.Migrator, logger log.Logger) error {
+	applied, err := migrator.Up(context.Background())
+	for _, migration := range applied {
+		_ = level.Info(logger).Log("msg", "applied migration", "version", migration.Version, "name", migration.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(applied) == 0 {
+		_ = level.Debug(logger).Log("msg", "database schema is up to date")
+	}
+
+	return nil
+}
+
+func printMigrationStatus(statuses []database.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+	w.Flush()
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// PoolStats reports the state of the database connection pool
+type PoolStats struct {
+	MaxOpenConnections int   `json:"maxOpenConnections"`
+	OpenConnections    int   `json:"openConnections"`
+	InUse              int   `json:"inUse"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"waitCount"`
+	WaitDurationMs     int64 `json:"waitDurationMs"`
+	MaxIdleClosed      int64 `json:"maxIdleClosed"`
+	MaxIdleTimeClosed  int64 `json:"maxIdleTimeClosed"`
+	MaxLifetimeClosed  int64 `json:"maxLifetimeClosed"`
+}
+
+// WorkerSettings are the event processor settings that can be changed while it runs
+type WorkerSettings struct {
+	PollIntervalMs int64 `json:"pollIntervalMs"`
+	BatchSize      int   `json:"batchSize"`
+	WorkerCount    int   `json:"workerCount"`
+	Paused         bool  `json:"paused"` // paused workers stop polling, ingestion continues
+}
+
+// WorkerStatus reports the event processor settings and what it is doing. Settings are shared
+// by every instance, the activity is that of the instance named by Instance.
+type WorkerStatus struct {
+	WorkerSettings
+	Instance      string `json:"instance"`
+	Running       bool   `json:"running"`
+	ActiveWorkers int    `json:"activeWorkers"` // above workerCount while removed workers finish their event
+	InFlight      int    `json:"inFlight"`      // events being processed right now
+}
+
+// LeaderLease tells which instance runs a singleton background job
+type LeaderLease struct {
+	Name       string    `json:"name" db:"name"`
+	Holder     string    `json:"holder" db:"holder"`
+	AcquiredAt time.Time `json:"acquiredAt" db:"acquired_at"`
+	RenewedAt  time.Time `json:"renewedAt" db:"renewed_at"`
+	ExpiresAt  time.Time `json:"expiresAt" db:"expires_at"`
+	Expired    bool      `json:"expired"` // the holder stopped renewing, the next instance to try takes over
+}
+
+// LeaderStatus lists the leases and the instance answering the request
+type LeaderStatus struct {
+	Instance string        `json:"instance"`
+	Leases   []LeaderLease `json:"leases"`
+}
+
+// EventPartition is a daily partition of rocket_events, holding the events received in [From, To)
+type EventPartition struct {
+	Name          string    `json:"name"`
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	EstimatedRows int64     `json:"estimatedRows"` // planner estimate, 0 until the partition is analyzed
+}
+
+// PartitionReport lists the partitions a maintenance run created and dropped
+type PartitionReport struct {
+	Created []string `json:"created"`
+	Dropped []string `json:"dropped"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AlertRule describes a condition on rocket state that raises an alert
+type AlertRule struct {
+	ID         int64     `json:"id" db:"id"`
+	Name       string    `json:"name" db:"name"`
+	Metric     string    `json:"metric" db:"metric"`
+	Operator   string    `json:"operator" db:"operator"`
+	Threshold  float64   `json:"threshold" db:"threshold"`
+	RocketType *string   `json:"rocketType,omitempty" db:"rocket_type"`
+	Enabled    bool      `json:"enabled" db:"enabled"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt  time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// Alert is a fired alert rule together with the event that triggered it
+type Alert struct {
+	ID             int64           `json:"id" db:"id"`
+	RuleID         *int64          `json:"ruleId,omitempty" db:"rule_id"`
+	RuleName       string          `json:"ruleName" db:"rule_name"`
+	RocketID       UUID            `json:"rocketId" db:"rocket_id"`
+	EventID        int64           `json:"eventId" db:"event_id"`
+	MessageNumber  int             `json:"messageNumber" db:"message_number"`
+	MessageType    string          `json:"messageType" db:"message_type"`
+	MessageData    json.RawMessage `json:"messageData" db:"message_data"`
+	Metric         string          `json:"metric" db:"metric"`
+	Operator       string          `json:"operator" db:"operator"`
+	Threshold      float64         `json:"threshold" db:"threshold"`
+	Value          float64         `json:"value" db:"value"`
+	Status         string          `json:"status" db:"status"`
+	TriggeredAt    time.Time       `json:"triggeredAt" db:"triggered_at"`
+	AcknowledgedAt *time.Time      `json:"acknowledgedAt,omitempty" db:"acknowledged_at"`
+	ResolvedAt     *time.Time      `json:"resolvedAt,omitempty" db:"resolved_at"`
+}
+
+// AlertFilter narrows down the alerts returned by a listing query.
+// Empty fields are not applied.
+type AlertFilter struct {
+	Status   string
+	RocketID UUID
+}
+
+// Alert rule metrics
+const (
+	AlertMetricSpeed      = "speed"      // current speed after the event
+	AlertMetricSpeedDelta = "speedDelta" // speed change caused by a single event
+	AlertMetricAltitude   = "altitude"
+	AlertMetricFuelLevel  = "fuelLevel"
+)
+
+// Alert status constants
+const (
+	AlertStatusOpen         = "open"
+	AlertStatusAcknowledged = "acknowledged"
+	AlertStatusResolved     = "resolved"
+)
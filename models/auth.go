@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// APIKey identifies a client. The key itself is only returned when it is created,
+// afterwards only its prefix is known.
+type APIKey struct {
+	ID         int64      `json:"id" db:"id"`
+	Name       string     `json:"name" db:"name"`
+	Role       string     `json:"role" db:"role"`
+	Prefix     string     `json:"prefix" db:"prefix"`
+	KeyHash    string     `json:"-" db:"key_hash"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty" db:"revoked_at"`
+	RateLimit  *RateLimit `json:"rateLimit,omitempty"`
+}
+
+// RateLimit allows Rate messages per second on average, with bursts of up to Burst messages
+type RateLimit struct {
+	Rate  float64 `json:"rate" db:"rate_limit"`
+	Burst int     `json:"burst" db:"rate_burst"`
+}
+
+// CreatedAPIKey is a newly created key together with its secret
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// API key roles
+const (
+	RoleIngest = "ingest" // POST /messages
+	RoleRead   = "read"   // queries
+	RoleAdmin  = "admin"  // everything, including key management
+)
+
+// RoleAllows tells whether a key with role may call an endpoint requiring required
+func RoleAllows(role, required string) bool {
+	return role == RoleAdmin || role == required
+}
+
+// ValidRole tells whether role is a known API key role
+func ValidRole(role string) bool {
+	return role == RoleIngest || role == RoleRead || role == RoleAdmin
+}
+
+// AuditRecord is an administrative change and the API key that made it
+type AuditRecord struct {
+	ID         int64     `json:"id" db:"id"`
+	APIKeyID   *int64    `json:"apiKeyId,omitempty" db:"api_key_id"`
+	APIKeyName *string   `json:"apiKeyName,omitempty" db:"api_key_name"`
+	Action     string    `json:"action" db:"action"`
+	Target     string    `json:"target" db:"target"`
+	RequestID  *string   `json:"requestId,omitempty" db:"request_id"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+}
+
+// MessageSource is a telemetry source that signs the messages it sends with a shared
+// secret. The secret is only returned when the source is created.
+type MessageSource struct {
+	ID        int64      `json:"id" db:"id"`
+	Name      string     `json:"name" db:"name"`
+	Secret    string     `json:"-" db:"secret"`
+	CreatedAt time.Time  `json:"createdAt" db:"created_at"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty" db:"revoked_at"`
+}
+
+// CreatedMessageSource is a newly created source together with its secret
+type CreatedMessageSource struct {
+	MessageSource
+	Secret string `json:"secret"`
+}
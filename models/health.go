@@ -0,0 +1,28 @@
+package models
+
+// Health statuses, for a single check and for the whole report
+const (
+	HealthStatusOK       = "ok"
+	HealthStatusDegraded = "degraded" // ready, but something needs attention
+	HealthStatusFailed   = "failed"
+)
+
+// HealthReport is the result of a liveness or readiness probe
+type HealthReport struct {
+	Status string        `json:"status"`
+	Checks []HealthCheck `json:"checks"`
+}
+
+// HealthCheck is the result of a single dependency check
+type HealthCheck struct {
+	Name       string                 `json:"name"`
+	Status     string                 `json:"status"`
+	Error      string                 `json:"error,omitempty"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+	DurationMs int64                  `json:"durationMs"`
+}
+
+// Ready reports whether no check failed
+func (r HealthReport) Ready() bool {
+	return r.Status != HealthStatusFailed
+}
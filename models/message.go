@@ -18,9 +18,11 @@ type MessageMetadata struct {
 
 // RocketLaunchedMessage payload
 type RocketLaunchedMessage struct {
-	Type        string `json:"type"`
-	LaunchSpeed int    `json:"launchSpeed"`
-	Mission     string `json:"mission"`
+	Type        string   `json:"type"`
+	LaunchSpeed int      `json:"launchSpeed"`
+	Mission     string   `json:"mission"`
+	Stages      int      `json:"stages,omitempty"`   // number of stages, optional
+	Payloads    []string `json:"payloads,omitempty"` // payload manifest, optional
 }
 
 // RocketSpeedIncreasedMessage payload
@@ -41,4 +43,31 @@ type RocketExplodedMessage struct {
 // RocketMissionChangedMessage payload
 type RocketMissionChangedMessage struct {
 	NewMission string `json:"newMission"`
-}
\ No newline at end of file
+}
+
+// RocketAltitudeChangedMessage payload
+type RocketAltitudeChangedMessage struct {
+	Altitude int `json:"altitude"`
+}
+
+// RocketPositionReportedMessage payload
+type RocketPositionReportedMessage struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Altitude  int     `json:"altitude"`
+}
+
+// RocketFuelLevelReportedMessage payload, fuel level is a percentage (0-100)
+type RocketFuelLevelReportedMessage struct {
+	FuelLevel float64 `json:"fuelLevel"`
+}
+
+// RocketStageSeparatedMessage payload
+type RocketStageSeparatedMessage struct {
+	Stage int `json:"stage"`
+}
+
+// RocketPayloadDeployedMessage payload
+type RocketPayloadDeployedMessage struct {
+	Payload string `json:"payload"`
+}
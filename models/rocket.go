@@ -4,18 +4,87 @@ import (
 	"time"
 )
 
-// Rocket represents the current state of a rocket
+// Rocket represents the current state of a rocket.
+// PeakSpeed, TimeAtMaxSpeed (message time the peak was first reached), AverageSpeed (mean over
+// SpeedSamples) and Acceleration (speed change per second between the last two samples) are
+// derived from speed changing messages.
 type Rocket struct {
-	ID                UUID      `json:"id" db:"id"`
-	Type              string    `json:"type" db:"type"`
-	CurrentSpeed      int       `json:"currentSpeed" db:"current_speed"`
-	Mission           string    `json:"mission" db:"mission"`
-	Status            string    `json:"status" db:"status"`
-	ExplosionReason   *string   `json:"explosionReason,omitempty" db:"explosion_reason"`
-	LaunchTime        time.Time `json:"launchTime" db:"launch_time"`
-	LastUpdated       time.Time `json:"lastUpdated" db:"last_updated"`
-	LastMessageNumber int       `json:"-" db:"last_message_number"`
+	ID                UUID            `json:"id" db:"id"`
+	Type              string          `json:"type" db:"type"`
+	CurrentSpeed      int             `json:"currentSpeed" db:"current_speed"`
+	Mission           string          `json:"mission" db:"mission"`
+	Status            string          `json:"status" db:"status"`
+	ExplosionReason   *string         `json:"explosionReason,omitempty" db:"explosion_reason"`
+	Altitude          int             `json:"altitude" db:"altitude"`
+	Latitude          *float64        `json:"latitude,omitempty" db:"latitude"`
+	Longitude         *float64        `json:"longitude,omitempty" db:"longitude"`
+	FuelLevel         *float64        `json:"fuelLevel,omitempty" db:"fuel_level"`
+	ActiveStage       int             `json:"activeStage" db:"active_stage"`
+	Stages            []RocketStage   `json:"stages,omitempty" db:"stages"`
+	Payloads          []RocketPayload `json:"payloads,omitempty" db:"payloads"`
+	PeakSpeed         int             `json:"peakSpeed" db:"peak_speed"`
+	TimeAtMaxSpeed    *time.Time      `json:"timeAtMaxSpeed,omitempty" db:"time_at_max_speed"`
+	AverageSpeed      float64         `json:"averageSpeed" db:"average_speed"`
+	SpeedSamples      int             `json:"speedSamples" db:"speed_samples"`
+	Acceleration      *float64        `json:"acceleration,omitempty" db:"acceleration"`
+	LastSpeedAt       *time.Time      `json:"-" db:"last_speed_at"`
+	LaunchTime        time.Time       `json:"launchTime" db:"launch_time"`
+	LastUpdated       time.Time       `json:"lastUpdated" db:"last_updated"`
+	LastMessageNumber int             `json:"-" db:"last_message_number"`
+}
+
+// Rocket status constants
+const (
+	RocketStatusActive          = "active"
+	RocketStatusExploded        = "exploded"
+	RocketStatusMissionComplete = "mission_complete"
+	RocketStatusLostContact     = "lost_contact"
+)
+
+// RocketStatusChange is an entry in a rocket's status history
+type RocketStatusChange struct {
+	ID            int64     `json:"id" db:"id"`
+	RocketID      UUID      `json:"rocketId" db:"rocket_id"`
+	FromStatus    *string   `json:"fromStatus,omitempty" db:"from_status"`
+	ToStatus      string    `json:"toStatus" db:"to_status"`
+	Reason        string    `json:"reason" db:"reason"`
+	MessageNumber *int      `json:"messageNumber,omitempty" db:"message_number"`
+	ChangedAt     time.Time `json:"changedAt" db:"changed_at"`
+}
+
+// RocketStats summarizes the tracked rockets
+type RocketStats struct {
+	Total       int            `json:"total"`
+	ByStatus    map[string]int `json:"byStatus"`
+	LostContact int            `json:"lostContact"`
+}
+
+// RocketStage tracks a single stage of a multi-stage rocket
+type RocketStage struct {
+	Number      int        `json:"number"`
+	SeparatedAt *time.Time `json:"separatedAt,omitempty"`
+}
+
+// RocketPayload tracks a payload declared on launch
+type RocketPayload struct {
+	Name       string     `json:"name"`
+	Deployed   bool       `json:"deployed"`
+	DeployedAt *time.Time `json:"deployedAt,omitempty"`
+}
+
+// RocketFilter narrows down the rockets returned by a listing query.
+// Nil fields are not applied.
+type RocketFilter struct {
+	Status       string
+	MinAltitude  *int
+	MaxAltitude  *int
+	MinLatitude  *float64
+	MaxLatitude  *float64
+	MinLongitude *float64
+	MaxLongitude *float64
+	MinFuelLevel *float64
+	MaxFuelLevel *float64
 }
 
 // UUID type alias for rocket IDs
-type UUID = string
\ No newline at end of file
+type UUID = string
@@ -7,15 +7,18 @@ import (
 
 // RocketEvent represents a raw rocket message stored for processing
 type RocketEvent struct {
-	ID            int64     `json:"id" db:"id"`
-	Channel       UUID      `json:"channel" db:"channel"`
-	MessageNumber int       `json:"message_number" db:"message_number"`
-	MessageType   string    `json:"message_type" db:"message_type"`
+	ID            int64           `json:"id" db:"id"`
+	Channel       UUID            `json:"channel" db:"channel"`
+	MessageNumber int             `json:"message_number" db:"message_number"`
+	MessageType   string          `json:"message_type" db:"message_type"`
 	MessageData   json.RawMessage `json:"message_data" db:"message_data"`
-	ReceivedAt    time.Time `json:"received_at" db:"received_at"`
-	ProcessedAt   *time.Time `json:"processed_at,omitempty" db:"processed_at"`
-	Status        string    `json:"status" db:"status"`
-	ErrorMessage  *string   `json:"error_message,omitempty" db:"error_message"`
+	MessageTime   time.Time       `json:"message_time" db:"message_time"`
+	ReceivedAt    time.Time       `json:"received_at" db:"received_at"`
+	ProcessedAt   *time.Time      `json:"processed_at,omitempty" db:"processed_at"`
+	Status        string          `json:"status" db:"status"`
+	ErrorMessage  *string         `json:"error_message,omitempty" db:"error_message"`
+	RequestID     *string         `json:"request_id,omitempty" db:"request_id"`     // request that ingested the event
+	TraceParent   *string         `json:"trace_parent,omitempty" db:"trace_parent"` // W3C traceparent of the ingest span
 }
 
 // EventStatus constants
@@ -26,3 +29,21 @@ const (
 	EventStatusFailed     = "failed"
 )
 
+// EventQueueStats describes the events waiting to be processed
+type EventQueueStats struct {
+	Pending                 int     `json:"pending"`
+	OldestPendingAgeSeconds float64 `json:"oldestPendingAgeSeconds"`
+}
+
+// RetentionReport tells how many events a retention run moved to the archive
+type RetentionReport struct {
+	Results    []RetentionResult `json:"results"`
+	DurationMs int64             `json:"durationMs"`
+}
+
+// RetentionResult covers the events of one status, those received before Cutoff are archived
+type RetentionResult struct {
+	Status   string    `json:"status"`
+	Cutoff   time.Time `json:"cutoff"`
+	Archived int       `json:"archived"`
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// RocketSnapshot is the state of a rocket right after the event with MessageNumber was applied.
+// State holds the encoded rocket, Checksum the SHA-256 of that encoding.
+type RocketSnapshot struct {
+	RocketID      UUID            `json:"rocketId" db:"rocket_id"`
+	MessageNumber int             `json:"messageNumber" db:"message_number"`
+	State         json.RawMessage `json:"state" db:"state"`
+	Checksum      string          `json:"checksum" db:"checksum"`
+	CreatedAt     time.Time       `json:"createdAt" db:"created_at"`
+}
+
+// Snapshot verification statuses
+const (
+	SnapshotStatusOK               = "ok"
+	SnapshotStatusChecksumMismatch = "checksum_mismatch" // the stored state was altered
+	SnapshotStatusStateMismatch    = "state_mismatch"    // replaying the event log gives another state
+)
+
+// RocketReplay is the state of a rocket rebuilt from its event log
+type RocketReplay struct {
+	Rocket                Rocket `json:"rocket"`
+	MessageNumber         int    `json:"messageNumber"`                   // last applied message
+	SnapshotMessageNumber *int   `json:"snapshotMessageNumber,omitempty"` // snapshot the replay started from
+	EventsApplied         int    `json:"eventsApplied"`
+}
+
+// SnapshotVerification reports whether a rocket's snapshots match its event log
+type SnapshotVerification struct {
+	RocketID UUID            `json:"rocketId"`
+	Valid    bool            `json:"valid"`
+	Results  []SnapshotCheck `json:"results"`
+}
+
+// SnapshotCheck is the verification result of one snapshot. Fields lists the state
+// fields that differ from the replayed state.
+type SnapshotCheck struct {
+	MessageNumber int       `json:"messageNumber"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Status        string    `json:"status"`
+	Fields        []string  `json:"fields,omitempty"`
+}
+
+// rocketState is the snapshot encoding of a rocket, including the fields the API hides
+type rocketState struct {
+	Rocket
+	LastSpeedAt       *time.Time `json:"lastSpeedAt,omitempty"`
+	LastMessageNumber int        `json:"lastMessageNumber"`
+}
+
+// EncodeRocketState encodes a rocket for a snapshot. Times are written in UTC so the
+// encoding, and with it the checksum, does not depend on where the values were read from.
+func EncodeRocketState(rocket Rocket) (json.RawMessage, error) {
+	rocket.TimeAtMaxSpeed = utcTime(rocket.TimeAtMaxSpeed)
+	rocket.LaunchTime = rocket.LaunchTime.UTC()
+	rocket.LastUpdated = rocket.LastUpdated.UTC()
+
+	stages := make([]RocketStage, len(rocket.Stages))
+	for i, stage := range rocket.Stages {
+		stage.SeparatedAt = utcTime(stage.SeparatedAt)
+		stages[i] = stage
+	}
+	payloads := make([]RocketPayload, len(rocket.Payloads))
+	for i, payload := range rocket.Payloads {
+		payload.DeployedAt = utcTime(payload.DeployedAt)
+		payloads[i] = payload
+	}
+	if rocket.Stages != nil {
+		rocket.Stages = stages
+	}
+	if rocket.Payloads != nil {
+		rocket.Payloads = payloads
+	}
+
+	data, err := json.Marshal(rocketState{
+		Rocket:            rocket,
+		LastSpeedAt:       utcTime(rocket.LastSpeedAt),
+		LastMessageNumber: rocket.LastMessageNumber,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode rocket state: %w", err)
+	}
+	return data, nil
+}
+
+// DecodeRocketState decodes a rocket encoded by EncodeRocketState
+func DecodeRocketState(data json.RawMessage) (*Rocket, error) {
+	var state rocketState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode rocket state: %w", err)
+	}
+	rocket := state.Rocket
+	rocket.LastSpeedAt = state.LastSpeedAt
+	rocket.LastMessageNumber = state.LastMessageNumber
+	return &rocket, nil
+}
+
+// RocketStateChecksum returns the hex SHA-256 of the rocket's snapshot encoding
+func RocketStateChecksum(rocket Rocket) (string, error) {
+	data, err := EncodeRocketState(rocket)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func utcTime(value *time.Time) *time.Time {
+	if value == nil {
+		return nil
+	}
+	utc := value.UTC()
+	return &utc
+}
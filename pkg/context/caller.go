@@ -0,0 +1,64 @@
+package context
+
+import (
+	"context"
+	"rockets-backend/models"
+)
+
+const CallerKey contextKey = "caller"
+
+// Caller identifies the API key a request was authenticated with
+type Caller struct {
+	KeyID   int64
+	KeyName string
+	Role    string
+
+	// RateLimit overrides the default ingestion rate limit for the key
+	RateLimit *models.RateLimit
+}
+
+// WithCaller adds the authenticated caller to the context
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, CallerKey, caller)
+}
+
+// GetCaller retrieves the authenticated caller from context, ok is false for
+// unauthenticated requests and background work
+func GetCaller(ctx context.Context) (caller Caller, ok bool) {
+	caller, ok = ctx.Value(CallerKey).(Caller)
+	return caller, ok
+}
+
+const SourceKey contextKey = "source"
+
+// Source identifies the message source whose signature a request carried
+type Source struct {
+	ID   int64
+	Name string
+}
+
+// WithSource adds the verified message source to the context
+func WithSource(ctx context.Context, source Source) context.Context {
+	return context.WithValue(ctx, SourceKey, source)
+}
+
+// GetSource retrieves the verified message source from context, ok is false for unsigned requests
+func GetSource(ctx context.Context) (source Source, ok bool) {
+	source, ok = ctx.Value(SourceKey).(Source)
+	return source, ok
+}
+
+const ClientAddrKey contextKey = "clientAddr"
+
+// WithClientAddr adds the network address of the client to the context
+func WithClientAddr(ctx context.Context, addr string) context.Context {
+	return context.WithValue(ctx, ClientAddrKey, addr)
+}
+
+// GetClientAddr retrieves the network address of the client from context
+func GetClientAddr(ctx context.Context) string {
+	if addr, ok := ctx.Value(ClientAddrKey).(string); ok {
+		return addr
+	}
+	return ""
+}
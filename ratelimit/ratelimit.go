@@ -0,0 +1,102 @@
+// Package ratelimit implements keyed token bucket rate limiting
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often buckets that have refilled completely are dropped
+const sweepInterval = time.Minute
+
+// Limit allows Rate events per second on average with bursts of up to Burst events.
+// A zero Rate disables the limit, a Burst below one allows one second worth of events.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Enabled tells whether the limit restricts anything
+func (l Limit) Enabled() bool {
+	return l.Rate > 0
+}
+
+func (l Limit) burst() float64 {
+	if l.Burst < 1 {
+		return math.Max(1, math.Ceil(l.Rate))
+	}
+	return float64(l.Burst)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Duration // time to refill from empty
+}
+
+// Limiter keeps one token bucket per key. Each call passes the limit for its key, so
+// keys can have different limits and a changed limit applies immediately.
+type Limiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewLimiter() *Limiter {
+	return &Limiter{buckets: make(map[string]*bucket)}
+}
+
+// Allow takes a token from the bucket of key at now. When the bucket is empty it
+// returns false and how long until the next token is available.
+func (l *Limiter) Allow(key string, limit Limit, now time.Time) (bool, time.Duration) {
+	if !limit.Enabled() {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	burst := limit.burst()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[key] = b
+	}
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed.Seconds()*limit.Rate)
+		b.last = now
+	}
+	b.tokens = math.Min(b.tokens, burst)
+	b.full = time.Duration(burst / limit.Rate * float64(time.Second))
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+}
+
+// sweep drops buckets that would be full again, they behave like new ones
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= b.full {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Len returns the number of tracked keys
+func (l *Limiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
@@ -0,0 +1,56 @@
+package ratelimit_test
+
+import (
+	"rockets-backend/ratelimit"
+	"rockets-backend/testutil"
+	"testing"
+	"time"
+)
+
+func TestLimiterBurstAndRefill(t *testing.T) {
+	limiter := ratelimit.NewLimiter()
+	limit := ratelimit.Limit{Rate: 2, Burst: 3}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		allowed, _ := limiter.Allow("client", limit, now)
+		testutil.AssertEqual(t, true, allowed)
+	}
+
+	allowed, retryAfter := limiter.Allow("client", limit, now)
+	testutil.AssertEqual(t, false, allowed)
+	testutil.AssertEqual(t, 500*time.Millisecond, retryAfter)
+
+	// Other keys have their own bucket
+	allowed, _ = limiter.Allow("other", limit, now)
+	testutil.AssertEqual(t, true, allowed)
+
+	now = now.Add(500 * time.Millisecond)
+	allowed, _ = limiter.Allow("client", limit, now)
+	testutil.AssertEqual(t, true, allowed)
+	allowed, _ = limiter.Allow("client", limit, now)
+	testutil.AssertEqual(t, false, allowed)
+}
+
+func TestLimiterDisabledLimit(t *testing.T) {
+	limiter := ratelimit.NewLimiter()
+	now := time.Now()
+
+	for i := 0; i < 100; i++ {
+		allowed, _ := limiter.Allow("client", ratelimit.Limit{}, now)
+		testutil.AssertEqual(t, true, allowed)
+	}
+	testutil.AssertEqual(t, 0, limiter.Len())
+}
+
+func TestLimiterSweepsIdleBuckets(t *testing.T) {
+	limiter := ratelimit.NewLimiter()
+	limit := ratelimit.Limit{Rate: 10, Burst: 10}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	limiter.Allow("idle", limit, now)
+	now = now.Add(2 * time.Minute)
+	limiter.Allow("active", limit, now)
+
+	testutil.AssertEqual(t, 1, limiter.Len())
+}
@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"rockets-backend/models"
+	"strings"
+)
+
+type AlertRepository interface {
+	// Alert rule operations
+	CreateAlertRule(ctx context.Context, rule *models.AlertRule) error
+	GetAlertRule(ctx context.Context, id int64) (*models.AlertRule, error)
+	GetAlertRules(ctx context.Context, enabledOnly bool) ([]models.AlertRule, error)
+	UpdateAlertRule(ctx context.Context, rule *models.AlertRule) error
+	DeleteAlertRule(ctx context.Context, id int64) (bool, error)
+
+	// Alert operations
+	CreateAlert(ctx context.Context, alert *models.Alert) error
+	GetAlert(ctx context.Context, id int64) (*models.Alert, error)
+	GetAlerts(ctx context.Context, filter models.AlertFilter) ([]models.Alert, error)
+	UpdateAlertStatus(ctx context.Context, id int64, status string) error
+}
+
+type PostgresAlertRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresAlertRepository(db *sql.DB) AlertRepository {
+	return &PostgresAlertRepository{db: db}
+}
+
+const alertRuleColumns = `id, name, metric, operator, threshold, rocket_type, enabled, created_at, updated_at`
+
+func scanAlertRule(row rowScanner, rule *models.AlertRule) error {
+	return row.Scan(
+		&rule.ID, &rule.Name, &rule.Metric, &rule.Operator, &rule.Threshold,
+		&rule.RocketType, &rule.Enabled, &rule.CreatedAt, &rule.UpdatedAt,
+	)
+}
+
+const alertColumns = `id, rule_id, rule_name, rocket_id, event_id, message_number, message_type,
+		       message_data, metric, operator, threshold, value, status,
+		       triggered_at, acknowledged_at, resolved_at`
+
+func scanAlert(row rowScanner, alert *models.Alert) error {
+	return row.Scan(
+		&alert.ID, &alert.RuleID, &alert.RuleName, &alert.RocketID, &alert.EventID,
+		&alert.MessageNumber, &alert.MessageType, &alert.MessageData, &alert.Metric,
+		&alert.Operator, &alert.Threshold, &alert.Value, &alert.Status,
+		&alert.TriggeredAt, &alert.AcknowledgedAt, &alert.ResolvedAt,
+	)
+}
+
+func (r *PostgresAlertRepository) CreateAlertRule(ctx context.Context, rule *models.AlertRule) error {
+	ctx, cancel := startQuery(ctx, "CreateAlertRule", writeTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO alert_rules (name, metric, operator, threshold, rocket_type, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, tagQuery(ctx, query),
+		rule.Name, rule.Metric, rule.Operator, rule.Threshold, rule.RocketType, rule.Enabled,
+	).Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create alert rule: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresAlertRepository) GetAlertRule(ctx context.Context, id int64) (*models.AlertRule, error) {
+	ctx, cancel := startQuery(ctx, "GetAlertRule", readTimeout)
+	defer cancel()
+
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules WHERE id = $1`
+
+	rule := &models.AlertRule{}
+	err := scanAlertRule(r.db.QueryRowContext(ctx, tagQuery(ctx, query), id), rule)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+func (r *PostgresAlertRepository) GetAlertRules(ctx context.Context, enabledOnly bool) ([]models.AlertRule, error) {
+	ctx, cancel := startQuery(ctx, "GetAlertRules", listTimeout)
+	defer cancel()
+
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules`
+	if enabledOnly {
+		query += ` WHERE enabled`
+	}
+	query += ` ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, tagQuery(ctx, query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []models.AlertRule
+	for rows.Next() {
+		rule := models.AlertRule{}
+		if err := scanAlertRule(rows, &rule); err != nil {
+			return nil, fmt.Errorf("failed to scan alert rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (r *PostgresAlertRepository) UpdateAlertRule(ctx context.Context, rule *models.AlertRule) error {
+	ctx, cancel := startQuery(ctx, "UpdateAlertRule", writeTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE alert_rules
+		SET name = $2, metric = $3, operator = $4, threshold = $5, rocket_type = $6,
+		    enabled = $7, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, tagQuery(ctx, query),
+		rule.ID, rule.Name, rule.Metric, rule.Operator, rule.Threshold, rule.RocketType, rule.Enabled,
+	).Scan(&rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update alert rule: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresAlertRepository) DeleteAlertRule(ctx context.Context, id int64) (bool, error) {
+	ctx, cancel := startQuery(ctx, "DeleteAlertRule", writeTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, tagQuery(ctx, `DELETE FROM alert_rules WHERE id = $1`), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *PostgresAlertRepository) CreateAlert(ctx context.Context, alert *models.Alert) error {
+	ctx, cancel := startQuery(ctx, "CreateAlert", writeTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO alerts (rule_id, rule_name, rocket_id, event_id, message_number, message_type,
+		                    message_data, metric, operator, threshold, value, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, triggered_at`
+
+	err := r.db.QueryRowContext(ctx, tagQuery(ctx, query),
+		alert.RuleID, alert.RuleName, alert.RocketID, alert.EventID, alert.MessageNumber,
+		alert.MessageType, alert.MessageData, alert.Metric, alert.Operator, alert.Threshold,
+		alert.Value, models.AlertStatusOpen,
+	).Scan(&alert.ID, &alert.TriggeredAt)
+	if err != nil {
+		return fmt.Errorf("failed to create alert: %w", err)
+	}
+
+	alert.Status = models.AlertStatusOpen
+	return nil
+}
+
+func (r *PostgresAlertRepository) GetAlert(ctx context.Context, id int64) (*models.Alert, error) {
+	ctx, cancel := startQuery(ctx, "GetAlert", readTimeout)
+	defer cancel()
+
+	query := `SELECT ` + alertColumns + ` FROM alerts WHERE id = $1`
+
+	alert := &models.Alert{}
+	err := scanAlert(r.db.QueryRowContext(ctx, tagQuery(ctx, query), id), alert)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert: %w", err)
+	}
+
+	return alert, nil
+}
+
+func (r *PostgresAlertRepository) GetAlerts(ctx context.Context, filter models.AlertFilter) ([]models.Alert, error) {
+	ctx, cancel := startQuery(ctx, "GetAlerts", listTimeout)
+	defer cancel()
+
+	var conditions []string
+	var args []interface{}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.RocketID != "" {
+		args = append(args, filter.RocketID)
+		conditions = append(conditions, fmt.Sprintf("rocket_id = $%d", len(args)))
+	}
+
+	query := `SELECT ` + alertColumns + ` FROM alerts`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY triggered_at DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, tagQuery(ctx, query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []models.Alert
+	for rows.Next() {
+		alert := models.Alert{}
+		if err := scanAlert(rows, &alert); err != nil {
+			return nil, fmt.Errorf("failed to scan alert: %w", err)
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (r *PostgresAlertRepository) UpdateAlertStatus(ctx context.Context, id int64, status string) error {
+	ctx, cancel := startQuery(ctx, "UpdateAlertStatus", writeTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE alerts
+		SET status = $2::varchar,
+		    acknowledged_at = CASE WHEN $2 = 'acknowledged' THEN CURRENT_TIMESTAMP ELSE acknowledged_at END,
+		    resolved_at = CASE WHEN $2 = 'resolved' THEN CURRENT_TIMESTAMP ELSE resolved_at END
+		WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, tagQuery(ctx, query), id, status)
+	if err != nil {
+		return fmt.Errorf("failed to update alert status: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package repository_test
+
+import (
+	"context"
+	"rockets-backend/models"
+	"rockets-backend/repository"
+	"rockets-backend/testutil"
+	"testing"
+	"time"
+)
+
+// testAuthRepositoryContract runs the behaviour every AuthRepository must share
+func testAuthRepositoryContract(t *testing.T, newRepo func(t *testing.T) repository.AuthRepository) {
+	ctx := context.Background()
+
+	t.Run("APIKeyLifecycle", func(t *testing.T) {
+		repo := newRepo(t)
+
+		key := &models.APIKey{Name: "telemetry", Role: models.RoleIngest, Prefix: "rk_abc123", KeyHash: "hash"}
+		testutil.AssertNoError(t, repo.CreateAPIKey(ctx, key))
+		testutil.AssertNotEqual(t, int64(0), key.ID)
+
+		duplicate := &models.APIKey{Name: "other", Role: models.RoleRead, Prefix: "rk_abc123", KeyHash: "hash"}
+		if err := repo.CreateAPIKey(ctx, duplicate); err == nil {
+			t.Fatal("Expected a duplicate prefix to fail")
+		}
+
+		found, err := repo.GetAPIKeyByPrefix(ctx, "rk_abc123")
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, key.ID, found.ID)
+		testutil.AssertEqual(t, "hash", found.KeyHash)
+		if found.LastUsedAt != nil || found.RevokedAt != nil {
+			t.Fatalf("Expected a new key to be unused and active, got %+v", found)
+		}
+
+		missing, err := repo.GetAPIKeyByPrefix(ctx, "rk_missing")
+		testutil.AssertNoError(t, err)
+		if missing != nil {
+			t.Fatalf("Expected nil key, got %+v", missing)
+		}
+
+		usedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		testutil.AssertNoError(t, repo.TouchAPIKey(ctx, key.ID, usedAt))
+
+		revoked, err := repo.RevokeAPIKey(ctx, key.ID)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, true, revoked)
+		revoked, err = repo.RevokeAPIKey(ctx, key.ID)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, false, revoked)
+
+		keys, err := repo.GetAPIKeys(ctx)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, len(keys))
+		testutil.AssertEqual(t, true, keys[0].LastUsedAt.Equal(usedAt))
+		if keys[0].RevokedAt == nil {
+			t.Fatal("Expected the key to be revoked")
+		}
+	})
+
+	t.Run("APIKeyRateLimit", func(t *testing.T) {
+		repo := newRepo(t)
+
+		key := &models.APIKey{Name: "telemetry", Role: models.RoleIngest, Prefix: "rk_rate", KeyHash: "hash"}
+		testutil.AssertNoError(t, repo.CreateAPIKey(ctx, key))
+
+		found, err := repo.GetAPIKeyByPrefix(ctx, "rk_rate")
+		testutil.AssertNoError(t, err)
+		if found.RateLimit != nil {
+			t.Fatalf("Expected a new key to use the default rate limit, got %+v", found.RateLimit)
+		}
+
+		updated, err := repo.SetAPIKeyRateLimit(ctx, key.ID, &models.RateLimit{Rate: 2.5, Burst: 10})
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, true, updated)
+
+		found, err = repo.GetAPIKeyByPrefix(ctx, "rk_rate")
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, models.RateLimit{Rate: 2.5, Burst: 10}, *found.RateLimit)
+
+		updated, err = repo.SetAPIKeyRateLimit(ctx, key.ID, nil)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, true, updated)
+
+		keys, err := repo.GetAPIKeys(ctx)
+		testutil.AssertNoError(t, err)
+		if keys[0].RateLimit != nil {
+			t.Fatalf("Expected the rate limit to be cleared, got %+v", keys[0].RateLimit)
+		}
+
+		updated, err = repo.SetAPIKeyRateLimit(ctx, 999, nil)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, false, updated)
+	})
+
+	t.Run("AuditLogNewestFirst", func(t *testing.T) {
+		repo := newRepo(t)
+
+		keyID := int64(7)
+		keyName := "ops"
+		for _, action := range []string{"apikey.create", "alert_rule.delete", "apikey.revoke"} {
+			record := &models.AuditRecord{APIKeyID: &keyID, APIKeyName: &keyName, Action: action, Target: "1"}
+			testutil.AssertNoError(t, repo.CreateAuditRecord(ctx, record))
+			testutil.AssertNotEqual(t, int64(0), record.ID)
+		}
+
+		records, err := repo.GetAuditRecords(ctx, 2)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 2, len(records))
+		testutil.AssertEqual(t, "apikey.revoke", records[0].Action)
+		testutil.AssertEqual(t, "alert_rule.delete", records[1].Action)
+		testutil.AssertEqual(t, "ops", *records[0].APIKeyName)
+	})
+}
@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"rockets-backend/models"
+	"time"
+)
+
+type AuthRepository interface {
+	// API key operations
+	CreateAPIKey(ctx context.Context, key *models.APIKey) error
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error)
+	GetAPIKeys(ctx context.Context) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int64) (bool, error)
+	TouchAPIKey(ctx context.Context, id int64, usedAt time.Time) error
+	SetAPIKeyRateLimit(ctx context.Context, id int64, limit *models.RateLimit) (bool, error)
+
+	// Audit log operations
+	CreateAuditRecord(ctx context.Context, record *models.AuditRecord) error
+	GetAuditRecords(ctx context.Context, limit int) ([]models.AuditRecord, error)
+}
+
+type PostgresAuthRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresAuthRepository(db *sql.DB) AuthRepository {
+	return &PostgresAuthRepository{db: db}
+}
+
+const apiKeyColumns = `id, name, role, prefix, key_hash, created_at, last_used_at, revoked_at, rate_limit, rate_burst`
+
+func scanAPIKey(row rowScanner, key *models.APIKey) error {
+	var rate sql.NullFloat64
+	var burst sql.NullInt64
+	err := row.Scan(
+		&key.ID, &key.Name, &key.Role, &key.Prefix, &key.KeyHash,
+		&key.CreatedAt, &key.LastUsedAt, &key.RevokedAt, &rate, &burst,
+	)
+	if err != nil {
+		return err
+	}
+
+	key.RateLimit = nil
+	if rate.Valid {
+		key.RateLimit = &models.RateLimit{Rate: rate.Float64, Burst: int(burst.Int64)}
+	}
+	return nil
+}
+
+const auditRecordColumns = `id, api_key_id, api_key_name, action, target, request_id, created_at`
+
+func scanAuditRecord(row rowScanner, record *models.AuditRecord) error {
+	return row.Scan(
+		&record.ID, &record.APIKeyID, &record.APIKeyName, &record.Action,
+		&record.Target, &record.RequestID, &record.CreatedAt,
+	)
+}
+
+func (r *PostgresAuthRepository) CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+	ctx, cancel := startQuery(ctx, "CreateAPIKey", writeTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO api_keys (name, role, prefix, key_hash)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, tagQuery(ctx, query),
+		key.Name, key.Role, key.Prefix, key.KeyHash,
+	).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresAuthRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	ctx, cancel := startQuery(ctx, "GetAPIKeyByPrefix", readTimeout)
+	defer cancel()
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE prefix = $1`
+
+	key := &models.APIKey{}
+	err := scanAPIKey(r.db.QueryRowContext(ctx, tagQuery(ctx, query), prefix), key)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return key, nil
+}
+
+func (r *PostgresAuthRepository) GetAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	ctx, cancel := startQuery(ctx, "GetAPIKeys", listTimeout)
+	defer cancel()
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, tagQuery(ctx, query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []models.APIKey
+	for rows.Next() {
+		key := models.APIKey{}
+		if err := scanAPIKey(rows, &key); err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey returns false if the key does not exist or is already revoked
+func (r *PostgresAuthRepository) RevokeAPIKey(ctx context.Context, id int64) (bool, error) {
+	ctx, cancel := startQuery(ctx, "RevokeAPIKey", writeTimeout)
+	defer cancel()
+
+	query := `UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND revoked_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, tagQuery(ctx, query), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *PostgresAuthRepository) TouchAPIKey(ctx context.Context, id int64, usedAt time.Time) error {
+	ctx, cancel := startQuery(ctx, "TouchAPIKey", writeTimeout)
+	defer cancel()
+
+	query := `UPDATE api_keys SET last_used_at = $2 WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, tagQuery(ctx, query), id, usedAt.UTC()); err != nil {
+		return fmt.Errorf("failed to update api key last use: %w", err)
+	}
+
+	return nil
+}
+
+// SetAPIKeyRateLimit replaces the rate limit of a key, nil restores the default.
+// Returns false if the key does not exist.
+func (r *PostgresAuthRepository) SetAPIKeyRateLimit(ctx context.Context, id int64, limit *models.RateLimit) (bool, error) {
+	ctx, cancel := startQuery(ctx, "SetAPIKeyRateLimit", writeTimeout)
+	defer cancel()
+
+	query := `UPDATE api_keys SET rate_limit = $2, rate_burst = $3 WHERE id = $1`
+
+	var rate sql.NullFloat64
+	var burst sql.NullInt64
+	if limit != nil {
+		rate = sql.NullFloat64{Float64: limit.Rate, Valid: true}
+		burst = sql.NullInt64{Int64: int64(limit.Burst), Valid: true}
+	}
+
+	result, err := r.db.ExecContext(ctx, tagQuery(ctx, query), id, rate, burst)
+	if err != nil {
+		return false, fmt.Errorf("failed to set api key rate limit: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to set api key rate limit: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *PostgresAuthRepository) CreateAuditRecord(ctx context.Context, record *models.AuditRecord) error {
+	ctx, cancel := startQuery(ctx, "CreateAuditRecord", writeTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO audit_log (api_key_id, api_key_name, action, target, request_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, tagQuery(ctx, query),
+		record.APIKeyID, record.APIKeyName, record.Action, record.Target, record.RequestID,
+	).Scan(&record.ID, &record.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create audit record: %w", err)
+	}
+
+	return nil
+}
+
+// GetAuditRecords returns the most recent records first
+func (r *PostgresAuthRepository) GetAuditRecords(ctx context.Context, limit int) ([]models.AuditRecord, error) {
+	ctx, cancel := startQuery(ctx, "GetAuditRecords", listTimeout)
+	defer cancel()
+
+	query := `SELECT ` + auditRecordColumns + ` FROM audit_log ORDER BY id DESC LIMIT $1`
+
+	rows, err := r.db.QueryContext(ctx, tagQuery(ctx, query), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var records []models.AuditRecord
+	for rows.Next() {
+		record := models.AuditRecord{}
+		if err := scanAuditRecord(rows, &record); err != nil {
+			return nil, fmt.Errorf("failed to scan audit record: %w", err)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
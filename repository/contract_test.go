@@ -0,0 +1,719 @@
+package repository_test
+
+import (
+	"context"
+	"rockets-backend/models"
+	"rockets-backend/repository"
+	"rockets-backend/testutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func upsertRocket(t *testing.T, repo repository.RocketRepository, rocket *models.Rocket) {
+	t.Helper()
+	_, err := repo.UpsertRocket(context.Background(), rocket)
+	testutil.AssertNoError(t, err)
+}
+
+// testRocketRepositoryContract runs the behaviour every RocketRepository implementation must share.
+// newRepo must return an empty repository.
+func testRocketRepositoryContract(t *testing.T, newRepo func(t *testing.T) repository.RocketRepository) {
+	// Postgres TIMESTAMP columns keep microseconds and no time zone
+	base := time.Now().UTC().Truncate(time.Microsecond)
+	ctx := context.Background()
+
+	newRocket := func(id models.UUID, messageNumber int) *models.Rocket {
+		return &models.Rocket{
+			ID:                id,
+			Type:              "Falcon-9",
+			CurrentSpeed:      500,
+			Mission:           "ARTEMIS",
+			Status:            models.RocketStatusActive,
+			LaunchTime:        base,
+			LastUpdated:       base,
+			LastMessageNumber: messageNumber,
+		}
+	}
+
+	t.Run("GetMissingRocket", func(t *testing.T) {
+		repo := newRepo(t)
+
+		rocket, err := repo.GetRocket(ctx, uuid.New().String())
+		testutil.AssertNoError(t, err)
+		if rocket != nil {
+			t.Fatalf("Expected nil rocket, got %+v", rocket)
+		}
+	})
+
+	t.Run("RocketRoundTrip", func(t *testing.T) {
+		repo := newRepo(t)
+		id := uuid.New().String()
+
+		reason := "engine failure"
+		latitude, longitude, fuel, acceleration := 28.5, -80.6, 64.5, -12.5
+		separatedAt := base.Add(time.Second)
+		rocket := newRocket(id, 7)
+		rocket.Status = models.RocketStatusExploded
+		rocket.ExplosionReason = &reason
+		rocket.Altitude = 12000
+		rocket.Latitude = &latitude
+		rocket.Longitude = &longitude
+		rocket.FuelLevel = &fuel
+		rocket.ActiveStage = 2
+		rocket.Stages = []models.RocketStage{{Number: 1, SeparatedAt: &separatedAt}, {Number: 2}}
+		rocket.Payloads = []models.RocketPayload{{Name: "SAT-1", Deployed: true, DeployedAt: &separatedAt}}
+		rocket.PeakSpeed = 900
+		rocket.TimeAtMaxSpeed = &separatedAt
+		rocket.AverageSpeed = 700.5
+		rocket.SpeedSamples = 3
+		rocket.Acceleration = &acceleration
+		rocket.LastSpeedAt = &separatedAt
+		upsertRocket(t, repo, rocket)
+
+		saved, err := repo.GetRocket(ctx, id)
+		testutil.AssertNoError(t, err)
+		testutil.AssertNotNil(t, saved)
+		testutil.AssertEqual(t, models.RocketStatusExploded, saved.Status)
+		testutil.AssertEqual(t, reason, *saved.ExplosionReason)
+		testutil.AssertEqual(t, 12000, saved.Altitude)
+		testutil.AssertEqual(t, latitude, *saved.Latitude)
+		testutil.AssertEqual(t, longitude, *saved.Longitude)
+		testutil.AssertEqual(t, fuel, *saved.FuelLevel)
+		testutil.AssertEqual(t, 2, saved.ActiveStage)
+		testutil.AssertEqual(t, 2, len(saved.Stages))
+		testutil.AssertEqual(t, true, saved.Stages[0].SeparatedAt.Equal(separatedAt))
+		testutil.AssertEqual(t, 1, len(saved.Payloads))
+		testutil.AssertEqual(t, true, saved.Payloads[0].Deployed)
+		testutil.AssertEqual(t, 900, saved.PeakSpeed)
+		testutil.AssertEqual(t, true, saved.TimeAtMaxSpeed.Equal(separatedAt))
+		testutil.AssertEqual(t, 700.5, saved.AverageSpeed)
+		testutil.AssertEqual(t, 3, saved.SpeedSamples)
+		testutil.AssertEqual(t, acceleration, *saved.Acceleration)
+		testutil.AssertEqual(t, true, saved.LaunchTime.Equal(base))
+		testutil.AssertEqual(t, 7, saved.LastMessageNumber)
+
+		// Returned rockets must not alias stored state
+		saved.Stages[0].Number = 99
+		again, err := repo.GetRocket(ctx, id)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, again.Stages[0].Number)
+	})
+
+	t.Run("UpsertOnlyAppliesNewerMessages", func(t *testing.T) {
+		repo := newRepo(t)
+		id := uuid.New().String()
+
+		upsertRocket(t, repo, newRocket(id, 2))
+
+		older := newRocket(id, 1)
+		older.CurrentSpeed = 100
+		upsertRocket(t, repo, older)
+
+		same := newRocket(id, 2)
+		same.CurrentSpeed = 200
+		upsertRocket(t, repo, same)
+
+		rocket, err := repo.GetRocket(ctx, id)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 500, rocket.CurrentSpeed)
+
+		newer := newRocket(id, 3)
+		newer.CurrentSpeed = 300
+		upsertRocket(t, repo, newer)
+
+		rocket, err = repo.GetRocket(ctx, id)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 300, rocket.CurrentSpeed)
+		testutil.AssertEqual(t, 3, rocket.LastMessageNumber)
+	})
+
+	t.Run("GetAllRocketsOrderingAndFilters", func(t *testing.T) {
+		repo := newRepo(t)
+
+		rockets, err := repo.GetAllRockets(ctx, "", models.RocketFilter{})
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 0, len(rockets))
+
+		fast, slow, unknown := uuid.New().String(), uuid.New().String(), uuid.New().String()
+		fullTank, emptyTank := 90.0, 10.0
+
+		r := newRocket(fast, 1)
+		r.CurrentSpeed = 1200
+		r.Altitude = 5000
+		r.FuelLevel = &emptyTank
+		r.LastUpdated = base.Add(2 * time.Second)
+		upsertRocket(t, repo, r)
+
+		r = newRocket(slow, 1)
+		r.CurrentSpeed = 300
+		r.Altitude = 100
+		r.FuelLevel = &fullTank
+		r.LastUpdated = base.Add(time.Second)
+		upsertRocket(t, repo, r)
+
+		r = newRocket(unknown, 1)
+		r.CurrentSpeed = 800
+		r.Status = models.RocketStatusLostContact
+		r.LastUpdated = base
+		upsertRocket(t, repo, r)
+
+		// Default ordering is most recently updated first
+		rockets, err = repo.GetAllRockets(ctx, "", models.RocketFilter{})
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 3, len(rockets))
+		testutil.AssertEqual(t, fast, rockets[0].ID)
+		testutil.AssertEqual(t, unknown, rockets[2].ID)
+
+		// Unknown sort keys fall back to the default ordering
+		rockets, err = repo.GetAllRockets(ctx, "speed; DROP TABLE rockets", models.RocketFilter{})
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, fast, rockets[0].ID)
+
+		rockets, err = repo.GetAllRockets(ctx, "speed", models.RocketFilter{})
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, slow, rockets[0].ID)
+		testutil.AssertEqual(t, unknown, rockets[1].ID)
+		testutil.AssertEqual(t, fast, rockets[2].ID)
+
+		// NULLs sort last
+		rockets, err = repo.GetAllRockets(ctx, "fuelLevel", models.RocketFilter{})
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, fast, rockets[0].ID)
+		testutil.AssertEqual(t, slow, rockets[1].ID)
+		testutil.AssertEqual(t, unknown, rockets[2].ID)
+
+		minAltitude := 1000
+		rockets, err = repo.GetAllRockets(ctx, "", models.RocketFilter{MinAltitude: &minAltitude})
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, len(rockets))
+		testutil.AssertEqual(t, fast, rockets[0].ID)
+
+		// NULL never matches a range filter
+		maxFuel := 50.0
+		rockets, err = repo.GetAllRockets(ctx, "", models.RocketFilter{MaxFuelLevel: &maxFuel})
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, len(rockets))
+		testutil.AssertEqual(t, fast, rockets[0].ID)
+
+		rockets, err = repo.GetAllRockets(ctx, "", models.RocketFilter{Status: models.RocketStatusLostContact})
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, len(rockets))
+		testutil.AssertEqual(t, unknown, rockets[0].ID)
+	})
+
+	t.Run("StaleRocketsAndStatusCompareAndSet", func(t *testing.T) {
+		repo := newRepo(t)
+		stale, fresh, exploded := uuid.New().String(), uuid.New().String(), uuid.New().String()
+
+		r := newRocket(stale, 1)
+		r.LastUpdated = base.Add(-time.Hour)
+		upsertRocket(t, repo, r)
+		upsertRocket(t, repo, newRocket(fresh, 1))
+		r = newRocket(exploded, 1)
+		r.Status = models.RocketStatusExploded
+		r.LastUpdated = base.Add(-time.Hour)
+		upsertRocket(t, repo, r)
+
+		cutoff := base.Add(-time.Minute)
+		rockets, err := repo.GetStaleRockets(ctx, cutoff)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, len(rockets))
+		testutil.AssertEqual(t, stale, rockets[0].ID)
+
+		updated, err := repo.UpdateRocketStatus(ctx, stale, models.RocketStatusActive, models.RocketStatusLostContact, cutoff)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, true, updated)
+
+		updated, err = repo.UpdateRocketStatus(ctx, stale, models.RocketStatusActive, models.RocketStatusLostContact, cutoff)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, false, updated)
+
+		updated, err = repo.UpdateRocketStatus(ctx, uuid.New().String(), models.RocketStatusActive, models.RocketStatusLostContact, cutoff)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, false, updated)
+
+		// A rocket updated after the cutoff is no longer stale and keeps its status
+		updated, err = repo.UpdateRocketStatus(ctx, fresh, models.RocketStatusActive, models.RocketStatusLostContact, cutoff)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, false, updated)
+
+		// Saving the rocket reports the status it overwrote
+		previous, err := repo.UpsertRocket(ctx, newRocket(stale, 2))
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, models.RocketStatusLostContact, previous)
+		previous, err = repo.UpsertRocket(ctx, newRocket(uuid.New().String(), 1))
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, "", previous)
+
+		stats, err := repo.GetRocketStats(ctx)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 4, stats.Total)
+		testutil.AssertEqual(t, 0, stats.LostContact)
+		testutil.AssertEqual(t, 3, stats.ByStatus[models.RocketStatusActive])
+		testutil.AssertEqual(t, 1, stats.ByStatus[models.RocketStatusExploded])
+	})
+
+	t.Run("StatusHistory", func(t *testing.T) {
+		repo := newRepo(t)
+		id := uuid.New().String()
+
+		active := models.RocketStatusActive
+		messageNumber := 1
+		first := &models.RocketStatusChange{RocketID: id, ToStatus: active, Reason: "RocketLaunched", MessageNumber: &messageNumber}
+		testutil.AssertNoError(t, repo.CreateStatusChange(ctx, first))
+		testutil.AssertNotEqual(t, int64(0), first.ID)
+
+		second := &models.RocketStatusChange{RocketID: id, FromStatus: &active, ToStatus: models.RocketStatusLostContact, Reason: "silent"}
+		testutil.AssertNoError(t, repo.CreateStatusChange(ctx, second))
+		testutil.AssertNoError(t, repo.CreateStatusChange(ctx, &models.RocketStatusChange{
+			RocketID: uuid.New().String(), ToStatus: active, Reason: "other rocket",
+		}))
+
+		history, err := repo.GetStatusHistory(ctx, id)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 2, len(history))
+		testutil.AssertEqual(t, first.ID, history[0].ID)
+		if history[0].FromStatus != nil {
+			t.Fatalf("Expected no from status on the initial entry")
+		}
+		testutil.AssertEqual(t, 1, *history[0].MessageNumber)
+		testutil.AssertEqual(t, models.RocketStatusLostContact, history[1].ToStatus)
+		if history[1].MessageNumber != nil {
+			t.Fatalf("Expected no message number on a timer driven entry")
+		}
+	})
+
+	t.Run("EventConflictKeepsIDAndStatus", func(t *testing.T) {
+		repo := newRepo(t)
+		channel := uuid.New().String()
+		messageTime := time.Date(2022, 2, 2, 19, 39, 5, 0, time.FixedZone("CET", 3600))
+
+		first := &models.RocketEvent{
+			Channel:       channel,
+			MessageNumber: 1,
+			MessageType:   "RocketLaunched",
+			MessageData:   []byte(`{"type":"Falcon-9","launchSpeed":500,"mission":"TEST"}`),
+			MessageTime:   messageTime,
+		}
+		testutil.AssertNoError(t, repo.CreateRocketEvent(ctx, first))
+		testutil.AssertNotEqual(t, int64(0), first.ID)
+		testutil.AssertEqual(t, models.EventStatusPending, first.Status)
+		testutil.AssertEqual(t, true, first.MessageTime.Equal(messageTime))
+
+		testutil.AssertNoError(t, repo.MarkEventProcessed(ctx, first.ID))
+
+		duplicate := &models.RocketEvent{
+			Channel:       channel,
+			MessageNumber: 1,
+			MessageType:   "RocketSpeedIncreased",
+			MessageData:   []byte(`{"by":100}`),
+		}
+		testutil.AssertNoError(t, repo.CreateRocketEvent(ctx, duplicate))
+		testutil.AssertEqual(t, first.ID, duplicate.ID)
+
+		saved, err := repo.GetRocketEvent(ctx, first.ID)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, "RocketSpeedIncreased", saved.MessageType)
+		testutil.AssertEqual(t, models.EventStatusProcessed, saved.Status)
+		testutil.AssertNotNil(t, saved.ProcessedAt)
+
+		missing, err := repo.GetRocketEvent(ctx, first.ID+1000)
+		testutil.AssertNoError(t, err)
+		if missing != nil {
+			t.Fatalf("Expected nil event, got %+v", missing)
+		}
+	})
+
+	t.Run("EventTraceContext", func(t *testing.T) {
+		repo := newRepo(t)
+		channel := uuid.New().String()
+
+		requestID := "req-1"
+		traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+		event := &models.RocketEvent{
+			Channel:       channel,
+			MessageNumber: 1,
+			MessageType:   "RocketSpeedIncreased",
+			MessageData:   []byte(`{"by":100}`),
+			RequestID:     &requestID,
+			TraceParent:   &traceParent,
+		}
+		testutil.AssertNoError(t, repo.CreateRocketEvent(ctx, event))
+
+		pending, err := repo.GetPendingEvents(ctx, 10)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, len(pending))
+		testutil.AssertEqual(t, requestID, *pending[0].RequestID)
+		testutil.AssertEqual(t, traceParent, *pending[0].TraceParent)
+
+		// A redelivery without trace context clears it
+		redelivered := &models.RocketEvent{
+			Channel:       channel,
+			MessageNumber: 1,
+			MessageType:   "RocketSpeedIncreased",
+			MessageData:   []byte(`{"by":100}`),
+		}
+		testutil.AssertNoError(t, repo.CreateRocketEvent(ctx, redelivered))
+
+		saved, err := repo.GetRocketEvent(ctx, event.ID)
+		testutil.AssertNoError(t, err)
+		if saved.RequestID != nil || saved.TraceParent != nil {
+			t.Fatalf("Expected trace context to be replaced, got %v %v", saved.RequestID, saved.TraceParent)
+		}
+	})
+
+	t.Run("PendingEventsAndStatusUpdates", func(t *testing.T) {
+		repo := newRepo(t)
+		channel := uuid.New().String()
+
+		var ids []int64
+		for i := 1; i <= 3; i++ {
+			event := &models.RocketEvent{
+				Channel:       channel,
+				MessageNumber: i,
+				MessageType:   "RocketSpeedIncreased",
+				MessageData:   []byte(`{"by":100}`),
+			}
+			testutil.AssertNoError(t, repo.CreateRocketEvent(ctx, event))
+			ids = append(ids, event.ID)
+			time.Sleep(2 * time.Millisecond) // distinct received_at
+		}
+
+		pending, err := repo.GetPendingEvents(ctx, 2)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 2, len(pending))
+		testutil.AssertEqual(t, ids[0], pending[0].ID)
+		testutil.AssertEqual(t, ids[1], pending[1].ID)
+
+		testutil.AssertNoError(t, repo.UpdateEventStatus(ctx, ids[0], models.EventStatusProcessing, nil))
+		event, err := repo.GetRocketEvent(ctx, ids[0])
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, models.EventStatusProcessing, event.Status)
+		if event.ProcessedAt != nil {
+			t.Fatalf("Expected no processed_at while processing")
+		}
+
+		errorMessage := "boom"
+		testutil.AssertNoError(t, repo.UpdateEventStatus(ctx, ids[1], models.EventStatusFailed, &errorMessage))
+		event, err = repo.GetRocketEvent(ctx, ids[1])
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, models.EventStatusFailed, event.Status)
+		testutil.AssertEqual(t, errorMessage, *event.ErrorMessage)
+		testutil.AssertNotNil(t, event.ProcessedAt)
+
+		pending, err = repo.GetPendingEvents(ctx, 10)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, len(pending))
+		testutil.AssertEqual(t, ids[2], pending[0].ID)
+	})
+
+	t.Run("ClaimAndReleaseEvents", func(t *testing.T) {
+		repo := newRepo(t)
+		channel, other := uuid.New().String(), uuid.New().String()
+
+		createEvent := func(channel string, messageNumber int) int64 {
+			event := &models.RocketEvent{
+				Channel:       channel,
+				MessageNumber: messageNumber,
+				MessageType:   "RocketSpeedIncreased",
+				MessageData:   []byte(`{"by":100}`),
+			}
+			testutil.AssertNoError(t, repo.CreateRocketEvent(ctx, event))
+			time.Sleep(2 * time.Millisecond) // distinct received_at
+			return event.ID
+		}
+		ids := []int64{createEvent(channel, 1), createEvent(channel, 2), createEvent(other, 1),
+			createEvent(channel, 3)}
+
+		claimed, err := repo.ClaimPendingEvents(ctx, 2, time.Minute)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 2, len(claimed))
+		testutil.AssertEqual(t, ids[0], claimed[0].ID)
+		testutil.AssertEqual(t, ids[1], claimed[1].ID)
+		testutil.AssertEqual(t, models.EventStatusProcessing, claimed[0].Status)
+
+		// Claimed events are not handed out again, nor later events of their channel
+		claimed, err = repo.ClaimPendingEvents(ctx, 10, time.Minute)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, len(claimed))
+		testutil.AssertEqual(t, ids[2], claimed[0].ID)
+
+		// Only events still processing are released
+		testutil.AssertNoError(t, repo.MarkEventProcessed(ctx, ids[0]))
+		released, err := repo.ReleaseEvents(ctx, ids)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 2, released)
+
+		pending, err := repo.GetPendingEvents(ctx, 10)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 3, len(pending))
+		testutil.AssertEqual(t, ids[1], pending[0].ID)
+
+		// Once nothing of the channel is processing its next events are claimed
+		claimed, err = repo.ClaimPendingEvents(ctx, 10, time.Minute)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 3, len(claimed))
+
+		released, err = repo.ReleaseEvents(ctx, nil)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 0, released)
+	})
+
+	t.Run("ExpiredClaims", func(t *testing.T) {
+		repo := newRepo(t)
+		channel := uuid.New().String()
+
+		var ids []int64
+		for i := 1; i <= 2; i++ {
+			event := &models.RocketEvent{
+				Channel:       channel,
+				MessageNumber: i,
+				MessageType:   "RocketSpeedIncreased",
+				MessageData:   []byte(`{"by":100}`),
+			}
+			testutil.AssertNoError(t, repo.CreateRocketEvent(ctx, event))
+			time.Sleep(2 * time.Millisecond) // distinct received_at
+			ids = append(ids, event.ID)
+		}
+
+		claimed, err := repo.ClaimPendingEvents(ctx, 1, time.Minute)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, len(claimed))
+		time.Sleep(50 * time.Millisecond)
+
+		// A live claim holds back its channel
+		claimed, err = repo.ClaimPendingEvents(ctx, 10, time.Minute)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 0, len(claimed))
+		reclaimed, err := repo.ReclaimEvents(ctx, time.Minute)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 0, reclaimed)
+
+		// An expired one does not, and its event is claimed again first
+		claimed, err = repo.ClaimPendingEvents(ctx, 10, 10*time.Millisecond)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 2, len(claimed))
+		testutil.AssertEqual(t, ids[0], claimed[0].ID)
+		testutil.AssertEqual(t, ids[1], claimed[1].ID)
+
+		// Marking an event processing renews its claim
+		time.Sleep(50 * time.Millisecond)
+		testutil.AssertNoError(t, repo.UpdateEventStatus(ctx, ids[0], models.EventStatusProcessing, nil))
+		reclaimed, err = repo.ReclaimEvents(ctx, 40*time.Millisecond)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, reclaimed)
+
+		pending, err := repo.GetPendingEvents(ctx, 10)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, len(pending))
+		testutil.AssertEqual(t, ids[1], pending[0].ID)
+	})
+
+	t.Run("ConcurrentClaimsKeepChannelOnOneClaimer", func(t *testing.T) {
+		repo := newRepo(t)
+		channel := uuid.New().String()
+		for i := 1; i <= 10; i++ {
+			testutil.AssertNoError(t, repo.CreateRocketEvent(ctx, &models.RocketEvent{
+				Channel:       channel,
+				MessageNumber: i,
+				MessageType:   "RocketSpeedIncreased",
+				MessageData:   []byte(`{"by":100}`),
+			}))
+		}
+
+		// Two claimers race for one message at a time, only one of them may get it
+		for round := 0; round < 5; round++ {
+			start := make(chan struct{})
+			counts := make(chan int, 2)
+			var claimedIDs [2][]int64
+			for claimer := 0; claimer < 2; claimer++ {
+				go func(claimer int) {
+					<-start
+					events, err := repo.ClaimPendingEvents(ctx, 1, time.Minute)
+					if err != nil {
+						t.Errorf("failed to claim events: %v", err)
+					}
+					for _, event := range events {
+						claimedIDs[claimer] = append(claimedIDs[claimer], event.ID)
+					}
+					counts <- len(events)
+				}(claimer)
+			}
+			close(start)
+			total := <-counts + <-counts
+			testutil.AssertEqual(t, 1, total)
+
+			for _, ids := range claimedIDs {
+				for _, id := range ids {
+					testutil.AssertNoError(t, repo.MarkEventProcessed(ctx, id))
+				}
+			}
+		}
+	})
+
+	t.Run("ArchiveEvents", func(t *testing.T) {
+		repo := newRepo(t)
+		channel := uuid.New().String()
+
+		var ids []int64
+		for i := 1; i <= 4; i++ {
+			event := &models.RocketEvent{
+				Channel:       channel,
+				MessageNumber: i,
+				MessageType:   "RocketSpeedIncreased",
+				MessageData:   []byte(`{"by":100}`),
+			}
+			testutil.AssertNoError(t, repo.CreateRocketEvent(ctx, event))
+			ids = append(ids, event.ID)
+		}
+		testutil.AssertNoError(t, repo.MarkEventProcessed(ctx, ids[0]))
+		testutil.AssertNoError(t, repo.MarkEventProcessed(ctx, ids[1]))
+		errorMessage := "boom"
+		testutil.AssertNoError(t, repo.UpdateEventStatus(ctx, ids[2], models.EventStatusFailed, &errorMessage))
+		time.Sleep(5 * time.Millisecond)
+
+		// Nothing is old enough yet
+		moved, err := repo.ArchiveEvents(ctx, models.EventStatusProcessed, time.Hour, 10)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 0, moved)
+
+		moved, err = repo.ArchiveEvents(ctx, models.EventStatusProcessed, 0, 1)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, moved)
+		moved, err = repo.ArchiveEvents(ctx, models.EventStatusProcessed, 0, 10)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, moved)
+
+		archived, err := repo.GetRocketEvent(ctx, ids[0])
+		testutil.AssertNoError(t, err)
+		if archived != nil {
+			t.Fatalf("Expected archived event to leave rocket_events, got %+v", archived)
+		}
+
+		// Other statuses stay until archived on their own
+		failed, err := repo.GetRocketEvent(ctx, ids[2])
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, models.EventStatusFailed, failed.Status)
+		moved, err = repo.ArchiveEvents(ctx, models.EventStatusFailed, 0, 10)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, moved)
+
+		pending, err := repo.GetPendingEvents(ctx, 10)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, len(pending))
+		testutil.AssertEqual(t, ids[3], pending[0].ID)
+	})
+
+	t.Run("EventQueueStats", func(t *testing.T) {
+		repo := newRepo(t)
+		channel := uuid.New().String()
+
+		stats, err := repo.GetEventQueueStats(ctx)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 0, stats.Pending)
+		testutil.AssertEqual(t, 0.0, stats.OldestPendingAgeSeconds)
+
+		var ids []int64
+		for i := 1; i <= 2; i++ {
+			event := &models.RocketEvent{
+				Channel:       channel,
+				MessageNumber: i,
+				MessageType:   "RocketSpeedIncreased",
+				MessageData:   []byte(`{"by":100}`),
+			}
+			testutil.AssertNoError(t, repo.CreateRocketEvent(ctx, event))
+			ids = append(ids, event.ID)
+		}
+		testutil.AssertNoError(t, repo.MarkEventProcessed(ctx, ids[0]))
+
+		stats, err = repo.GetEventQueueStats(ctx)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, stats.Pending)
+		if stats.OldestPendingAgeSeconds < 0 || stats.OldestPendingAgeSeconds > 60 {
+			t.Fatalf("Unexpected oldest pending age %v", stats.OldestPendingAgeSeconds)
+		}
+	})
+
+	t.Run("RocketSnapshots", func(t *testing.T) {
+		repo := newRepo(t)
+		channel := uuid.New().String()
+
+		snapshot, err := repo.GetLatestRocketSnapshot(ctx, channel, 100)
+		testutil.AssertNoError(t, err)
+		if snapshot != nil {
+			t.Fatalf("Expected no snapshot, got %+v", snapshot)
+		}
+
+		for _, number := range []int{20, 10} {
+			rocket := newRocket(channel, number)
+			state, err := models.EncodeRocketState(*rocket)
+			testutil.AssertNoError(t, err)
+			checksum, err := models.RocketStateChecksum(*rocket)
+			testutil.AssertNoError(t, err)
+			testutil.AssertNoError(t, repo.CreateRocketSnapshot(ctx, &models.RocketSnapshot{
+				RocketID: channel, MessageNumber: number, State: state, Checksum: checksum,
+			}))
+		}
+
+		snapshot, err = repo.GetLatestRocketSnapshot(ctx, channel, 15)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 10, snapshot.MessageNumber)
+		rocket, err := models.DecodeRocketState(snapshot.State)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 10, rocket.LastMessageNumber)
+		testutil.AssertEqual(t, true, rocket.LaunchTime.Equal(base))
+		checksum, err := models.RocketStateChecksum(*rocket)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, snapshot.Checksum, checksum)
+
+		snapshot, err = repo.GetLatestRocketSnapshot(ctx, channel, 20)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 20, snapshot.MessageNumber)
+
+		snapshots, err := repo.GetRocketSnapshots(ctx, channel)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 2, len(snapshots))
+		testutil.AssertEqual(t, 10, snapshots[0].MessageNumber)
+		testutil.AssertEqual(t, 20, snapshots[1].MessageNumber)
+	})
+
+	t.Run("GetProcessedEvents", func(t *testing.T) {
+		repo := newRepo(t)
+		channel := uuid.New().String()
+
+		var ids []int64
+		for _, number := range []int{3, 1, 2, 4} {
+			event := &models.RocketEvent{
+				Channel:       channel,
+				MessageNumber: number,
+				MessageType:   "RocketSpeedIncreased",
+				MessageData:   []byte(`{"by":100}`),
+			}
+			testutil.AssertNoError(t, repo.CreateRocketEvent(ctx, event))
+			ids = append(ids, event.ID)
+		}
+		for _, id := range ids[:3] {
+			testutil.AssertNoError(t, repo.MarkEventProcessed(ctx, id))
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		// Archived events are still part of the log
+		moved, err := repo.ArchiveEvents(ctx, models.EventStatusProcessed, 0, 1)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, moved)
+
+		events, err := repo.GetProcessedEvents(ctx, channel, 0, 10)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 3, len(events))
+		testutil.AssertEqual(t, 3, events[0].MessageNumber)
+		testutil.AssertEqual(t, 1, events[1].MessageNumber)
+		testutil.AssertEqual(t, 2, events[2].MessageNumber)
+
+		events, err = repo.GetProcessedEvents(ctx, channel, 1, 2)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, len(events))
+		testutil.AssertEqual(t, ids[2], events[0].ID)
+	})
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"rockets-backend/models"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryAlertRepository is a concurrency-safe in-memory AlertRepository
+type MemoryAlertRepository struct {
+	mu          sync.RWMutex
+	rules       map[int64]*models.AlertRule
+	alerts      map[int64]*models.Alert
+	nextRuleID  int64
+	nextAlertID int64
+}
+
+func NewMemoryAlertRepository() AlertRepository {
+	return &MemoryAlertRepository{
+		rules:  make(map[int64]*models.AlertRule),
+		alerts: make(map[int64]*models.Alert),
+	}
+}
+
+func (r *MemoryAlertRepository) CreateAlertRule(ctx context.Context, rule *models.AlertRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextRuleID++
+	rule.ID = r.nextRuleID
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = rule.CreatedAt
+
+	r.rules[rule.ID] = copyAlertRule(rule)
+	return nil
+}
+
+func (r *MemoryAlertRepository) GetAlertRule(ctx context.Context, id int64) (*models.AlertRule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rule, ok := r.rules[id]
+	if !ok {
+		return nil, nil
+	}
+	return copyAlertRule(rule), nil
+}
+
+func (r *MemoryAlertRepository) GetAlertRules(ctx context.Context, enabledOnly bool) ([]models.AlertRule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var rules []models.AlertRule
+	for _, rule := range r.rules {
+		if !enabledOnly || rule.Enabled {
+			rules = append(rules, *copyAlertRule(rule))
+		}
+	}
+
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	return rules, nil
+}
+
+func (r *MemoryAlertRepository) UpdateAlertRule(ctx context.Context, rule *models.AlertRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.rules[rule.ID]
+	if !ok {
+		return fmt.Errorf("failed to update alert rule: no alert rule with id %d", rule.ID)
+	}
+
+	rule.CreatedAt = existing.CreatedAt
+	rule.UpdatedAt = time.Now()
+	r.rules[rule.ID] = copyAlertRule(rule)
+	return nil
+}
+
+func (r *MemoryAlertRepository) DeleteAlertRule(ctx context.Context, id int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.rules[id]; !ok {
+		return false, nil
+	}
+	delete(r.rules, id)
+
+	// ON DELETE SET NULL
+	for _, alert := range r.alerts {
+		if alert.RuleID != nil && *alert.RuleID == id {
+			alert.RuleID = nil
+		}
+	}
+	return true, nil
+}
+
+func (r *MemoryAlertRepository) CreateAlert(ctx context.Context, alert *models.Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextAlertID++
+	alert.ID = r.nextAlertID
+	alert.TriggeredAt = time.Now()
+	alert.Status = models.AlertStatusOpen
+
+	r.alerts[alert.ID] = copyAlert(alert)
+	return nil
+}
+
+func (r *MemoryAlertRepository) GetAlert(ctx context.Context, id int64) (*models.Alert, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	alert, ok := r.alerts[id]
+	if !ok {
+		return nil, nil
+	}
+	return copyAlert(alert), nil
+}
+
+func (r *MemoryAlertRepository) GetAlerts(ctx context.Context, filter models.AlertFilter) ([]models.Alert, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var alerts []models.Alert
+	for _, alert := range r.alerts {
+		if filter.Status != "" && alert.Status != filter.Status {
+			continue
+		}
+		if filter.RocketID != "" && alert.RocketID != filter.RocketID {
+			continue
+		}
+		alerts = append(alerts, *copyAlert(alert))
+	}
+
+	// Newest first
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].ID > alerts[j].ID })
+	return alerts, nil
+}
+
+func (r *MemoryAlertRepository) UpdateAlertStatus(ctx context.Context, id int64, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	alert, ok := r.alerts[id]
+	if !ok {
+		return nil
+	}
+
+	now := time.Now()
+	alert.Status = status
+	switch status {
+	case models.AlertStatusAcknowledged:
+		alert.AcknowledgedAt = &now
+	case models.AlertStatusResolved:
+		alert.ResolvedAt = &now
+	}
+	return nil
+}
+
+func copyAlertRule(rule *models.AlertRule) *models.AlertRule {
+	c := *rule
+	c.RocketType = copyString(rule.RocketType)
+	return &c
+}
+
+func copyAlert(alert *models.Alert) *models.Alert {
+	c := *alert
+	if alert.RuleID != nil {
+		ruleID := *alert.RuleID
+		c.RuleID = &ruleID
+	}
+	c.MessageData = copyRawMessage(alert.MessageData)
+	c.AcknowledgedAt = copyTime(alert.AcknowledgedAt)
+	c.ResolvedAt = copyTime(alert.ResolvedAt)
+	return &c
+}
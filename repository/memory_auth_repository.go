@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"rockets-backend/models"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryAuthRepository is a concurrency-safe in-memory AuthRepository
+type MemoryAuthRepository struct {
+	mu           sync.RWMutex
+	keys         map[int64]*models.APIKey
+	audit        []models.AuditRecord
+	nextKeyID    int64
+	nextRecordID int64
+}
+
+func NewMemoryAuthRepository() AuthRepository {
+	return &MemoryAuthRepository{
+		keys: make(map[int64]*models.APIKey),
+	}
+}
+
+func (r *MemoryAuthRepository) CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Mirrors the UNIQUE constraint on api_keys.prefix
+	for _, existing := range r.keys {
+		if existing.Prefix == key.Prefix {
+			return fmt.Errorf("failed to create api key: prefix %s already exists", key.Prefix)
+		}
+	}
+
+	r.nextKeyID++
+	key.ID = r.nextKeyID
+	key.CreatedAt = time.Now()
+
+	r.keys[key.ID] = copyAPIKey(key)
+	return nil
+}
+
+func (r *MemoryAuthRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.keys {
+		if key.Prefix == prefix {
+			return copyAPIKey(key), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *MemoryAuthRepository) GetAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]models.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, *copyAPIKey(key))
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys, nil
+}
+
+func (r *MemoryAuthRepository) RevokeAPIKey(ctx context.Context, id int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.keys[id]
+	if !ok || key.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	key.RevokedAt = &now
+	return true, nil
+}
+
+func (r *MemoryAuthRepository) TouchAPIKey(ctx context.Context, id int64, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if key, ok := r.keys[id]; ok {
+		usedAt = usedAt.UTC()
+		key.LastUsedAt = &usedAt
+	}
+	return nil
+}
+
+func (r *MemoryAuthRepository) SetAPIKeyRateLimit(ctx context.Context, id int64, limit *models.RateLimit) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return false, nil
+	}
+	key.RateLimit = copyRateLimit(limit)
+	return true, nil
+}
+
+func (r *MemoryAuthRepository) CreateAuditRecord(ctx context.Context, record *models.AuditRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextRecordID++
+	record.ID = r.nextRecordID
+	record.CreatedAt = time.Now()
+
+	r.audit = append(r.audit, *copyAuditRecord(record))
+	return nil
+}
+
+func (r *MemoryAuthRepository) GetAuditRecords(ctx context.Context, limit int) ([]models.AuditRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var records []models.AuditRecord
+	for i := len(r.audit) - 1; i >= 0 && len(records) < limit; i-- {
+		records = append(records, *copyAuditRecord(&r.audit[i]))
+	}
+	return records, nil
+}
+
+func copyAPIKey(key *models.APIKey) *models.APIKey {
+	c := *key
+	c.LastUsedAt = copyTime(key.LastUsedAt)
+	c.RevokedAt = copyTime(key.RevokedAt)
+	c.RateLimit = copyRateLimit(key.RateLimit)
+	return &c
+}
+
+func copyRateLimit(limit *models.RateLimit) *models.RateLimit {
+	if limit == nil {
+		return nil
+	}
+	c := *limit
+	return &c
+}
+
+func copyAuditRecord(record *models.AuditRecord) *models.AuditRecord {
+	c := *record
+	if record.APIKeyID != nil {
+		keyID := *record.APIKeyID
+		c.APIKeyID = &keyID
+	}
+	c.APIKeyName = copyString(record.APIKeyName)
+	c.RequestID = copyString(record.RequestID)
+	return &c
+}
@@ -0,0 +1,592 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"rockets-backend/models"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryRocketRepository is a concurrency-safe in-memory RocketRepository with the same ordering,
+// upsert guard and conflict semantics as PostgresRocketRepository. Nothing is persisted.
+type MemoryRocketRepository struct {
+	mu            sync.RWMutex
+	rockets       map[models.UUID]*models.Rocket
+	events        map[int64]*models.RocketEvent
+	eventKeys     map[eventKey]int64
+	archive       map[int64]*models.RocketEvent
+	history       []models.RocketStatusChange
+	snapshots     map[models.UUID][]models.RocketSnapshot // per rocket, by message number
+	claimedAt     map[int64]time.Time                     // when processing events were claimed
+	nextEventID   int64
+	nextHistoryID int64
+}
+
+// eventKey mirrors the UNIQUE(channel, message_number) constraint on rocket_events
+type eventKey struct {
+	channel       models.UUID
+	messageNumber int
+}
+
+func NewMemoryRocketRepository() RocketRepository {
+	return &MemoryRocketRepository{
+		rockets:   make(map[models.UUID]*models.Rocket),
+		events:    make(map[int64]*models.RocketEvent),
+		eventKeys: make(map[eventKey]int64),
+		archive:   make(map[int64]*models.RocketEvent),
+		snapshots: make(map[models.UUID][]models.RocketSnapshot),
+		claimedAt: make(map[int64]time.Time),
+	}
+}
+
+func (r *MemoryRocketRepository) GetRocket(ctx context.Context, id models.UUID) (*models.Rocket, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rocket, ok := r.rockets[id]
+	if !ok {
+		return nil, nil
+	}
+	return copyRocket(rocket), nil
+}
+
+func (r *MemoryRocketRepository) GetAllRockets(ctx context.Context, sortBy string, filter models.RocketFilter) ([]models.Rocket, error) {
+	r.mu.RLock()
+	var rockets []models.Rocket
+	for _, rocket := range r.rockets {
+		if matchesRocketFilter(rocket, filter) {
+			rockets = append(rockets, *copyRocket(rocket))
+		}
+	}
+	r.mu.RUnlock()
+
+	less, ok := rocketSorts[sortBy]
+	if !ok {
+		// Default ordering is last_updated DESC
+		sort.SliceStable(rockets, func(i, j int) bool {
+			return rockets[i].LastUpdated.After(rockets[j].LastUpdated)
+		})
+		return rockets, nil
+	}
+
+	sort.SliceStable(rockets, func(i, j int) bool {
+		return less(&rockets[i], &rockets[j])
+	})
+	return rockets, nil
+}
+
+// rocketSorts are the ascending orderings accepted by GetAllRockets. Nullable columns sort NULLs
+// last, like Postgres does for ASC.
+var rocketSorts = map[string]func(a, b *models.Rocket) bool{
+	"type":           func(a, b *models.Rocket) bool { return a.Type < b.Type },
+	"speed":          func(a, b *models.Rocket) bool { return a.CurrentSpeed < b.CurrentSpeed },
+	"mission":        func(a, b *models.Rocket) bool { return a.Mission < b.Mission },
+	"status":         func(a, b *models.Rocket) bool { return a.Status < b.Status },
+	"altitude":       func(a, b *models.Rocket) bool { return a.Altitude < b.Altitude },
+	"latitude":       func(a, b *models.Rocket) bool { return lessNullableFloat(a.Latitude, b.Latitude) },
+	"longitude":      func(a, b *models.Rocket) bool { return lessNullableFloat(a.Longitude, b.Longitude) },
+	"fuelLevel":      func(a, b *models.Rocket) bool { return lessNullableFloat(a.FuelLevel, b.FuelLevel) },
+	"peakSpeed":      func(a, b *models.Rocket) bool { return a.PeakSpeed < b.PeakSpeed },
+	"averageSpeed":   func(a, b *models.Rocket) bool { return a.AverageSpeed < b.AverageSpeed },
+	"acceleration":   func(a, b *models.Rocket) bool { return lessNullableFloat(a.Acceleration, b.Acceleration) },
+	"timeAtMaxSpeed": func(a, b *models.Rocket) bool { return lessNullableTime(a.TimeAtMaxSpeed, b.TimeAtMaxSpeed) },
+	"launchTime":     func(a, b *models.Rocket) bool { return a.LaunchTime.Before(b.LaunchTime) },
+	"lastUpdated":    func(a, b *models.Rocket) bool { return a.LastUpdated.Before(b.LastUpdated) },
+}
+
+func lessNullableFloat(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a != nil && b == nil
+	}
+	return *a < *b
+}
+
+func lessNullableTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a != nil && b == nil
+	}
+	return a.Before(*b)
+}
+
+// matchesRocketFilter applies the filter like buildRocketFilter does, NULL never matches a range
+func matchesRocketFilter(rocket *models.Rocket, filter models.RocketFilter) bool {
+	inRange := func(value *float64, min, max *float64) bool {
+		if min == nil && max == nil {
+			return true
+		}
+		if value == nil {
+			return false
+		}
+		return (min == nil || *value >= *min) && (max == nil || *value <= *max)
+	}
+
+	if filter.Status != "" && rocket.Status != filter.Status {
+		return false
+	}
+	if filter.MinAltitude != nil && rocket.Altitude < *filter.MinAltitude {
+		return false
+	}
+	if filter.MaxAltitude != nil && rocket.Altitude > *filter.MaxAltitude {
+		return false
+	}
+	return inRange(rocket.Latitude, filter.MinLatitude, filter.MaxLatitude) &&
+		inRange(rocket.Longitude, filter.MinLongitude, filter.MaxLongitude) &&
+		inRange(rocket.FuelLevel, filter.MinFuelLevel, filter.MaxFuelLevel)
+}
+
+func (r *MemoryRocketRepository) UpsertRocket(ctx context.Context, rocket *models.Rocket) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.rockets[rocket.ID]
+	if !ok {
+		r.rockets[rocket.ID] = copyRocket(rocket)
+		return "", nil
+	}
+
+	// Same guard as the Postgres upsert: only newer messages overwrite existing state
+	if rocket.LastMessageNumber > existing.LastMessageNumber {
+		r.rockets[rocket.ID] = copyRocket(rocket)
+	}
+	return existing.Status, nil
+}
+
+func (r *MemoryRocketRepository) GetStaleRockets(ctx context.Context, lastUpdatedBefore time.Time) ([]models.Rocket, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var rockets []models.Rocket
+	for _, rocket := range r.rockets {
+		if rocket.Status == models.RocketStatusActive && rocket.LastUpdated.Before(lastUpdatedBefore) {
+			rockets = append(rockets, *copyRocket(rocket))
+		}
+	}
+
+	sort.SliceStable(rockets, func(i, j int) bool {
+		return rockets[i].LastUpdated.Before(rockets[j].LastUpdated)
+	})
+	return rockets, nil
+}
+
+func (r *MemoryRocketRepository) UpdateRocketStatus(ctx context.Context, id models.UUID, fromStatus, toStatus string, lastUpdatedBefore time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rocket, ok := r.rockets[id]
+	if !ok || rocket.Status != fromStatus || !rocket.LastUpdated.Before(lastUpdatedBefore) {
+		return false, nil
+	}
+
+	rocket.Status = toStatus
+	return true, nil
+}
+
+func (r *MemoryRocketRepository) GetRocketStats(ctx context.Context) (*models.RocketStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stats := &models.RocketStats{ByStatus: map[string]int{}}
+	for _, rocket := range r.rockets {
+		stats.ByStatus[rocket.Status]++
+		stats.Total++
+	}
+	stats.LostContact = stats.ByStatus[models.RocketStatusLostContact]
+
+	return stats, nil
+}
+
+// Status history operations
+func (r *MemoryRocketRepository) CreateStatusChange(ctx context.Context, change *models.RocketStatusChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextHistoryID++
+	change.ID = r.nextHistoryID
+	change.ChangedAt = time.Now()
+
+	r.history = append(r.history, *change)
+	return nil
+}
+
+func (r *MemoryRocketRepository) GetStatusHistory(ctx context.Context, rocketID models.UUID) ([]models.RocketStatusChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Entries are appended in ID order, which is also changed_at order
+	var history []models.RocketStatusChange
+	for _, change := range r.history {
+		if change.RocketID == rocketID {
+			history = append(history, change)
+		}
+	}
+	return history, nil
+}
+
+// Snapshot operations
+func (r *MemoryRocketRepository) CreateRocketSnapshot(ctx context.Context, snapshot *models.RocketSnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshot.CreatedAt = time.Now()
+	stored := copySnapshot(*snapshot)
+
+	snapshots := r.snapshots[snapshot.RocketID]
+	i := sort.Search(len(snapshots), func(i int) bool { return snapshots[i].MessageNumber >= snapshot.MessageNumber })
+	if i < len(snapshots) && snapshots[i].MessageNumber == snapshot.MessageNumber {
+		snapshots[i] = stored
+		return nil
+	}
+	snapshots = append(snapshots, models.RocketSnapshot{})
+	copy(snapshots[i+1:], snapshots[i:])
+	snapshots[i] = stored
+	r.snapshots[snapshot.RocketID] = snapshots
+	return nil
+}
+
+func (r *MemoryRocketRepository) GetLatestRocketSnapshot(ctx context.Context, rocketID models.UUID,
+	atOrBefore int) (*models.RocketSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshots := r.snapshots[rocketID]
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if snapshots[i].MessageNumber <= atOrBefore {
+			snapshot := copySnapshot(snapshots[i])
+			return &snapshot, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *MemoryRocketRepository) GetRocketSnapshots(ctx context.Context, rocketID models.UUID) ([]models.RocketSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var snapshots []models.RocketSnapshot
+	for _, snapshot := range r.snapshots[rocketID] {
+		snapshots = append(snapshots, copySnapshot(snapshot))
+	}
+	return snapshots, nil
+}
+
+// Event operations
+func (r *MemoryRocketRepository) CreateRocketEvent(ctx context.Context, event *models.RocketEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	messageTime := now
+	if !event.MessageTime.IsZero() {
+		messageTime = event.MessageTime.UTC()
+	}
+
+	// ON CONFLICT (channel, message_number) updates the payload but keeps the processing state
+	key := eventKey{channel: event.Channel, messageNumber: event.MessageNumber}
+	if id, ok := r.eventKeys[key]; ok {
+		stored := r.events[id]
+		stored.MessageType = event.MessageType
+		stored.MessageData = copyRawMessage(event.MessageData)
+		stored.MessageTime = messageTime
+		stored.RequestID = copyString(event.RequestID)
+		stored.TraceParent = copyString(event.TraceParent)
+		stored.ReceivedAt = now
+
+		event.ID = id
+		event.MessageTime = messageTime
+		event.ReceivedAt = now
+		event.Status = models.EventStatusPending
+		return nil
+	}
+
+	r.nextEventID++
+	stored := &models.RocketEvent{
+		ID:            r.nextEventID,
+		Channel:       event.Channel,
+		MessageNumber: event.MessageNumber,
+		MessageType:   event.MessageType,
+		MessageData:   copyRawMessage(event.MessageData),
+		MessageTime:   messageTime,
+		ReceivedAt:    now,
+		Status:        models.EventStatusPending,
+		RequestID:     copyString(event.RequestID),
+		TraceParent:   copyString(event.TraceParent),
+	}
+	r.events[stored.ID] = stored
+	r.eventKeys[key] = stored.ID
+
+	event.ID = stored.ID
+	event.MessageTime = messageTime
+	event.ReceivedAt = now
+	event.Status = models.EventStatusPending
+	return nil
+}
+
+func (r *MemoryRocketRepository) GetRocketEvent(ctx context.Context, id int64) (*models.RocketEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	event, ok := r.events[id]
+	if !ok {
+		return nil, nil
+	}
+	return copyEvent(event), nil
+}
+
+func (r *MemoryRocketRepository) GetPendingEvents(ctx context.Context, limit int) ([]models.RocketEvent, error) {
+	r.mu.RLock()
+	var events []models.RocketEvent
+	for _, event := range r.events {
+		if event.Status == models.EventStatusPending {
+			events = append(events, *copyEvent(event))
+		}
+	}
+	r.mu.RUnlock()
+
+	sortEventsByReceived(events)
+
+	if limit >= 0 && len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+func (r *MemoryRocketRepository) ClaimPendingEvents(ctx context.Context, limit int,
+	claimTimeout time.Duration) ([]models.RocketEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Channels with events still processing are skipped, like in Postgres, unless their
+	// claim timed out
+	now := time.Now()
+	busy := make(map[models.UUID]bool)
+	for _, event := range r.events {
+		if event.Status == models.EventStatusProcessing && !r.claimExpired(event.ID, now, claimTimeout) {
+			busy[event.Channel] = true
+		}
+	}
+
+	var pending []*models.RocketEvent
+	for _, event := range r.events {
+		claimable := event.Status == models.EventStatusPending ||
+			event.Status == models.EventStatusProcessing && r.claimExpired(event.ID, now, claimTimeout)
+		if claimable && !busy[event.Channel] {
+			pending = append(pending, event)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return eventReceivedBefore(pending[i], pending[j]) })
+	if limit >= 0 && len(pending) > limit {
+		pending = pending[:limit]
+	}
+
+	events := make([]models.RocketEvent, 0, len(pending))
+	for _, event := range pending {
+		event.Status = models.EventStatusProcessing
+		r.claimedAt[event.ID] = now
+		events = append(events, *copyEvent(event))
+	}
+	return events, nil
+}
+
+// claimExpired tells whether a processing event was claimed more than claimTimeout
+// before now, the caller holds mu. Events without a claim time count as expired,
+// like a NULL claimed_at in Postgres.
+func (r *MemoryRocketRepository) claimExpired(id int64, now time.Time, claimTimeout time.Duration) bool {
+	claimedAt, ok := r.claimedAt[id]
+	return !ok || claimedAt.Before(now.Add(-claimTimeout))
+}
+
+func (r *MemoryRocketRepository) ReleaseEvents(ctx context.Context, ids []int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	released := 0
+	for _, id := range ids {
+		if event, ok := r.events[id]; ok && event.Status == models.EventStatusProcessing {
+			event.Status = models.EventStatusPending
+			delete(r.claimedAt, id)
+			released++
+		}
+	}
+	return released, nil
+}
+
+func (r *MemoryRocketRepository) ReclaimEvents(ctx context.Context, claimTimeout time.Duration) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	reclaimed := 0
+	for _, event := range r.events {
+		if event.Status == models.EventStatusProcessing && r.claimExpired(event.ID, now, claimTimeout) {
+			event.Status = models.EventStatusPending
+			delete(r.claimedAt, event.ID)
+			reclaimed++
+		}
+	}
+	return reclaimed, nil
+}
+
+func (r *MemoryRocketRepository) ArchiveEvents(ctx context.Context, status string, olderThan time.Duration,
+	limit int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cutoff := time.Now().Add(-olderThan)
+	var old []*models.RocketEvent
+	for _, event := range r.events {
+		if event.Status == status && event.ReceivedAt.Before(cutoff) {
+			old = append(old, event)
+		}
+	}
+	sort.Slice(old, func(i, j int) bool { return eventReceivedBefore(old[i], old[j]) })
+	if limit >= 0 && len(old) > limit {
+		old = old[:limit]
+	}
+
+	for _, event := range old {
+		delete(r.events, event.ID)
+		delete(r.eventKeys, eventKey{channel: event.Channel, messageNumber: event.MessageNumber})
+		delete(r.claimedAt, event.ID)
+		r.archive[event.ID] = event
+	}
+	return len(old), nil
+}
+
+func (r *MemoryRocketRepository) GetProcessedEvents(ctx context.Context, channel models.UUID,
+	afterNumber, upToNumber int) ([]models.RocketEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var events []models.RocketEvent
+	for _, stored := range []map[int64]*models.RocketEvent{r.events, r.archive} {
+		for _, event := range stored {
+			if event.Channel == channel && event.Status == models.EventStatusProcessed &&
+				event.MessageNumber > afterNumber && event.MessageNumber <= upToNumber {
+				events = append(events, *copyEvent(event))
+			}
+		}
+	}
+	sortEventsByReceived(events)
+	return events, nil
+}
+
+func (r *MemoryRocketRepository) GetEventQueueStats(ctx context.Context) (*models.EventQueueStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stats := &models.EventQueueStats{}
+	var oldest time.Time
+	for _, event := range r.events {
+		if event.Status != models.EventStatusPending {
+			continue
+		}
+		stats.Pending++
+		if oldest.IsZero() || event.ReceivedAt.Before(oldest) {
+			oldest = event.ReceivedAt
+		}
+	}
+	if !oldest.IsZero() {
+		stats.OldestPendingAgeSeconds = time.Since(oldest).Seconds()
+	}
+
+	return stats, nil
+}
+
+func (r *MemoryRocketRepository) UpdateEventStatus(ctx context.Context, id int64, status string, errorMessage *string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event, ok := r.events[id]
+	if !ok {
+		return nil // UPDATE of a missing row is not an error
+	}
+
+	event.Status = status
+	event.ErrorMessage = copyString(errorMessage)
+	if status == models.EventStatusProcessing {
+		r.claimedAt[id] = time.Now()
+	} else {
+		delete(r.claimedAt, id)
+	}
+	if status == models.EventStatusProcessed || status == models.EventStatusFailed {
+		now := time.Now()
+		event.ProcessedAt = &now
+	}
+	return nil
+}
+
+func (r *MemoryRocketRepository) MarkEventProcessed(ctx context.Context, id int64) error {
+	return r.UpdateEventStatus(ctx, id, models.EventStatusProcessed, nil)
+}
+
+// copyRocket deep copies a rocket so callers never share state with the store
+func copyRocket(rocket *models.Rocket) *models.Rocket {
+	c := *rocket
+	c.ExplosionReason = copyString(rocket.ExplosionReason)
+	c.Latitude = copyFloat(rocket.Latitude)
+	c.Longitude = copyFloat(rocket.Longitude)
+	c.FuelLevel = copyFloat(rocket.FuelLevel)
+	c.Acceleration = copyFloat(rocket.Acceleration)
+	c.TimeAtMaxSpeed = copyTime(rocket.TimeAtMaxSpeed)
+	c.LastSpeedAt = copyTime(rocket.LastSpeedAt)
+
+	c.Stages = nil
+	for _, stage := range rocket.Stages {
+		stage.SeparatedAt = copyTime(stage.SeparatedAt)
+		c.Stages = append(c.Stages, stage)
+	}
+	c.Payloads = nil
+	for _, payload := range rocket.Payloads {
+		payload.DeployedAt = copyTime(payload.DeployedAt)
+		c.Payloads = append(c.Payloads, payload)
+	}
+	return &c
+}
+
+func copyEvent(event *models.RocketEvent) *models.RocketEvent {
+	c := *event
+	c.MessageData = copyRawMessage(event.MessageData)
+	c.ProcessedAt = copyTime(event.ProcessedAt)
+	c.ErrorMessage = copyString(event.ErrorMessage)
+	c.RequestID = copyString(event.RequestID)
+	c.TraceParent = copyString(event.TraceParent)
+	return &c
+}
+
+func copySnapshot(snapshot models.RocketSnapshot) models.RocketSnapshot {
+	snapshot.State = copyRawMessage(snapshot.State)
+	return snapshot
+}
+
+func copyRawMessage(data json.RawMessage) json.RawMessage {
+	if data == nil {
+		return nil
+	}
+	return append(json.RawMessage(nil), data...)
+}
+
+func copyString(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	c := *value
+	return &c
+}
+
+func copyFloat(value *float64) *float64 {
+	if value == nil {
+		return nil
+	}
+	c := *value
+	return &c
+}
+
+func copyTime(value *time.Time) *time.Time {
+	if value == nil {
+		return nil
+	}
+	c := *value
+	return &c
+}
@@ -0,0 +1,24 @@
+package repository_test
+
+import (
+	"rockets-backend/repository"
+	"testing"
+)
+
+func TestMemoryRocketRepositoryContract(t *testing.T) {
+	testRocketRepositoryContract(t, func(t *testing.T) repository.RocketRepository {
+		return repository.NewMemoryRocketRepository()
+	})
+}
+
+func TestMemoryAuthRepositoryContract(t *testing.T) {
+	testAuthRepositoryContract(t, func(t *testing.T) repository.AuthRepository {
+		return repository.NewMemoryAuthRepository()
+	})
+}
+
+func TestMemorySourceRepositoryContract(t *testing.T) {
+	testSourceRepositoryContract(t, func(t *testing.T) repository.SourceRepository {
+		return repository.NewMemorySourceRepository()
+	})
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"rockets-backend/models"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemorySourceRepository is a concurrency-safe in-memory SourceRepository
+type MemorySourceRepository struct {
+	mu           sync.RWMutex
+	sources      map[int64]*models.MessageSource
+	channels     map[models.UUID]int64
+	nextSourceID int64
+}
+
+func NewMemorySourceRepository() SourceRepository {
+	return &MemorySourceRepository{
+		sources:  make(map[int64]*models.MessageSource),
+		channels: make(map[models.UUID]int64),
+	}
+}
+
+func (r *MemorySourceRepository) CreateSource(ctx context.Context, source *models.MessageSource) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Mirrors the UNIQUE constraint on message_sources.name
+	for _, existing := range r.sources {
+		if existing.Name == source.Name {
+			return fmt.Errorf("failed to create message source: name %s already exists", source.Name)
+		}
+	}
+
+	r.nextSourceID++
+	source.ID = r.nextSourceID
+	source.CreatedAt = time.Now()
+
+	r.sources[source.ID] = copySource(source)
+	return nil
+}
+
+func (r *MemorySourceRepository) GetSourceByName(ctx context.Context, name string) (*models.MessageSource, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, source := range r.sources {
+		if source.Name == name {
+			return copySource(source), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *MemorySourceRepository) GetSources(ctx context.Context) ([]models.MessageSource, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sources := make([]models.MessageSource, 0, len(r.sources))
+	for _, source := range r.sources {
+		sources = append(sources, *copySource(source))
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].ID < sources[j].ID })
+	return sources, nil
+}
+
+func (r *MemorySourceRepository) RevokeSource(ctx context.Context, id int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	source, ok := r.sources[id]
+	if !ok || source.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	source.RevokedAt = &now
+	return true, nil
+}
+
+func (r *MemorySourceRepository) BindChannel(ctx context.Context, channel models.UUID, sourceID int64) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if boundTo, ok := r.channels[channel]; ok {
+		return boundTo, nil
+	}
+	r.channels[channel] = sourceID
+	return sourceID, nil
+}
+
+func (r *MemorySourceRepository) GetChannelSource(ctx context.Context, channel models.UUID) (*int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sourceID, ok := r.channels[channel]
+	if !ok {
+		return nil, nil
+	}
+	return &sourceID, nil
+}
+
+func copySource(source *models.MessageSource) *models.MessageSource {
+	c := *source
+	c.RevokedAt = copyTime(source.RevokedAt)
+	return &c
+}
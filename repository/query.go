@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	pkgContext "rockets-backend/pkg/context"
+	"rockets-backend/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Per-operation deadlines. A deadline already set by the caller wins if it is earlier.
+const (
+	readTimeout  = 5 * time.Second
+	listTimeout  = 15 * time.Second
+	writeTimeout = 5 * time.Second
+)
+
+// maxTaggedRequestIDLength bounds the request ID copied into SQL comments
+const maxTaggedRequestIDLength = 64
+
+// startQuery starts the span and deadline of a repository operation. The returned
+// function ends both and must be deferred. Queries outside of a trace, such as the
+// event processor polling for pending events, are not traced.
+func startQuery(ctx context.Context, operation string, timeout time.Duration) (context.Context, func()) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return context.WithTimeout(ctx, timeout)
+	}
+
+	ctx, span := tracing.StartSpan(ctx, "repository."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.system", "postgresql"), attribute.String("db.operation", operation)),
+	)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+
+	return ctx, func() {
+		cancel()
+		span.End()
+	}
+}
+
+// tagQuery prefixes the query with a comment carrying the request ID and traceparent, so
+// statements in the postgres logs and pg_stat_activity can be traced back to the request
+// that issued them. The request ID comes from a client header, so only characters that
+// cannot end the comment are kept. The traceparent is always generated by the propagator.
+func tagQuery(ctx context.Context, query string) string {
+	var tags string
+	if requestID := sanitizeRequestID(pkgContext.GetRequestID(ctx)); requestID != "" {
+		tags += " requestId=" + requestID
+	}
+	if traceparent := tracing.Traceparent(ctx); traceparent != "" {
+		tags += " traceparent=" + traceparent
+	}
+	if tags == "" {
+		return query
+	}
+	return "/*" + tags + " */ " + query
+}
+
+func sanitizeRequestID(requestID string) string {
+	sanitized := make([]byte, 0, len(requestID))
+	for i := 0; i < len(requestID) && len(sanitized) < maxTaggedRequestIDLength; i++ {
+		c := requestID[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.' || c == ':' {
+			sanitized = append(sanitized, c)
+		}
+	}
+	return string(sanitized)
+}
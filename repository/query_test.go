@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	pkgContext "rockets-backend/pkg/context"
+	"rockets-backend/testutil"
+	"rockets-backend/tracing"
+	"strings"
+	"testing"
+)
+
+func TestTagQuery(t *testing.T) {
+	query := "SELECT 1"
+
+	tests := []struct {
+		name      string
+		requestID string
+		want      string
+	}{
+		{
+			name: "no request ID",
+			want: "SELECT 1",
+		},
+		{
+			name:      "uuid",
+			requestID: "7b0a4c58-4c4e-4a8a-9d0e-0f5e0e4a1b2c",
+			want:      "/* requestId=7b0a4c58-4c4e-4a8a-9d0e-0f5e0e4a1b2c */ SELECT 1",
+		},
+		{
+			name:      "comment terminator is stripped",
+			requestID: "abc*/; DROP TABLE rockets; --",
+			want:      "/* requestId=abcDROPTABLErockets-- */ SELECT 1",
+		},
+		{
+			name:      "only unsafe characters",
+			requestID: "*/",
+			want:      "SELECT 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.requestID != "" {
+				ctx = pkgContext.WithRequestID(ctx, tt.requestID)
+			}
+
+			if got := tagQuery(ctx, query); got != tt.want {
+				t.Errorf("tagQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagQueryTruncatesLongRequestIDs(t *testing.T) {
+	ctx := pkgContext.WithRequestID(context.Background(), strings.Repeat("a", 200))
+
+	got := tagQuery(ctx, "SELECT 1")
+	if want := "/* requestId=" + strings.Repeat("a", maxTaggedRequestIDLength) + " */ SELECT 1"; got != want {
+		t.Errorf("tagQuery() = %q, want %q", got, want)
+	}
+}
+
+func TestTagQueryIncludesTraceparent(t *testing.T) {
+	testutil.SetupTracing(t)
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := pkgContext.WithRequestID(tracing.WithTraceparent(context.Background(), traceparent), "req-1")
+
+	got := tagQuery(ctx, "SELECT 1")
+	if want := "/* requestId=req-1 traceparent=" + traceparent + " */ SELECT 1"; got != want {
+		t.Errorf("tagQuery() = %q, want %q", got, want)
+	}
+}
+
+func TestStartQueryRecordsSpan(t *testing.T) {
+	spans := testutil.SetupTracing(t)
+	ctx, parent := tracing.StartSpan(context.Background(), "parent")
+
+	queryCtx, done := startQuery(ctx, "GetRocket", readTimeout)
+	if _, ok := queryCtx.Deadline(); !ok {
+		t.Fatal("Expected the query context to have a deadline")
+	}
+	done()
+	parent.End()
+
+	span := testutil.FindSpan(t, spans, "repository.GetRocket")
+	testutil.AssertEqual(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+	if queryCtx.Err() == nil {
+		t.Error("Expected the query context to be cancelled once done")
+	}
+}
+
+func TestStartQueryOutsideTraceHasNoSpan(t *testing.T) {
+	spans := testutil.SetupTracing(t)
+
+	_, done := startQuery(context.Background(), "GetPendingEvents", listTimeout)
+	done()
+
+	testutil.AssertEqual(t, 0, len(spans.GetSpans()))
+}
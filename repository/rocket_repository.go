@@ -1,25 +1,48 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"rockets-backend/models"
+	"sort"
+	"strings"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type RocketRepository interface {
 	// Rocket operations
-	GetRocket(id models.UUID) (*models.Rocket, error)
-	GetAllRockets(sortBy string) ([]models.Rocket, error)
-	UpsertRocket(rocket *models.Rocket) error
+	GetRocket(ctx context.Context, id models.UUID) (*models.Rocket, error)
+	GetAllRockets(ctx context.Context, sortBy string, filter models.RocketFilter) ([]models.Rocket, error)
+	UpsertRocket(ctx context.Context, rocket *models.Rocket) (string, error)
+	GetStaleRockets(ctx context.Context, lastUpdatedBefore time.Time) ([]models.Rocket, error)
+	UpdateRocketStatus(ctx context.Context, id models.UUID, fromStatus, toStatus string, lastUpdatedBefore time.Time) (bool, error)
+	GetRocketStats(ctx context.Context) (*models.RocketStats, error)
+
+	// Status history operations
+	CreateStatusChange(ctx context.Context, change *models.RocketStatusChange) error
+	GetStatusHistory(ctx context.Context, rocketID models.UUID) ([]models.RocketStatusChange, error)
+
+	// Snapshot operations
+	CreateRocketSnapshot(ctx context.Context, snapshot *models.RocketSnapshot) error
+	GetLatestRocketSnapshot(ctx context.Context, rocketID models.UUID, atOrBefore int) (*models.RocketSnapshot, error)
+	GetRocketSnapshots(ctx context.Context, rocketID models.UUID) ([]models.RocketSnapshot, error)
 
 	// Event operations
-	CreateRocketEvent(event *models.RocketEvent) error
-	GetRocketEvent(id int64) (*models.RocketEvent, error)
-	GetPendingEvents(limit int) ([]models.RocketEvent, error)
-	UpdateEventStatus(id int64, status string, errorMessage *string) error
-	MarkEventProcessed(id int64) error
+	CreateRocketEvent(ctx context.Context, event *models.RocketEvent) error
+	GetRocketEvent(ctx context.Context, id int64) (*models.RocketEvent, error)
+	GetPendingEvents(ctx context.Context, limit int) ([]models.RocketEvent, error)
+	ClaimPendingEvents(ctx context.Context, limit int, claimTimeout time.Duration) ([]models.RocketEvent, error)
+	ReleaseEvents(ctx context.Context, ids []int64) (int, error)
+	ReclaimEvents(ctx context.Context, claimTimeout time.Duration) (int, error)
+	ArchiveEvents(ctx context.Context, status string, olderThan time.Duration, limit int) (int, error)
+	GetProcessedEvents(ctx context.Context, channel models.UUID, afterNumber, upToNumber int) ([]models.RocketEvent, error)
+	GetEventQueueStats(ctx context.Context) (*models.EventQueueStats, error)
+	UpdateEventStatus(ctx context.Context, id int64, status string, errorMessage *string) error
+	MarkEventProcessed(ctx context.Context, id int64) error
 }
 
 type PostgresRocketRepository struct {
@@ -30,18 +53,50 @@ func NewPostgresRocketRepository(db *sql.DB) RocketRepository {
 	return &PostgresRocketRepository{db: db}
 }
 
-func (r *PostgresRocketRepository) GetRocket(id models.UUID) (*models.Rocket, error) {
-	query := `
-		SELECT id, type, current_speed, mission, status, explosion_reason, 
-		       launch_time, last_updated, last_message_number
-		FROM rockets WHERE id = $1`
+// rocketColumns is the column list shared by every rocket SELECT, in scanRocket order
+const rocketColumns = `id, type, current_speed, mission, status, explosion_reason,
+		       altitude, latitude, longitude, fuel_level,
+		       active_stage, stages, payloads,
+		       peak_speed, time_at_max_speed, average_speed, speed_samples, acceleration, last_speed_at,
+		       launch_time, last_updated, last_message_number`
 
-	rocket := &models.Rocket{}
-	err := r.db.QueryRow(query, id).Scan(
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRocket(row rowScanner, rocket *models.Rocket) error {
+	var stages, payloads []byte
+	err := row.Scan(
 		&rocket.ID, &rocket.Type, &rocket.CurrentSpeed, &rocket.Mission,
-		&rocket.Status, &rocket.ExplosionReason, &rocket.LaunchTime,
-		&rocket.LastUpdated, &rocket.LastMessageNumber,
+		&rocket.Status, &rocket.ExplosionReason,
+		&rocket.Altitude, &rocket.Latitude, &rocket.Longitude, &rocket.FuelLevel,
+		&rocket.ActiveStage, &stages, &payloads,
+		&rocket.PeakSpeed, &rocket.TimeAtMaxSpeed, &rocket.AverageSpeed, &rocket.SpeedSamples,
+		&rocket.Acceleration, &rocket.LastSpeedAt,
+		&rocket.LaunchTime, &rocket.LastUpdated, &rocket.LastMessageNumber,
 	)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(stages, &rocket.Stages); err != nil {
+		return fmt.Errorf("failed to decode stages: %w", err)
+	}
+	if err := json.Unmarshal(payloads, &rocket.Payloads); err != nil {
+		return fmt.Errorf("failed to decode payloads: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRocketRepository) GetRocket(ctx context.Context, id models.UUID) (*models.Rocket, error) {
+	ctx, cancel := startQuery(ctx, "GetRocket", readTimeout)
+	defer cancel()
+
+	query := `SELECT ` + rocketColumns + ` FROM rockets WHERE id = $1`
+
+	rocket := &models.Rocket{}
+	err := scanRocket(r.db.QueryRowContext(ctx, tagQuery(ctx, query), id), rocket)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -53,14 +108,25 @@ func (r *PostgresRocketRepository) GetRocket(id models.UUID) (*models.Rocket, er
 	return rocket, nil
 }
 
-func (r *PostgresRocketRepository) GetAllRockets(sortBy string) ([]models.Rocket, error) {
+func (r *PostgresRocketRepository) GetAllRockets(ctx context.Context, sortBy string, filter models.RocketFilter) ([]models.Rocket, error) {
+	ctx, cancel := startQuery(ctx, "GetAllRockets", listTimeout)
+	defer cancel()
+
 	validSorts := map[string]string{
-		"type":        "type",
-		"speed":       "current_speed",
-		"mission":     "mission",
-		"status":      "status",
-		"launchTime":  "launch_time",
-		"lastUpdated": "last_updated",
+		"type":           "type",
+		"speed":          "current_speed",
+		"mission":        "mission",
+		"status":         "status",
+		"altitude":       "altitude",
+		"latitude":       "latitude",
+		"longitude":      "longitude",
+		"fuelLevel":      "fuel_level",
+		"peakSpeed":      "peak_speed",
+		"averageSpeed":   "average_speed",
+		"acceleration":   "acceleration",
+		"timeAtMaxSpeed": "time_at_max_speed",
+		"launchTime":     "launch_time",
+		"lastUpdated":    "last_updated",
 	}
 
 	orderBy := "last_updated DESC"
@@ -68,12 +134,11 @@ func (r *PostgresRocketRepository) GetAllRockets(sortBy string) ([]models.Rocket
 		orderBy = fmt.Sprintf("%s ASC", sort)
 	}
 
-	query := fmt.Sprintf(`
-		SELECT id, type, current_speed, mission, status, explosion_reason,
-		       launch_time, last_updated, last_message_number
-		FROM rockets ORDER BY %s`, orderBy)
+	where, args := buildRocketFilter(filter)
+
+	query := fmt.Sprintf(`SELECT %s FROM rockets%s ORDER BY %s`, rocketColumns, where, orderBy)
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, tagQuery(ctx, query), args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query rockets: %w", err)
 	}
@@ -82,12 +147,7 @@ func (r *PostgresRocketRepository) GetAllRockets(sortBy string) ([]models.Rocket
 	var rockets []models.Rocket
 	for rows.Next() {
 		rocket := models.Rocket{}
-		err := rows.Scan(
-			&rocket.ID, &rocket.Type, &rocket.CurrentSpeed, &rocket.Mission,
-			&rocket.Status, &rocket.ExplosionReason, &rocket.LaunchTime,
-			&rocket.LastUpdated, &rocket.LastMessageNumber,
-		)
-		if err != nil {
+		if err := scanRocket(rows, &rocket); err != nil {
 			return nil, fmt.Errorf("failed to scan rocket: %w", err)
 		}
 		rockets = append(rockets, rocket)
@@ -96,72 +156,492 @@ func (r *PostgresRocketRepository) GetAllRockets(sortBy string) ([]models.Rocket
 	return rockets, nil
 }
 
-func (r *PostgresRocketRepository) UpsertRocket(rocket *models.Rocket) error {
+// buildRocketFilter turns the non-nil filter fields into a WHERE clause and its positional arguments
+func buildRocketFilter(filter models.RocketFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Status != "" {
+		add("status = $%d", filter.Status)
+	}
+	if filter.MinAltitude != nil {
+		add("altitude >= $%d", *filter.MinAltitude)
+	}
+	if filter.MaxAltitude != nil {
+		add("altitude <= $%d", *filter.MaxAltitude)
+	}
+	if filter.MinLatitude != nil {
+		add("latitude >= $%d", *filter.MinLatitude)
+	}
+	if filter.MaxLatitude != nil {
+		add("latitude <= $%d", *filter.MaxLatitude)
+	}
+	if filter.MinLongitude != nil {
+		add("longitude >= $%d", *filter.MinLongitude)
+	}
+	if filter.MaxLongitude != nil {
+		add("longitude <= $%d", *filter.MaxLongitude)
+	}
+	if filter.MinFuelLevel != nil {
+		add("fuel_level >= $%d", *filter.MinFuelLevel)
+	}
+	if filter.MaxFuelLevel != nil {
+		add("fuel_level <= $%d", *filter.MaxFuelLevel)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// UpsertRocket saves the rocket unless a newer message was already applied, and returns the
+// status it had before, empty for a new rocket. The row stays locked between reading that
+// status and writing, so a concurrent status change is either seen or waits.
+func (r *PostgresRocketRepository) UpsertRocket(ctx context.Context, rocket *models.Rocket) (string, error) {
+	ctx, cancel := startQuery(ctx, "UpsertRocket", writeTimeout)
+	defer cancel()
+
+	stages, err := marshalJSONArray(rocket.Stages)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode stages: %w", err)
+	}
+	payloads, err := marshalJSONArray(rocket.Payloads)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode payloads: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create rocket: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previous sql.NullString
+	err = tx.QueryRowContext(ctx, tagQuery(ctx, `SELECT status FROM rockets WHERE id = $1 FOR UPDATE`), rocket.ID).Scan(&previous)
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("failed to create rocket: %w", err)
+	}
+
 	query := `
 		INSERT INTO rockets (id, type, current_speed, mission, status, explosion_reason,
+		                    altitude, latitude, longitude, fuel_level,
+		                    active_stage, stages, payloads,
+		                    peak_speed, time_at_max_speed, average_speed, speed_samples, acceleration, last_speed_at,
 		                    launch_time, last_updated, last_message_number)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19,
+		        $20, $21, $22)
 		ON CONFLICT (id) DO UPDATE SET
 			type = EXCLUDED.type,
 			current_speed = EXCLUDED.current_speed,
 			mission = EXCLUDED.mission,
 			status = EXCLUDED.status,
 			explosion_reason = EXCLUDED.explosion_reason,
+			altitude = EXCLUDED.altitude,
+			latitude = EXCLUDED.latitude,
+			longitude = EXCLUDED.longitude,
+			fuel_level = EXCLUDED.fuel_level,
+			active_stage = EXCLUDED.active_stage,
+			stages = EXCLUDED.stages,
+			payloads = EXCLUDED.payloads,
+			peak_speed = EXCLUDED.peak_speed,
+			time_at_max_speed = EXCLUDED.time_at_max_speed,
+			average_speed = EXCLUDED.average_speed,
+			speed_samples = EXCLUDED.speed_samples,
+			acceleration = EXCLUDED.acceleration,
+			last_speed_at = EXCLUDED.last_speed_at,
 			launch_time = EXCLUDED.launch_time,
 			last_updated = EXCLUDED.last_updated,
 			last_message_number = EXCLUDED.last_message_number
 		WHERE EXCLUDED.last_message_number > rockets.last_message_number 
 		   OR rockets.last_message_number IS NULL`
 
-	_, err := r.db.Exec(query,
+	_, err = tx.ExecContext(ctx, tagQuery(ctx, query),
 		rocket.ID, rocket.Type, rocket.CurrentSpeed, rocket.Mission,
-		rocket.Status, rocket.ExplosionReason, rocket.LaunchTime,
-		rocket.LastUpdated, rocket.LastMessageNumber,
+		rocket.Status, rocket.ExplosionReason,
+		rocket.Altitude, rocket.Latitude, rocket.Longitude, rocket.FuelLevel,
+		rocket.ActiveStage, stages, payloads,
+		rocket.PeakSpeed, rocket.TimeAtMaxSpeed, rocket.AverageSpeed, rocket.SpeedSamples,
+		rocket.Acceleration, rocket.LastSpeedAt,
+		rocket.LaunchTime, rocket.LastUpdated, rocket.LastMessageNumber,
 	)
 
 	if err != nil {
-		return fmt.Errorf("failed to create rocket: %w", err)
+		return "", fmt.Errorf("failed to create rocket: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to create rocket: %w", err)
 	}
 
-	return nil
+	return previous.String, nil
 }
 
-// Event operations
-func (r *PostgresRocketRepository) CreateRocketEvent(event *models.RocketEvent) error {
+// GetStaleRockets returns active rockets that have not been updated since lastUpdatedBefore
+func (r *PostgresRocketRepository) GetStaleRockets(ctx context.Context, lastUpdatedBefore time.Time) ([]models.Rocket, error) {
+	ctx, cancel := startQuery(ctx, "GetStaleRockets", listTimeout)
+	defer cancel()
+
+	query := `SELECT ` + rocketColumns + ` FROM rockets WHERE status = $1 AND last_updated < $2 ORDER BY last_updated`
+
+	rows, err := r.db.QueryContext(ctx, tagQuery(ctx, query), models.RocketStatusActive, lastUpdatedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stale rockets: %w", err)
+	}
+	defer rows.Close()
+
+	var rockets []models.Rocket
+	for rows.Next() {
+		rocket := models.Rocket{}
+		if err := scanRocket(rows, &rocket); err != nil {
+			return nil, fmt.Errorf("failed to scan rocket: %w", err)
+		}
+		rockets = append(rockets, rocket)
+	}
+
+	return rockets, nil
+}
+
+// UpdateRocketStatus changes the status only if the rocket is still in fromStatus and was last updated
+// before lastUpdatedBefore, reporting whether it did
+func (r *PostgresRocketRepository) UpdateRocketStatus(ctx context.Context, id models.UUID, fromStatus, toStatus string, lastUpdatedBefore time.Time) (bool, error) {
+	ctx, cancel := startQuery(ctx, "UpdateRocketStatus", writeTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, tagQuery(ctx, `
+		UPDATE rockets SET status = $3
+		WHERE id = $1 AND status = $2 AND last_updated < $4`), id, fromStatus, toStatus, lastUpdatedBefore)
+	if err != nil {
+		return false, fmt.Errorf("failed to update rocket status: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to update rocket status: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *PostgresRocketRepository) GetRocketStats(ctx context.Context) (*models.RocketStats, error) {
+	ctx, cancel := startQuery(ctx, "GetRocketStats", listTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, tagQuery(ctx, `SELECT status, COUNT(*) FROM rockets GROUP BY status`))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rocket stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &models.RocketStats{ByStatus: map[string]int{}}
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan rocket stats: %w", err)
+		}
+		stats.ByStatus[status] = count
+		stats.Total += count
+	}
+	stats.LostContact = stats.ByStatus[models.RocketStatusLostContact]
+
+	return stats, nil
+}
+
+// Status history operations
+func (r *PostgresRocketRepository) CreateStatusChange(ctx context.Context, change *models.RocketStatusChange) error {
+	ctx, cancel := startQuery(ctx, "CreateStatusChange", writeTimeout)
+	defer cancel()
+
 	query := `
-		INSERT INTO rocket_events (channel, message_number, message_type, message_data, status)
+		INSERT INTO rocket_status_history (rocket_id, from_status, to_status, reason, message_number)
 		VALUES ($1, $2, $3, $4, $5)
-		ON CONFLICT (channel, message_number) DO UPDATE SET
-			message_type = EXCLUDED.message_type,
-			message_data = EXCLUDED.message_data,
-			received_at = CURRENT_TIMESTAMP
-		RETURNING id, received_at`
+		RETURNING id, changed_at`
+
+	err := r.db.QueryRowContext(ctx, tagQuery(ctx, query),
+		change.RocketID, change.FromStatus, change.ToStatus, change.Reason, change.MessageNumber,
+	).Scan(&change.ID, &change.ChangedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create status change: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresRocketRepository) GetStatusHistory(ctx context.Context, rocketID models.UUID) ([]models.RocketStatusChange, error) {
+	ctx, cancel := startQuery(ctx, "GetStatusHistory", listTimeout)
+	defer cancel()
+
+	query := `
+		SELECT id, rocket_id, from_status, to_status, reason, message_number, changed_at
+		FROM rocket_status_history
+		WHERE rocket_id = $1
+		ORDER BY changed_at, id`
+
+	rows, err := r.db.QueryContext(ctx, tagQuery(ctx, query), rocketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []models.RocketStatusChange
+	for rows.Next() {
+		change := models.RocketStatusChange{}
+		err := rows.Scan(
+			&change.ID, &change.RocketID, &change.FromStatus, &change.ToStatus,
+			&change.Reason, &change.MessageNumber, &change.ChangedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan status change: %w", err)
+		}
+		history = append(history, change)
+	}
+
+	return history, nil
+}
 
-	err := r.db.QueryRow(query,
-		event.Channel, event.MessageNumber, event.MessageType,
-		event.MessageData, models.EventStatusPending,
-	).Scan(&event.ID, &event.ReceivedAt)
+// Snapshot operations
+func (r *PostgresRocketRepository) CreateRocketSnapshot(ctx context.Context, snapshot *models.RocketSnapshot) error {
+	ctx, cancel := startQuery(ctx, "CreateRocketSnapshot", writeTimeout)
+	defer cancel()
 
+	query := `
+		INSERT INTO rocket_snapshots (rocket_id, message_number, state, checksum)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (rocket_id, message_number) DO UPDATE SET
+			state = EXCLUDED.state,
+			checksum = EXCLUDED.checksum,
+			created_at = CURRENT_TIMESTAMP
+		RETURNING created_at`
+
+	err := r.db.QueryRowContext(ctx, tagQuery(ctx, query),
+		snapshot.RocketID, snapshot.MessageNumber, []byte(snapshot.State), snapshot.Checksum,
+	).Scan(&snapshot.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create rocket snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// snapshotColumns is the column list shared by every snapshot SELECT, in scanSnapshot order
+const snapshotColumns = `rocket_id, message_number, state, checksum, created_at`
+
+func scanSnapshot(row rowScanner, snapshot *models.RocketSnapshot) error {
+	var state []byte
+	err := row.Scan(&snapshot.RocketID, &snapshot.MessageNumber, &state, &snapshot.Checksum, &snapshot.CreatedAt)
+	snapshot.State = state
+	return err
+}
+
+func (r *PostgresRocketRepository) GetLatestRocketSnapshot(ctx context.Context, rocketID models.UUID,
+	atOrBefore int) (*models.RocketSnapshot, error) {
+	ctx, cancel := startQuery(ctx, "GetLatestRocketSnapshot", readTimeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + snapshotColumns + `
+		FROM rocket_snapshots
+		WHERE rocket_id = $1 AND message_number <= $2
+		ORDER BY message_number DESC
+		LIMIT 1`
+
+	snapshot := &models.RocketSnapshot{}
+	err := scanSnapshot(r.db.QueryRowContext(ctx, tagQuery(ctx, query), rocketID, atOrBefore), snapshot)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rocket snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+func (r *PostgresRocketRepository) GetRocketSnapshots(ctx context.Context, rocketID models.UUID) ([]models.RocketSnapshot, error) {
+	ctx, cancel := startQuery(ctx, "GetRocketSnapshots", listTimeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + snapshotColumns + `
+		FROM rocket_snapshots
+		WHERE rocket_id = $1
+		ORDER BY message_number`
+
+	rows, err := r.db.QueryContext(ctx, tagQuery(ctx, query), rocketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rocket snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []models.RocketSnapshot
+	for rows.Next() {
+		snapshot := models.RocketSnapshot{}
+		if err := scanSnapshot(rows, &snapshot); err != nil {
+			return nil, fmt.Errorf("failed to scan rocket snapshot: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query rocket snapshots: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// marshalJSONArray encodes a slice for a JSONB array column, storing nil as an empty array
+func marshalJSONArray(value interface{}) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if string(data) == "null" {
+		return []byte("[]"), nil
+	}
+	return data, nil
+}
+
+// Event operations
+
+// CreateRocketEvent stores a pending event. A message already stored for the channel and
+// message number updates that event instead and keeps its processing state. The partitioned
+// rocket_events cannot enforce that uniqueness, rocket_event_keys does.
+func (r *PostgresRocketRepository) CreateRocketEvent(ctx context.Context, event *models.RocketEvent) error {
+	ctx, cancel := startQuery(ctx, "CreateRocketEvent", writeTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create rocket event: %w", err)
 	}
+	defer tx.Rollback()
+
+	// Message times are stored as UTC, a missing message time falls back to the receive time
+	var messageTime sql.NullTime
+	if !event.MessageTime.IsZero() {
+		messageTime = sql.NullTime{Time: event.MessageTime.UTC(), Valid: true}
+	}
+
+	// A concurrent insert of the same message waits on the key until this one commits.
+	// CURRENT_TIMESTAMP is fixed for the transaction, so key and event agree on received_at.
+	key := `
+		INSERT INTO rocket_event_keys (channel, message_number, event_id, received_at)
+		VALUES ($1, $2, nextval('rocket_events_id_seq'), CURRENT_TIMESTAMP)
+		ON CONFLICT (channel, message_number) DO NOTHING
+		RETURNING event_id`
+
+	var id int64
+	err = tx.QueryRowContext(ctx, tagQuery(ctx, key), event.Channel, event.MessageNumber).Scan(&id)
+	switch {
+	case err == sql.ErrNoRows:
+		err = updateDuplicateEvent(ctx, tx, event, messageTime)
+	case err == nil:
+		insert := `
+			INSERT INTO rocket_events (id, channel, message_number, message_type, message_data, message_time,
+			                           received_at, status, request_id, trace_parent)
+			VALUES ($1, $2, $3, $4, $5, COALESCE($6::timestamp, CURRENT_TIMESTAMP), CURRENT_TIMESTAMP, $7, $8, $9)
+			RETURNING id, message_time, received_at`
+
+		err = tx.QueryRowContext(ctx, tagQuery(ctx, insert),
+			id, event.Channel, event.MessageNumber, event.MessageType, event.MessageData, messageTime,
+			models.EventStatusPending, event.RequestID, event.TraceParent,
+		).Scan(&event.ID, &event.MessageTime, &event.ReceivedAt)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create rocket event: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to create rocket event: %w", err)
+	}
 
 	event.Status = models.EventStatusPending
 	return nil
 }
 
-func (r *PostgresRocketRepository) GetRocketEvent(id int64) (*models.RocketEvent, error) {
-	query := `
-		SELECT id, channel, message_number, message_type, message_data, 
-		       received_at, processed_at, status, error_message
-		FROM rocket_events WHERE id = $1`
+// updateDuplicateEvent replaces the payload of the event already stored for the message.
+// It is received again, so it moves to the current partition.
+func updateDuplicateEvent(ctx context.Context, tx *sql.Tx, event *models.RocketEvent, messageTime sql.NullTime) error {
+	var id int64
+	lock := `SELECT event_id FROM rocket_event_keys WHERE channel = $1 AND message_number = $2 FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, tagQuery(ctx, lock), event.Channel, event.MessageNumber).Scan(&id); err != nil {
+		return err
+	}
 
-	event := &models.RocketEvent{}
-	err := r.db.QueryRow(query, id).Scan(
+	update := `
+		UPDATE rocket_events SET
+			message_type = $2,
+			message_data = $3,
+			message_time = COALESCE($4::timestamp, CURRENT_TIMESTAMP),
+			request_id = $5,
+			trace_parent = $6,
+			received_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND ` + eventPartition("$1") + `
+		RETURNING id, message_time, received_at`
+
+	err := tx.QueryRowContext(ctx, tagQuery(ctx, update),
+		id, event.MessageType, event.MessageData, messageTime, event.RequestID, event.TraceParent,
+	).Scan(&event.ID, &event.MessageTime, &event.ReceivedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("event %d of the message key does not exist", id)
+	}
+	if err != nil {
+		return err
+	}
+
+	reset := `UPDATE rocket_event_keys SET received_at = CURRENT_TIMESTAMP WHERE event_id = $1`
+	_, err = tx.ExecContext(ctx, tagQuery(ctx, reset), id)
+	return err
+}
+
+// eventPartition limits an event query to the partition of the event ID in param. The
+// subquery is evaluated first, so Postgres skips the other partitions at execution time.
+func eventPartition(param string) string {
+	return `received_at = (SELECT received_at FROM rocket_event_keys WHERE event_id = ` + param + `)`
+}
+
+// eventPartitions is eventPartition for the array of event IDs in param
+func eventPartitions(param string) string {
+	return `received_at = ANY(ARRAY(SELECT received_at FROM rocket_event_keys WHERE event_id = ANY(` + param + `)))`
+}
+
+// eventColumns is the column list shared by every event SELECT, in scanEvent order
+const eventColumns = `id, channel, message_number, message_type, message_data, message_time,
+		       received_at, processed_at, status, error_message, request_id, trace_parent`
+
+// eventReceivedBefore orders events as they are processed, by arrival and then ID
+func eventReceivedBefore(a, b *models.RocketEvent) bool {
+	if a.ReceivedAt.Equal(b.ReceivedAt) {
+		return a.ID < b.ID
+	}
+	return a.ReceivedAt.Before(b.ReceivedAt)
+}
+
+func sortEventsByReceived(events []models.RocketEvent) {
+	sort.Slice(events, func(i, j int) bool { return eventReceivedBefore(&events[i], &events[j]) })
+}
+
+func scanEvent(row rowScanner, event *models.RocketEvent) error {
+	return row.Scan(
 		&event.ID, &event.Channel, &event.MessageNumber, &event.MessageType,
-		&event.MessageData, &event.ReceivedAt, &event.ProcessedAt,
-		&event.Status, &event.ErrorMessage,
+		&event.MessageData, &event.MessageTime, &event.ReceivedAt, &event.ProcessedAt,
+		&event.Status, &event.ErrorMessage, &event.RequestID, &event.TraceParent,
 	)
+}
+
+func (r *PostgresRocketRepository) GetRocketEvent(ctx context.Context, id int64) (*models.RocketEvent, error) {
+	ctx, cancel := startQuery(ctx, "GetRocketEvent", readTimeout)
+	defer cancel()
+
+	query := `SELECT ` + eventColumns + ` FROM rocket_events WHERE id = $1 AND ` + eventPartition("$1")
+
+	event := &models.RocketEvent{}
+	err := scanEvent(r.db.QueryRowContext(ctx, tagQuery(ctx, query), id), event)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -173,16 +653,18 @@ func (r *PostgresRocketRepository) GetRocketEvent(id int64) (*models.RocketEvent
 	return event, nil
 }
 
-func (r *PostgresRocketRepository) GetPendingEvents(limit int) ([]models.RocketEvent, error) {
+func (r *PostgresRocketRepository) GetPendingEvents(ctx context.Context, limit int) ([]models.RocketEvent, error) {
+	ctx, cancel := startQuery(ctx, "GetPendingEvents", listTimeout)
+	defer cancel()
+
 	query := `
-		SELECT id, channel, message_number, message_type, message_data,
-		       received_at, processed_at, status, error_message
+		SELECT ` + eventColumns + `
 		FROM rocket_events 
 		WHERE status = $1 
-		ORDER BY received_at
+		ORDER BY received_at, id
 		LIMIT $2`
 
-	rows, err := r.db.Query(query, models.EventStatusPending, limit)
+	rows, err := r.db.QueryContext(ctx, tagQuery(ctx, query), models.EventStatusPending, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query pending events: %w", err)
 	}
@@ -191,27 +673,287 @@ func (r *PostgresRocketRepository) GetPendingEvents(limit int) ([]models.RocketE
 	var events []models.RocketEvent
 	for rows.Next() {
 		event := models.RocketEvent{}
-		err := rows.Scan(
-			&event.ID, &event.Channel, &event.MessageNumber, &event.MessageType,
-			&event.MessageData, &event.ReceivedAt, &event.ProcessedAt,
-			&event.Status, &event.ErrorMessage,
+		if err := scanEvent(rows, &event); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// claimLockKey is the advisory lock held while claiming events, so that a claim sees the
+// channels claimed by the one before it
+const claimLockKey int64 = 4_242_002
+
+// ClaimPendingEvents marks up to limit of the oldest pending events as processing and
+// returns them oldest first. Channels with events still processing are skipped, so the
+// events of a channel are only handed to one caller at a time and processed in order.
+// A claim expires after claimTimeout: the event is claimed again and no longer holds
+// back its channel, so an instance that died while processing does not block it.
+func (r *PostgresRocketRepository) ClaimPendingEvents(ctx context.Context, limit int,
+	claimTimeout time.Duration) ([]models.RocketEvent, error) {
+	ctx, cancel := startQuery(ctx, "ClaimPendingEvents", writeTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pending events: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Without the lock two concurrent claims would not see each other's processing events,
+	// and could split the events of a channel
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", claimLockKey); err != nil {
+		return nil, fmt.Errorf("failed to claim pending events: %w", err)
+	}
+
+	// claimed_at is stored without time zone like received_at, a NULL one counts as expired
+	query := `
+		UPDATE rocket_events
+		SET status = $1, claimed_at = LOCALTIMESTAMP
+		WHERE (id, received_at) IN (
+			SELECT id, received_at FROM rocket_events
+			WHERE (status = $2 OR (status = $1 AND (claimed_at IS NULL
+			           OR claimed_at < LOCALTIMESTAMP - $4 * interval '1 millisecond')))
+			  AND channel NOT IN (
+			      SELECT channel FROM rocket_events
+			      WHERE status = $1 AND claimed_at >= LOCALTIMESTAMP - $4 * interval '1 millisecond')
+			ORDER BY received_at, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED)
+		RETURNING ` + eventColumns
+
+	rows, err := tx.QueryContext(ctx, tagQuery(ctx, query), models.EventStatusProcessing, models.EventStatusPending, limit,
+		claimTimeout.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pending events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.RocketEvent
+	for rows.Next() {
+		event := models.RocketEvent{}
+		if err := scanEvent(rows, &event); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to claim pending events: %w", err)
+	}
+	rows.Close()
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to claim pending events: %w", err)
+	}
+
+	// RETURNING does not keep the subquery order
+	sortEventsByReceived(events)
+	return events, nil
+}
+
+// ReleaseEvents returns claimed events that were not processed to pending. Events that
+// are no longer processing are left alone. Returns the number of released events.
+func (r *PostgresRocketRepository) ReleaseEvents(ctx context.Context, ids []int64) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	ctx, cancel := startQuery(ctx, "ReleaseEvents", writeTimeout)
+	defer cancel()
+
+	query := `UPDATE rocket_events SET status = $1, claimed_at = NULL WHERE id = ANY($2) AND status = $3 AND ` +
+		eventPartitions("$2")
+
+	result, err := r.db.ExecContext(ctx, tagQuery(ctx, query), models.EventStatusPending, pq.Array(ids),
+		models.EventStatusProcessing)
+	if err != nil {
+		return 0, fmt.Errorf("failed to release events: %w", err)
+	}
+
+	released, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to release events: %w", err)
+	}
+	return int(released), nil
+}
+
+// ReclaimEvents returns events claimed more than claimTimeout ago that are still
+// processing to pending, their worker is assumed dead. Returns the number of reclaimed events.
+func (r *PostgresRocketRepository) ReclaimEvents(ctx context.Context, claimTimeout time.Duration) (int, error) {
+	ctx, cancel := startQuery(ctx, "ReclaimEvents", writeTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE rocket_events SET status = $1, claimed_at = NULL
+		WHERE status = $2 AND (claimed_at IS NULL OR claimed_at < LOCALTIMESTAMP - $3 * interval '1 millisecond')`
+
+	result, err := r.db.ExecContext(ctx, tagQuery(ctx, query), models.EventStatusPending, models.EventStatusProcessing,
+		claimTimeout.Milliseconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to reclaim events: %w", err)
+	}
+
+	reclaimed, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to reclaim events: %w", err)
+	}
+	return int(reclaimed), nil
+}
+
+// ArchiveEvents moves up to limit events in status received more than olderThan ago
+// from rocket_events to rocket_events_archive, creating the monthly partitions they
+// need. Returns the number of moved events.
+func (r *PostgresRocketRepository) ArchiveEvents(ctx context.Context, status string, olderThan time.Duration,
+	limit int) (int, error) {
+	ctx, cancel := startQuery(ctx, "ArchiveEvents", listTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive events: %w", err)
+	}
+	defer tx.Rollback()
+
+	// received_at is stored without time zone, so the cutoff is computed in the same session time zone
+	query := `
+		SELECT id, received_at FROM rocket_events
+		WHERE status = $1 AND received_at < LOCALTIMESTAMP - $2 * interval '1 millisecond'
+		ORDER BY received_at, id
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED`
+
+	rows, err := tx.QueryContext(ctx, tagQuery(ctx, query), status, olderThan.Milliseconds(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to select events to archive: %w", err)
+	}
+	var ids []int64
+	months := map[time.Time]bool{}
+	for rows.Next() {
+		var id int64
+		var receivedAt time.Time
+		if err := rows.Scan(&id, &receivedAt); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan event to archive: %w", err)
+		}
+		ids = append(ids, id)
+		months[time.Date(receivedAt.Year(), receivedAt.Month(), 1, 0, 0, 0, 0, time.UTC)] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to select events to archive: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	for month := range months {
+		// Identifiers cannot be parameters, the name and bounds only come from the date
+		partition := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS rocket_events_archive_%s PARTITION OF rocket_events_archive
+			FOR VALUES FROM ('%s') TO ('%s')`,
+			month.Format("2006_01"), month.Format("2006-01-02"), month.AddDate(0, 1, 0).Format("2006-01-02"))
+		if _, err := tx.ExecContext(ctx, tagQuery(ctx, partition)); err != nil {
+			return 0, fmt.Errorf("failed to create archive partition: %w", err)
+		}
+	}
+
+	move := `
+		WITH moved AS (
+			DELETE FROM rocket_events WHERE id = ANY($1) AND ` + eventPartitions("$1") + `
+			RETURNING ` + eventColumns + `
 		)
-		if err != nil {
+		INSERT INTO rocket_events_archive (` + eventColumns + `)
+		SELECT ` + eventColumns + ` FROM moved`
+
+	result, err := tx.ExecContext(ctx, tagQuery(ctx, move), pq.Array(ids))
+	if err != nil {
+		return 0, fmt.Errorf("failed to move events to the archive: %w", err)
+	}
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to move events to the archive: %w", err)
+	}
+
+	// Archived messages may be received again as new events
+	if _, err := tx.ExecContext(ctx, tagQuery(ctx, `DELETE FROM rocket_event_keys WHERE event_id = ANY($1)`),
+		pq.Array(ids)); err != nil {
+		return 0, fmt.Errorf("failed to remove archived event keys: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to archive events: %w", err)
+	}
+	return int(moved), nil
+}
+
+// GetProcessedEvents returns the processed events of a channel with a message number in
+// (afterNumber, upToNumber], archived ones included, in the order they were processed
+func (r *PostgresRocketRepository) GetProcessedEvents(ctx context.Context, channel models.UUID,
+	afterNumber, upToNumber int) ([]models.RocketEvent, error) {
+	ctx, cancel := startQuery(ctx, "GetProcessedEvents", listTimeout)
+	defer cancel()
+
+	filter := `WHERE channel = $1 AND status = $2 AND message_number > $3 AND message_number <= $4`
+	query := `
+		SELECT ` + eventColumns + ` FROM rocket_events ` + filter + `
+		UNION ALL
+		SELECT ` + eventColumns + ` FROM rocket_events_archive ` + filter + `
+		ORDER BY received_at, id`
+
+	rows, err := r.db.QueryContext(ctx, tagQuery(ctx, query),
+		channel, models.EventStatusProcessed, afterNumber, upToNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query processed events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.RocketEvent
+	for rows.Next() {
+		event := models.RocketEvent{}
+		if err := scanEvent(rows, &event); err != nil {
 			return nil, fmt.Errorf("failed to scan event: %w", err)
 		}
 		events = append(events, event)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query processed events: %w", err)
+	}
 
 	return events, nil
 }
 
-func (r *PostgresRocketRepository) UpdateEventStatus(id int64, status string, errorMessage *string) error {
+func (r *PostgresRocketRepository) GetEventQueueStats(ctx context.Context) (*models.EventQueueStats, error) {
+	ctx, cancel := startQuery(ctx, "GetEventQueueStats", readTimeout)
+	defer cancel()
+
+	// received_at is stored without time zone, so the age is computed in the same session time zone
+	query := `
+		SELECT COUNT(*), COALESCE(EXTRACT(EPOCH FROM LOCALTIMESTAMP - MIN(received_at)), 0)
+		FROM rocket_events
+		WHERE status = $1`
+
+	stats := &models.EventQueueStats{}
+	err := r.db.QueryRowContext(ctx, tagQuery(ctx, query), models.EventStatusPending).
+		Scan(&stats.Pending, &stats.OldestPendingAgeSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event queue stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+func (r *PostgresRocketRepository) UpdateEventStatus(ctx context.Context, id int64, status string, errorMessage *string) error {
+	ctx, cancel := startQuery(ctx, "UpdateEventStatus", writeTimeout)
+	defer cancel()
+
 	query := `
 		UPDATE rocket_events 
 		SET status = $2::varchar, 
 		    error_message = $3::text, 
-		    processed_at = CASE WHEN $2 IN ('processed', 'failed') THEN CURRENT_TIMESTAMP ELSE processed_at END
-		WHERE id = $1`
+		    processed_at = CASE WHEN $2 IN ('processed', 'failed') THEN CURRENT_TIMESTAMP ELSE processed_at END,
+		    claimed_at = CASE WHEN $2 = 'processing' THEN LOCALTIMESTAMP END
+		WHERE id = $1 AND ` + eventPartition("$1")
 
 	// Convert *string to sql.NullString to handle nil properly
 	var errorParam sql.NullString
@@ -219,7 +961,7 @@ func (r *PostgresRocketRepository) UpdateEventStatus(id int64, status string, er
 		errorParam = sql.NullString{String: *errorMessage, Valid: true}
 	}
 
-	_, err := r.db.Exec(query, id, status, errorParam)
+	_, err := r.db.ExecContext(ctx, tagQuery(ctx, query), id, status, errorParam)
 	if err != nil {
 		return fmt.Errorf("failed to update event status: %w", err)
 	}
@@ -227,6 +969,6 @@ func (r *PostgresRocketRepository) UpdateEventStatus(id int64, status string, er
 	return nil
 }
 
-func (r *PostgresRocketRepository) MarkEventProcessed(id int64) error {
-	return r.UpdateEventStatus(id, models.EventStatusProcessed, nil)
+func (r *PostgresRocketRepository) MarkEventProcessed(ctx context.Context, id int64) error {
+	return r.UpdateEventStatus(ctx, id, models.EventStatusProcessed, nil)
 }
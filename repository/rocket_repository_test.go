@@ -0,0 +1,49 @@
+package repository_test
+
+import (
+	"rockets-backend/repository"
+	"rockets-backend/testutil"
+	"testing"
+)
+
+func TestPostgresRocketRepositoryContractDB(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+
+	testRocketRepositoryContract(t, func(t *testing.T) repository.RocketRepository {
+		db := testutil.SetupTestDB(t)
+		testutil.CleanupTestDB(t, db)
+		t.Cleanup(func() {
+			testutil.CleanupTestDB(t, db)
+			db.Close()
+		})
+		return repository.NewPostgresRocketRepository(db)
+	})
+}
+
+func TestPostgresAuthRepositoryContractDB(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+
+	testAuthRepositoryContract(t, func(t *testing.T) repository.AuthRepository {
+		db := testutil.SetupTestDB(t)
+		testutil.CleanupTestDB(t, db)
+		t.Cleanup(func() {
+			testutil.CleanupTestDB(t, db)
+			db.Close()
+		})
+		return repository.NewPostgresAuthRepository(db)
+	})
+}
+
+func TestPostgresSourceRepositoryContractDB(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+
+	testSourceRepositoryContract(t, func(t *testing.T) repository.SourceRepository {
+		db := testutil.SetupTestDB(t)
+		testutil.CleanupTestDB(t, db)
+		t.Cleanup(func() {
+			testutil.CleanupTestDB(t, db)
+			db.Close()
+		})
+		return repository.NewPostgresSourceRepository(db)
+	})
+}
@@ -0,0 +1,83 @@
+package repository_test
+
+import (
+	"context"
+	"rockets-backend/models"
+	"rockets-backend/repository"
+	"rockets-backend/testutil"
+	"testing"
+)
+
+// testSourceRepositoryContract runs the behaviour every SourceRepository must share
+func testSourceRepositoryContract(t *testing.T, newRepo func(t *testing.T) repository.SourceRepository) {
+	ctx := context.Background()
+
+	t.Run("SourceLifecycle", func(t *testing.T) {
+		repo := newRepo(t)
+
+		source := &models.MessageSource{Name: "ground-station", Secret: "secret"}
+		testutil.AssertNoError(t, repo.CreateSource(ctx, source))
+		testutil.AssertNotEqual(t, int64(0), source.ID)
+
+		duplicate := &models.MessageSource{Name: "ground-station", Secret: "other"}
+		if err := repo.CreateSource(ctx, duplicate); err == nil {
+			t.Fatal("Expected a duplicate name to fail")
+		}
+
+		found, err := repo.GetSourceByName(ctx, "ground-station")
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, source.ID, found.ID)
+		testutil.AssertEqual(t, "secret", found.Secret)
+		if found.RevokedAt != nil {
+			t.Fatal("Expected a new source to be active")
+		}
+
+		missing, err := repo.GetSourceByName(ctx, "missing")
+		testutil.AssertNoError(t, err)
+		if missing != nil {
+			t.Fatalf("Expected nil source, got %+v", missing)
+		}
+
+		revoked, err := repo.RevokeSource(ctx, source.ID)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, true, revoked)
+		revoked, err = repo.RevokeSource(ctx, source.ID)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, false, revoked)
+
+		sources, err := repo.GetSources(ctx)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, len(sources))
+		if sources[0].RevokedAt == nil {
+			t.Fatal("Expected the source to be revoked")
+		}
+	})
+
+	t.Run("ChannelBinding", func(t *testing.T) {
+		repo := newRepo(t)
+
+		first := &models.MessageSource{Name: "first", Secret: "a"}
+		second := &models.MessageSource{Name: "second", Secret: "b"}
+		testutil.AssertNoError(t, repo.CreateSource(ctx, first))
+		testutil.AssertNoError(t, repo.CreateSource(ctx, second))
+
+		channel := "193270a9-c9cf-404a-8f83-838e71d9ae67"
+		boundTo, err := repo.GetChannelSource(ctx, channel)
+		testutil.AssertNoError(t, err)
+		if boundTo != nil {
+			t.Fatalf("Expected an unbound channel, got source %d", *boundTo)
+		}
+
+		// The first source to bind a channel keeps it
+		owner, err := repo.BindChannel(ctx, channel, first.ID)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, first.ID, owner)
+		owner, err = repo.BindChannel(ctx, channel, second.ID)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, first.ID, owner)
+
+		boundTo, err = repo.GetChannelSource(ctx, channel)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, first.ID, *boundTo)
+	})
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"rockets-backend/models"
+)
+
+type SourceRepository interface {
+	// Message source operations
+	CreateSource(ctx context.Context, source *models.MessageSource) error
+	GetSourceByName(ctx context.Context, name string) (*models.MessageSource, error)
+	GetSources(ctx context.Context) ([]models.MessageSource, error)
+	RevokeSource(ctx context.Context, id int64) (bool, error)
+
+	// Channel binding operations
+	BindChannel(ctx context.Context, channel models.UUID, sourceID int64) (int64, error)
+	GetChannelSource(ctx context.Context, channel models.UUID) (*int64, error)
+}
+
+type PostgresSourceRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSourceRepository(db *sql.DB) SourceRepository {
+	return &PostgresSourceRepository{db: db}
+}
+
+const sourceColumns = `id, name, secret, created_at, revoked_at`
+
+func scanSource(row rowScanner, source *models.MessageSource) error {
+	return row.Scan(&source.ID, &source.Name, &source.Secret, &source.CreatedAt, &source.RevokedAt)
+}
+
+func (r *PostgresSourceRepository) CreateSource(ctx context.Context, source *models.MessageSource) error {
+	ctx, cancel := startQuery(ctx, "CreateSource", writeTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO message_sources (name, secret)
+		VALUES ($1, $2)
+		RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, tagQuery(ctx, query), source.Name, source.Secret).Scan(&source.ID, &source.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create message source: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresSourceRepository) GetSourceByName(ctx context.Context, name string) (*models.MessageSource, error) {
+	ctx, cancel := startQuery(ctx, "GetSourceByName", readTimeout)
+	defer cancel()
+
+	query := `SELECT ` + sourceColumns + ` FROM message_sources WHERE name = $1`
+
+	source := &models.MessageSource{}
+	err := scanSource(r.db.QueryRowContext(ctx, tagQuery(ctx, query), name), source)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message source: %w", err)
+	}
+
+	return source, nil
+}
+
+func (r *PostgresSourceRepository) GetSources(ctx context.Context) ([]models.MessageSource, error) {
+	ctx, cancel := startQuery(ctx, "GetSources", listTimeout)
+	defer cancel()
+
+	query := `SELECT ` + sourceColumns + ` FROM message_sources ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, tagQuery(ctx, query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query message sources: %w", err)
+	}
+	defer rows.Close()
+
+	var sources []models.MessageSource
+	for rows.Next() {
+		source := models.MessageSource{}
+		if err := scanSource(rows, &source); err != nil {
+			return nil, fmt.Errorf("failed to scan message source: %w", err)
+		}
+		sources = append(sources, source)
+	}
+
+	return sources, nil
+}
+
+// RevokeSource returns false if the source does not exist or is already revoked
+func (r *PostgresSourceRepository) RevokeSource(ctx context.Context, id int64) (bool, error) {
+	ctx, cancel := startQuery(ctx, "RevokeSource", writeTimeout)
+	defer cancel()
+
+	query := `UPDATE message_sources SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND revoked_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, tagQuery(ctx, query), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke message source: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke message source: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// BindChannel binds an unbound channel to the source and returns the source the channel
+// is bound to, which differs from sourceID when another source got there first
+func (r *PostgresSourceRepository) BindChannel(ctx context.Context, channel models.UUID, sourceID int64) (int64, error) {
+	ctx, cancel := startQuery(ctx, "BindChannel", writeTimeout)
+	defer cancel()
+
+	// The no-op update makes RETURNING report the existing binding on conflict
+	query := `
+		INSERT INTO source_channels (channel, source_id)
+		VALUES ($1, $2)
+		ON CONFLICT (channel) DO UPDATE SET channel = EXCLUDED.channel
+		RETURNING source_id`
+
+	var boundTo int64
+	if err := r.db.QueryRowContext(ctx, tagQuery(ctx, query), channel, sourceID).Scan(&boundTo); err != nil {
+		return 0, fmt.Errorf("failed to bind channel: %w", err)
+	}
+
+	return boundTo, nil
+}
+
+// GetChannelSource returns the source a channel is bound to, or nil if it is unbound
+func (r *PostgresSourceRepository) GetChannelSource(ctx context.Context, channel models.UUID) (*int64, error) {
+	ctx, cancel := startQuery(ctx, "GetChannelSource", readTimeout)
+	defer cancel()
+
+	var sourceID int64
+	err := r.db.QueryRowContext(ctx, tagQuery(ctx, `SELECT source_id FROM source_channels WHERE channel = $1`),
+		channel).Scan(&sourceID)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel source: %w", err)
+	}
+
+	return &sourceID, nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"rockets-backend/models"
+)
+
+var (
+	errPoolStatsUnavailable = errors.New("database pool stats not available")
+	errLeasesUnavailable    = errors.New("leader election not available")
+)
+
+// WithPoolStats exposes the connection pool statistics on the admin API
+func WithPoolStats(stats func() sql.DBStats) Option {
+	return func(s *service) {
+		s.poolStats = stats
+	}
+}
+
+// GetPoolStats returns the current database connection pool statistics
+func (s service) GetPoolStats(ctx context.Context) (*models.PoolStats, error) {
+	if s.poolStats == nil {
+		return nil, errPoolStatsUnavailable
+	}
+
+	stats := s.poolStats()
+	return &models.PoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}, nil
+}
+
+// WithLeaderLeases exposes the leader election of the singleton background jobs on the
+// admin API. instance is the lease holder name of this instance.
+func WithLeaderLeases(instance string, leases func(ctx context.Context) ([]models.LeaderLease, error)) Option {
+	return func(s *service) {
+		s.instance = instance
+		s.leases = leases
+	}
+}
+
+// GetLeaderStatus returns which instance runs each singleton background job
+func (s service) GetLeaderStatus(ctx context.Context) (*models.LeaderStatus, error) {
+	if s.leases == nil {
+		return nil, errLeasesUnavailable
+	}
+
+	leases, err := s.leases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &models.LeaderStatus{Instance: s.instance, Leases: leases}, nil
+}
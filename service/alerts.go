@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"rockets-backend/models"
+	pkgContext "rockets-backend/pkg/context"
+	"strconv"
+
+	"github.com/go-kit/log/level"
+)
+
+var errAlertingDisabled = errors.New("alerting is not configured")
+
+// validAlertOperators maps rule operators to their comparison
+var validAlertOperators = map[string]func(value, threshold float64) bool{
+	">":  func(value, threshold float64) bool { return value > threshold },
+	">=": func(value, threshold float64) bool { return value >= threshold },
+	"<":  func(value, threshold float64) bool { return value < threshold },
+	"<=": func(value, threshold float64) bool { return value <= threshold },
+}
+
+// alertMetricValue reads a rule metric from rocket state, previous is nil for a newly created rocket.
+// The second return value is false when the metric is not available.
+func alertMetricValue(metric string, previous, current *models.Rocket) (float64, bool) {
+	switch metric {
+	case models.AlertMetricSpeed:
+		return float64(current.CurrentSpeed), true
+	case models.AlertMetricSpeedDelta:
+		if previous == nil {
+			return 0, false
+		}
+		return float64(current.CurrentSpeed - previous.CurrentSpeed), true
+	case models.AlertMetricAltitude:
+		return float64(current.Altitude), true
+	case models.AlertMetricFuelLevel:
+		if current.FuelLevel == nil {
+			return 0, false
+		}
+		return *current.FuelLevel, true
+	}
+	return 0, false
+}
+
+func validateAlertRule(rule *models.AlertRule) error {
+	if rule.Name == "" {
+		return fmt.Errorf("alert rule name is required")
+	}
+	switch rule.Metric {
+	case models.AlertMetricSpeed, models.AlertMetricSpeedDelta, models.AlertMetricAltitude, models.AlertMetricFuelLevel:
+	default:
+		return fmt.Errorf("invalid alert metric: %s", rule.Metric)
+	}
+	if _, ok := validAlertOperators[rule.Operator]; !ok {
+		return fmt.Errorf("invalid alert operator: %s", rule.Operator)
+	}
+	return nil
+}
+
+// evaluateAlertRules fires enabled rules matching the state change caused by event.
+// Level metrics only fire when the condition starts holding, speedDelta fires on every matching event.
+func (s service) evaluateAlertRules(ctx context.Context, event *models.RocketEvent, previous, current *models.Rocket) {
+	if s.alerts == nil {
+		return
+	}
+	requestID := pkgContext.GetRequestID(ctx)
+
+	rules, err := s.alerts.GetAlertRules(ctx, true)
+	if err != nil {
+		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to load alert rules", "eventId", event.ID,
+			"error", err)
+		return
+	}
+
+	for _, rule := range rules {
+		if rule.RocketType != nil && *rule.RocketType != current.Type {
+			continue
+		}
+		compare := validAlertOperators[rule.Operator]
+		value, ok := alertMetricValue(rule.Metric, previous, current)
+		if compare == nil || !ok || !compare(value, rule.Threshold) {
+			continue
+		}
+		if rule.Metric != models.AlertMetricSpeedDelta && previous != nil {
+			if before, ok := alertMetricValue(rule.Metric, nil, previous); ok && compare(before, rule.Threshold) {
+				continue
+			}
+		}
+
+		ruleID := rule.ID
+		alert := &models.Alert{
+			RuleID:        &ruleID,
+			RuleName:      rule.Name,
+			RocketID:      current.ID,
+			EventID:       event.ID,
+			MessageNumber: event.MessageNumber,
+			MessageType:   event.MessageType,
+			MessageData:   event.MessageData,
+			Metric:        rule.Metric,
+			Operator:      rule.Operator,
+			Threshold:     rule.Threshold,
+			Value:         value,
+		}
+		if err := s.alerts.CreateAlert(ctx, alert); err != nil {
+			_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to store alert", "ruleId", rule.ID,
+				"eventId", event.ID, "error", err)
+			continue
+		}
+
+		_ = level.Warn(s.logger).Log("requestId", requestID, "msg", "alert fired", "alertId", alert.ID,
+			"rule", rule.Name, "rocketId", current.ID, "metric", rule.Metric, "value", value,
+			"threshold", rule.Threshold)
+	}
+}
+
+func (s service) CreateAlertRule(ctx context.Context, rule models.AlertRule) (*models.AlertRule, error) {
+	requestID := pkgContext.GetRequestID(ctx)
+	if s.alerts == nil {
+		return nil, errAlertingDisabled
+	}
+	if err := validateAlertRule(&rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.alerts.CreateAlertRule(ctx, &rule); err != nil {
+		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to create alert rule", "error", err)
+		return nil, err
+	}
+
+	_ = level.Info(s.logger).Log("requestId", requestID, "msg", "alert rule created", "ruleId", rule.ID,
+		"name", rule.Name, "apiKey", callerName(ctx))
+	s.audit(ctx, "alert_rule.create", "alert_rule/"+strconv.FormatInt(rule.ID, 10))
+	return &rule, nil
+}
+
+func (s service) GetAlertRule(ctx context.Context, id int64) (*models.AlertRule, error) {
+	if s.alerts == nil {
+		return nil, errAlertingDisabled
+	}
+	return s.alerts.GetAlertRule(ctx, id)
+}
+
+func (s service) GetAlertRules(ctx context.Context) ([]models.AlertRule, error) {
+	if s.alerts == nil {
+		return nil, errAlertingDisabled
+	}
+	return s.alerts.GetAlertRules(ctx, false)
+}
+
+// UpdateAlertRule replaces an existing rule, returning nil if it does not exist
+func (s service) UpdateAlertRule(ctx context.Context, rule models.AlertRule) (*models.AlertRule, error) {
+	requestID := pkgContext.GetRequestID(ctx)
+	if s.alerts == nil {
+		return nil, errAlertingDisabled
+	}
+	if err := validateAlertRule(&rule); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.alerts.GetAlertRule(ctx, rule.ID)
+	if err != nil || existing == nil {
+		return nil, err
+	}
+
+	if err := s.alerts.UpdateAlertRule(ctx, &rule); err != nil {
+		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to update alert rule", "ruleId", rule.ID,
+			"error", err)
+		return nil, err
+	}
+
+	_ = level.Info(s.logger).Log("requestId", requestID, "msg", "alert rule updated", "ruleId", rule.ID,
+		"apiKey", callerName(ctx))
+	s.audit(ctx, "alert_rule.update", "alert_rule/"+strconv.FormatInt(rule.ID, 10))
+	return &rule, nil
+}
+
+// DeleteAlertRule removes a rule, fired alerts are kept. Returns false if the rule does not exist.
+func (s service) DeleteAlertRule(ctx context.Context, id int64) (bool, error) {
+	requestID := pkgContext.GetRequestID(ctx)
+	if s.alerts == nil {
+		return false, errAlertingDisabled
+	}
+
+	deleted, err := s.alerts.DeleteAlertRule(ctx, id)
+	if err != nil {
+		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to delete alert rule", "ruleId", id,
+			"error", err)
+		return false, err
+	}
+
+	_ = level.Info(s.logger).Log("requestId", requestID, "msg", "alert rule deleted", "ruleId", id, "found", deleted,
+		"apiKey", callerName(ctx))
+	if deleted {
+		s.audit(ctx, "alert_rule.delete", "alert_rule/"+strconv.FormatInt(id, 10))
+	}
+	return deleted, nil
+}
+
+func (s service) GetAlerts(ctx context.Context, filter models.AlertFilter) ([]models.Alert, error) {
+	if s.alerts == nil {
+		return nil, errAlertingDisabled
+	}
+	return s.alerts.GetAlerts(ctx, filter)
+}
+
+// AcknowledgeAlert marks an open alert as acknowledged, returning nil if it does not exist
+func (s service) AcknowledgeAlert(ctx context.Context, id int64) (*models.Alert, error) {
+	return s.updateAlertStatus(ctx, id, models.AlertStatusAcknowledged, models.AlertStatusOpen)
+}
+
+// ResolveAlert marks an open or acknowledged alert as resolved, returning nil if it does not exist
+func (s service) ResolveAlert(ctx context.Context, id int64) (*models.Alert, error) {
+	return s.updateAlertStatus(ctx, id, models.AlertStatusResolved, models.AlertStatusOpen, models.AlertStatusAcknowledged)
+}
+
+func (s service) updateAlertStatus(ctx context.Context, id int64, status string, allowedFrom ...string) (*models.Alert, error) {
+	requestID := pkgContext.GetRequestID(ctx)
+	if s.alerts == nil {
+		return nil, errAlertingDisabled
+	}
+
+	alert, err := s.alerts.GetAlert(ctx, id)
+	if err != nil || alert == nil {
+		return nil, err
+	}
+
+	allowed := false
+	for _, from := range allowedFrom {
+		allowed = allowed || alert.Status == from
+	}
+	if !allowed {
+		return nil, fmt.Errorf("cannot move alert from %s to %s", alert.Status, status)
+	}
+
+	if err := s.alerts.UpdateAlertStatus(ctx, id, status); err != nil {
+		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to update alert", "alertId", id,
+			"error", err)
+		return nil, err
+	}
+
+	_ = level.Info(s.logger).Log("requestId", requestID, "msg", "alert updated", "alertId", id, "status", status,
+		"apiKey", callerName(ctx))
+	s.audit(ctx, "alert."+status, "alert/"+strconv.FormatInt(id, 10))
+	return s.alerts.GetAlert(ctx, id)
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"rockets-backend/models"
+	pkgContext "rockets-backend/pkg/context"
+	"rockets-backend/repository"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log/level"
+)
+
+var errAuthDisabled = errors.New("api keys are not configured")
+
+// apiKeyTouchInterval limits how often last_used_at is written for a busy key
+const apiKeyTouchInterval = time.Minute
+
+// Audit log page size
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// WithAuthRepository enables API key management, authentication and the audit log
+func WithAuthRepository(auth repository.AuthRepository) Option {
+	return func(s *service) {
+		s.auth = auth
+	}
+}
+
+// generateAPIKey returns a new key of the form rk_<prefix>_<secret> and its prefix.
+// The prefix is stored in clear text to look the key up, the secret only as part of the hash.
+func generateAPIKey() (key string, prefix string, err error) {
+	random := make([]byte, 6+32)
+	if _, err := rand.Read(random); err != nil {
+		return "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	prefix = "rk_" + hex.EncodeToString(random[:6])
+	return prefix + "_" + hex.EncodeToString(random[6:]), prefix, nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// callerName returns the name of the API key in ctx, or "" for unauthenticated calls
+func callerName(ctx context.Context) string {
+	caller, _ := pkgContext.GetCaller(ctx)
+	return caller.KeyName
+}
+
+// AuthenticateAPIKey returns the active API key matching key, or nil if there is none
+func (s service) AuthenticateAPIKey(ctx context.Context, key string) (*models.APIKey, error) {
+	if s.auth == nil {
+		return nil, errAuthDisabled
+	}
+
+	separator := strings.LastIndex(key, "_")
+	if separator <= 0 {
+		return nil, nil
+	}
+
+	apiKey, err := s.auth.GetAPIKeyByPrefix(ctx, key[:separator])
+	if err != nil || apiKey == nil {
+		return nil, err
+	}
+	if apiKey.RevokedAt != nil || subtle.ConstantTimeCompare([]byte(apiKey.KeyHash), []byte(hashAPIKey(key))) != 1 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > apiKeyTouchInterval {
+		if err := s.auth.TouchAPIKey(ctx, apiKey.ID, now); err != nil {
+			_ = level.Warn(s.logger).Log("requestId", pkgContext.GetRequestID(ctx), "msg", "failed to record api key use",
+				"apiKey", apiKey.Name, "error", err)
+		}
+	}
+
+	return apiKey, nil
+}
+
+// CreateAPIKey creates a key for the given role. The returned secret cannot be retrieved again.
+func (s service) CreateAPIKey(ctx context.Context, name string, role string) (*models.CreatedAPIKey, error) {
+	requestID := pkgContext.GetRequestID(ctx)
+	if s.auth == nil {
+		return nil, errAuthDisabled
+	}
+	if name == "" {
+		return nil, fmt.Errorf("api key name is required")
+	}
+	if !models.ValidRole(role) {
+		return nil, fmt.Errorf("invalid api key role: %s", role)
+	}
+
+	key, prefix, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey := models.APIKey{Name: name, Role: role, Prefix: prefix, KeyHash: hashAPIKey(key)}
+	if err := s.auth.CreateAPIKey(ctx, &apiKey); err != nil {
+		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to create api key", "error", err)
+		return nil, err
+	}
+
+	_ = level.Info(s.logger).Log("requestId", requestID, "msg", "api key created", "keyId", apiKey.ID,
+		"name", name, "role", role, "apiKey", callerName(ctx))
+	s.audit(ctx, "apikey.create", "api_key/"+strconv.FormatInt(apiKey.ID, 10))
+
+	return &models.CreatedAPIKey{APIKey: apiKey, Key: key}, nil
+}
+
+func (s service) GetAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	if s.auth == nil {
+		return nil, errAuthDisabled
+	}
+	return s.auth.GetAPIKeys(ctx)
+}
+
+// RevokeAPIKey disables a key. Returns false if the key does not exist or is already revoked.
+func (s service) RevokeAPIKey(ctx context.Context, id int64) (bool, error) {
+	requestID := pkgContext.GetRequestID(ctx)
+	if s.auth == nil {
+		return false, errAuthDisabled
+	}
+
+	revoked, err := s.auth.RevokeAPIKey(ctx, id)
+	if err != nil {
+		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to revoke api key", "keyId", id,
+			"error", err)
+		return false, err
+	}
+
+	_ = level.Info(s.logger).Log("requestId", requestID, "msg", "api key revoked", "keyId", id, "found", revoked,
+		"apiKey", callerName(ctx))
+	if revoked {
+		s.audit(ctx, "apikey.revoke", "api_key/"+strconv.FormatInt(id, 10))
+	}
+	return revoked, nil
+}
+
+// GetAuditLog returns the most recent audit records, limit defaults to 100 and is capped at 1000
+func (s service) GetAuditLog(ctx context.Context, limit int) ([]models.AuditRecord, error) {
+	if s.auth == nil {
+		return nil, errAuthDisabled
+	}
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+	if limit > maxAuditLimit {
+		limit = maxAuditLimit
+	}
+	return s.auth.GetAuditRecords(ctx, limit)
+}
+
+// audit records an administrative change made by the caller in ctx. A failure is
+// logged but does not fail the change, which has already been made.
+func (s service) audit(ctx context.Context, action string, target string) {
+	if s.auth == nil {
+		return
+	}
+
+	record := &models.AuditRecord{Action: action, Target: target}
+	if caller, ok := pkgContext.GetCaller(ctx); ok {
+		record.APIKeyID = &caller.KeyID
+		record.APIKeyName = &caller.KeyName
+	}
+	if requestID := pkgContext.GetRequestID(ctx); requestID != "" {
+		record.RequestID = &requestID
+	}
+
+	if err := s.auth.CreateAuditRecord(ctx, record); err != nil {
+		_ = level.Error(s.logger).Log("requestId", pkgContext.GetRequestID(ctx), "msg", "failed to write audit record",
+			"action", action, "target", target, "apiKey", callerName(ctx), "error", err)
+	}
+}
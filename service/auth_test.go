@@ -0,0 +1,107 @@
+package service_test
+
+import (
+	"context"
+	"rockets-backend/models"
+	pkgContext "rockets-backend/pkg/context"
+	"rockets-backend/repository"
+	"rockets-backend/service"
+	"rockets-backend/testutil"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+)
+
+func newAuthService() (service.Service, repository.AuthRepository) {
+	auth := repository.NewMemoryAuthRepository()
+	svc := service.NewService(log.NewNopLogger(), repository.NewMemoryRocketRepository(),
+		service.WithAuthRepository(auth), service.WithAlertRepository(repository.NewMemoryAlertRepository()))
+	return svc, auth
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	svc, auth := newAuthService()
+	ctx := context.Background()
+
+	created, err := svc.CreateAPIKey(ctx, "telemetry", models.RoleIngest)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, true, strings.HasPrefix(created.Key, created.Prefix+"_"))
+
+	stored, err := auth.GetAPIKeyByPrefix(ctx, created.Prefix)
+	testutil.AssertNoError(t, err)
+	testutil.AssertNotEqual(t, created.Key, stored.KeyHash)
+
+	key, err := svc.AuthenticateAPIKey(ctx, created.Key)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, created.ID, key.ID)
+	testutil.AssertEqual(t, models.RoleIngest, key.Role)
+
+	stored, err = auth.GetAPIKeyByPrefix(ctx, created.Prefix)
+	testutil.AssertNoError(t, err)
+	if stored.LastUsedAt == nil {
+		t.Fatal("Expected the key use to be recorded")
+	}
+
+	for _, invalid := range []string{"", "garbage", created.Prefix + "_wrong", created.Key + "0"} {
+		key, err := svc.AuthenticateAPIKey(ctx, invalid)
+		testutil.AssertNoError(t, err)
+		if key != nil {
+			t.Fatalf("Expected %q to be rejected", invalid)
+		}
+	}
+
+	revoked, err := svc.RevokeAPIKey(ctx, created.ID)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, true, revoked)
+
+	key, err = svc.AuthenticateAPIKey(ctx, created.Key)
+	testutil.AssertNoError(t, err)
+	if key != nil {
+		t.Fatal("Expected a revoked key to be rejected")
+	}
+}
+
+func TestCreateAPIKeyValidation(t *testing.T) {
+	svc, _ := newAuthService()
+	ctx := context.Background()
+
+	if _, err := svc.CreateAPIKey(ctx, "", models.RoleRead); err == nil {
+		t.Fatal("Expected a key without name to fail")
+	}
+	if _, err := svc.CreateAPIKey(ctx, "ops", "superuser"); err == nil {
+		t.Fatal("Expected an unknown role to fail")
+	}
+}
+
+func TestAdministrativeChangesAreAudited(t *testing.T) {
+	svc, _ := newAuthService()
+	ctx := pkgContext.WithRequestID(context.Background(), "req-1")
+	ctx = pkgContext.WithCaller(ctx, pkgContext.Caller{KeyID: 42, KeyName: "ops", Role: models.RoleAdmin})
+
+	rule, err := svc.CreateAlertRule(ctx, models.AlertRule{
+		Name: "fast", Metric: models.AlertMetricSpeed, Operator: ">", Threshold: 1000, Enabled: true,
+	})
+	testutil.AssertNoError(t, err)
+	_, err = svc.DeleteAlertRule(ctx, rule.ID)
+	testutil.AssertNoError(t, err)
+
+	// Keys created from the command line have no caller
+	_, err = svc.CreateAPIKey(context.Background(), "bootstrap", models.RoleAdmin)
+	testutil.AssertNoError(t, err)
+
+	records, err := svc.GetAuditLog(ctx, 0)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 3, len(records))
+
+	testutil.AssertEqual(t, "apikey.create", records[0].Action)
+	if records[0].APIKeyID != nil {
+		t.Fatalf("Expected no caller, got key %d", *records[0].APIKeyID)
+	}
+
+	testutil.AssertEqual(t, "alert_rule.delete", records[1].Action)
+	testutil.AssertEqual(t, "alert_rule.create", records[2].Action)
+	testutil.AssertEqual(t, int64(42), *records[2].APIKeyID)
+	testutil.AssertEqual(t, "ops", *records[2].APIKeyName)
+	testutil.AssertEqual(t, "req-1", *records[2].RequestID)
+}
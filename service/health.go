@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"rockets-backend/models"
+	"sync"
+	"time"
+)
+
+// readinessCheckTimeout bounds each readiness check
+const readinessCheckTimeout = 2 * time.Second
+
+// ReadinessCheck returns an error when a dependency is not ready
+type ReadinessCheck func(ctx context.Context) error
+
+type namedReadinessCheck struct {
+	name  string
+	check ReadinessCheck
+}
+
+// WithReadinessCheck adds a dependency check to the readiness probe
+func WithReadinessCheck(name string, check ReadinessCheck) Option {
+	return func(s *service) {
+		s.readinessChecks = append(s.readinessChecks, namedReadinessCheck{name: name, check: check})
+	}
+}
+
+// WithQueueBacklogThresholds marks the service degraded when more than maxPending events are
+// waiting or the oldest one is older than maxAge. A zero value disables that threshold.
+func WithQueueBacklogThresholds(maxPending int, maxAge time.Duration) Option {
+	return func(s *service) {
+		s.maxPendingEvents = maxPending
+		s.maxPendingAge = maxAge
+	}
+}
+
+// Liveness reports that the process is up and able to serve requests
+func (s service) Liveness(ctx context.Context) *models.HealthReport {
+	return &models.HealthReport{Status: models.HealthStatusOK, Checks: []models.HealthCheck{}}
+}
+
+// Readiness runs all dependency checks concurrently. Any failed check makes the service not
+// ready, a queue backlog above the thresholds only marks it degraded.
+func (s service) Readiness(ctx context.Context) *models.HealthReport {
+	checks := make([]models.HealthCheck, len(s.readinessChecks)+1)
+
+	var wg sync.WaitGroup
+	for i, named := range s.readinessChecks {
+		wg.Add(1)
+		go func(i int, named namedReadinessCheck) {
+			defer wg.Done()
+			checks[i] = runReadinessCheck(ctx, named.name, func(ctx context.Context) (string, map[string]interface{}, error) {
+				return models.HealthStatusOK, nil, named.check(ctx)
+			})
+		}(i, named)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		checks[len(checks)-1] = runReadinessCheck(ctx, "queueBacklog", s.checkQueueBacklog)
+	}()
+	wg.Wait()
+
+	report := &models.HealthReport{Status: models.HealthStatusOK, Checks: checks}
+	for _, check := range checks {
+		switch {
+		case check.Status == models.HealthStatusFailed:
+			report.Status = models.HealthStatusFailed
+		case check.Status == models.HealthStatusDegraded && report.Status == models.HealthStatusOK:
+			report.Status = models.HealthStatusDegraded
+		}
+	}
+
+	return report
+}
+
+func runReadinessCheck(ctx context.Context, name string,
+	check func(ctx context.Context) (string, map[string]interface{}, error)) models.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	status, details, err := check(ctx)
+	result := models.HealthCheck{
+		Name:       name,
+		Status:     status,
+		Details:    details,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = models.HealthStatusFailed
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// checkQueueBacklog reports the pending events against the configured thresholds
+func (s service) checkQueueBacklog(ctx context.Context) (string, map[string]interface{}, error) {
+	stats, err := s.repository.GetEventQueueStats(ctx)
+	if err != nil {
+		return models.HealthStatusFailed, nil, err
+	}
+
+	details := map[string]interface{}{
+		"pending":                 stats.Pending,
+		"oldestPendingAgeSeconds": stats.OldestPendingAgeSeconds,
+	}
+	status := models.HealthStatusOK
+
+	if s.maxPendingEvents > 0 {
+		details["maxPending"] = s.maxPendingEvents
+		if stats.Pending > s.maxPendingEvents {
+			status = models.HealthStatusDegraded
+			details["reason"] = fmt.Sprintf("%d events pending", stats.Pending)
+		}
+	}
+	if s.maxPendingAge > 0 {
+		details["maxPendingAgeSeconds"] = s.maxPendingAge.Seconds()
+		if stats.OldestPendingAgeSeconds > s.maxPendingAge.Seconds() {
+			status = models.HealthStatusDegraded
+			details["reason"] = fmt.Sprintf("oldest pending event is %.0fs old", stats.OldestPendingAgeSeconds)
+		}
+	}
+
+	return status, details, nil
+}
@@ -0,0 +1,85 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"rockets-backend/models"
+	"rockets-backend/service"
+	"rockets-backend/testutil"
+	"testing"
+	"time"
+)
+
+func findCheck(t *testing.T, report *models.HealthReport, name string) models.HealthCheck {
+	t.Helper()
+	for _, check := range report.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("check %s not in report %+v", name, report)
+	return models.HealthCheck{}
+}
+
+func TestReadinessReportsEachCheck(t *testing.T) {
+	f := newRocketFixture(t,
+		service.WithReadinessCheck("database", func(ctx context.Context) error { return nil }),
+		service.WithReadinessCheck("eventProcessor", func(ctx context.Context) error {
+			return errors.New("event processor is not running")
+		}),
+	)
+
+	report := f.svc.Readiness(context.Background())
+	testutil.AssertEqual(t, models.HealthStatusFailed, report.Status)
+	testutil.AssertEqual(t, false, report.Ready())
+	testutil.AssertEqual(t, 3, len(report.Checks))
+
+	testutil.AssertEqual(t, models.HealthStatusOK, findCheck(t, report, "database").Status)
+	failed := findCheck(t, report, "eventProcessor")
+	testutil.AssertEqual(t, models.HealthStatusFailed, failed.Status)
+	testutil.AssertEqual(t, "event processor is not running", failed.Error)
+	testutil.AssertEqual(t, models.HealthStatusOK, findCheck(t, report, "queueBacklog").Status)
+
+	live := f.svc.Liveness(context.Background())
+	testutil.AssertEqual(t, models.HealthStatusOK, live.Status)
+}
+
+func TestReadinessTimesOutSlowChecks(t *testing.T) {
+	f := newRocketFixture(t, service.WithReadinessCheck("database", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	start := time.Now()
+	report := f.svc.Readiness(context.Background())
+	if time.Since(start) > 5*time.Second {
+		t.Fatalf("readiness check was not bounded by a timeout")
+	}
+	testutil.AssertEqual(t, models.HealthStatusFailed, findCheck(t, report, "database").Status)
+}
+
+func TestReadinessDegradedOnQueueBacklog(t *testing.T) {
+	f := newRocketFixture(t, service.WithQueueBacklogThresholds(1, 0))
+
+	for i := 1; i <= 2; i++ {
+		_, err := f.svc.IngestMessage(context.Background(), models.IncomingMessage{
+			Metadata: models.MessageMetadata{
+				Channel:       f.channel,
+				MessageNumber: i,
+				MessageTime:   time.Now(),
+				MessageType:   "RocketSpeedIncreased",
+			},
+			Message: map[string]interface{}{"by": 100},
+		})
+		testutil.AssertNoError(t, err)
+	}
+
+	report := f.svc.Readiness(context.Background())
+	testutil.AssertEqual(t, models.HealthStatusDegraded, report.Status)
+	testutil.AssertEqual(t, true, report.Ready())
+
+	backlog := findCheck(t, report, "queueBacklog")
+	testutil.AssertEqual(t, models.HealthStatusDegraded, backlog.Status)
+	testutil.AssertEqual(t, 2, backlog.Details["pending"])
+	testutil.AssertEqual(t, 1, backlog.Details["maxPending"])
+}
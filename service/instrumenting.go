@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"rockets-backend/models"
+
+	"github.com/go-kit/kit/metrics"
+)
+
+// knownMessageTypes bounds the message_type label, unknown types are counted as "unknown"
+var knownMessageTypes = map[string]bool{
+	"RocketLaunched":          true,
+	"RocketSpeedIncreased":    true,
+	"RocketSpeedDecreased":    true,
+	"RocketExploded":          true,
+	"RocketMissionChanged":    true,
+	"RocketAltitudeChanged":   true,
+	"RocketPositionReported":  true,
+	"RocketFuelLevelReported": true,
+	"RocketStageSeparated":    true,
+	"RocketPayloadDeployed":   true,
+}
+
+// Middleware decorates a Service
+type Middleware func(Service) Service
+
+// InstrumentingMiddleware counts ingested events by message type, rate limited
+// messages by the limit they hit and archived events by status
+func InstrumentingMiddleware(ingested, rateLimited, archived metrics.Counter) Middleware {
+	return func(next Service) Service {
+		return instrumentingService{Service: next, ingested: ingested, rateLimited: rateLimited, archived: archived}
+	}
+}
+
+type instrumentingService struct {
+	Service
+	ingested    metrics.Counter
+	rateLimited metrics.Counter
+	archived    metrics.Counter
+}
+
+func (s instrumentingService) IngestMessage(ctx context.Context, msg models.IncomingMessage) (*models.RocketEvent, error) {
+	event, err := s.Service.IngestMessage(ctx, msg)
+	if err == nil {
+		messageType := msg.Metadata.MessageType
+		if !knownMessageTypes[messageType] {
+			messageType = "unknown"
+		}
+		s.ingested.With("message_type", messageType).Add(1)
+	}
+
+	var limitErr *RateLimitError
+	if errors.As(err, &limitErr) {
+		s.rateLimited.With("scope", limitErr.Scope).Add(1)
+	}
+	return event, err
+}
+
+func (s instrumentingService) RunRetention(ctx context.Context) (*models.RetentionReport, error) {
+	report, err := s.Service.RunRetention(ctx)
+	if report != nil {
+		for _, result := range report.Results {
+			s.archived.With("status", result.Status).Add(float64(result.Archived))
+		}
+	}
+	return report, err
+}
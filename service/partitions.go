@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"rockets-backend/models"
+	pkgContext "rockets-backend/pkg/context"
+
+	"github.com/go-kit/log/level"
+)
+
+var errPartitionsUnavailable = errors.New("event partitions not available")
+
+// PartitionManager creates and drops the daily partitions of rocket_events
+type PartitionManager interface {
+	Ensure(ctx context.Context, daysAhead int) ([]string, error)
+	DropEmpty(ctx context.Context) ([]string, error)
+	List(ctx context.Context) ([]models.EventPartition, error)
+}
+
+// WithEventPartitions maintains the rocket_events partitions, creating them daysAhead
+// days in advance
+func WithEventPartitions(partitions PartitionManager, daysAhead int) Option {
+	return func(s *service) {
+		s.partitions = partitions
+		s.partitionDaysAhead = daysAhead
+	}
+}
+
+// GetEventPartitions returns the partitions of rocket_events
+func (s service) GetEventPartitions(ctx context.Context) ([]models.EventPartition, error) {
+	if s.partitions == nil {
+		return nil, errPartitionsUnavailable
+	}
+	return s.partitions.List(ctx)
+}
+
+// MaintainPartitions creates the partitions of the coming days and drops the past ones
+// that are empty. Both steps run even if the other one fails.
+func (s service) MaintainPartitions(ctx context.Context) (*models.PartitionReport, error) {
+	if s.partitions == nil {
+		return nil, errPartitionsUnavailable
+	}
+	requestID := pkgContext.GetRequestID(ctx)
+
+	report := &models.PartitionReport{}
+	var err error
+	report.Created, err = s.partitions.Ensure(ctx, s.partitionDaysAhead)
+	if err != nil {
+		err = fmt.Errorf("partition maintenance failed: %w", err)
+	}
+	dropped, dropErr := s.partitions.DropEmpty(ctx)
+	report.Dropped = dropped
+	for _, name := range dropped {
+		s.audit(ctx, "partition.drop", name)
+	}
+	if dropErr != nil {
+		err = errors.Join(err, fmt.Errorf("partition maintenance failed: %w", dropErr))
+	}
+
+	if err != nil {
+		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "partition maintenance failed",
+			"created", len(report.Created), "dropped", len(report.Dropped), "error", err)
+		return report, err
+	}
+	if len(report.Created) > 0 || len(report.Dropped) > 0 {
+		_ = level.Info(s.logger).Log("requestId", requestID, "msg", "event partitions maintained",
+			"created", fmt.Sprint(report.Created), "dropped", fmt.Sprint(report.Dropped))
+	}
+	return report, nil
+}
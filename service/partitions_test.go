@@ -0,0 +1,69 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"rockets-backend/models"
+	"rockets-backend/repository"
+	"rockets-backend/service"
+	"rockets-backend/testutil"
+	"testing"
+
+	"github.com/go-kit/log"
+)
+
+type fakePartitions struct {
+	daysAhead int
+	ensureErr error
+}
+
+func (p *fakePartitions) Ensure(ctx context.Context, daysAhead int) ([]string, error) {
+	p.daysAhead = daysAhead
+	if p.ensureErr != nil {
+		return nil, p.ensureErr
+	}
+	return []string{"rocket_events_2026_10_25"}, nil
+}
+
+func (p *fakePartitions) DropEmpty(ctx context.Context) ([]string, error) {
+	return []string{"rocket_events_2026_09_01"}, nil
+}
+
+func (p *fakePartitions) List(ctx context.Context) ([]models.EventPartition, error) {
+	return []models.EventPartition{}, nil
+}
+
+func TestMaintainPartitions(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRocketRepository()
+
+	t.Run("not available", func(t *testing.T) {
+		svc := service.NewService(log.NewNopLogger(), repo)
+		_, err := svc.MaintainPartitions(ctx)
+		if err == nil || err.Error() != "event partitions not available" {
+			t.Fatalf("Expected event partitions not available, got %v", err)
+		}
+	})
+
+	t.Run("creates and drops", func(t *testing.T) {
+		partitions := &fakePartitions{}
+		svc := service.NewService(log.NewNopLogger(), repo, service.WithEventPartitions(partitions, 7))
+
+		report, err := svc.MaintainPartitions(ctx)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 7, partitions.daysAhead)
+		testutil.AssertEqual(t, 1, len(report.Created))
+		testutil.AssertEqual(t, 1, len(report.Dropped))
+	})
+
+	t.Run("drops after a failed create", func(t *testing.T) {
+		partitions := &fakePartitions{ensureErr: errors.New("lock timeout")}
+		svc := service.NewService(log.NewNopLogger(), repo, service.WithEventPartitions(partitions, 7))
+
+		report, err := svc.MaintainPartitions(ctx)
+		if err == nil {
+			t.Fatal("Expected the failed create to be reported")
+		}
+		testutil.AssertEqual(t, 1, len(report.Dropped))
+	})
+}
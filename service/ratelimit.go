@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"rockets-backend/models"
+	pkgContext "rockets-backend/pkg/context"
+	"rockets-backend/ratelimit"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+)
+
+// backlogCheckInterval bounds how often ingestion queries the pending event count
+const backlogCheckInterval = time.Second
+
+// Scopes of a RateLimitError
+const (
+	RateLimitScopeClient  = "client"
+	RateLimitScopeChannel = "channel"
+	RateLimitScopeQueue   = "queue"
+)
+
+// RateLimitError rejects a message that may be sent again after RetryAfter
+type RateLimitError struct {
+	Scope      string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Scope == RateLimitScopeQueue {
+		return "ingestion paused, too many events are waiting to be processed"
+	}
+	return "rate limit exceeded for " + e.Scope
+}
+
+// IngestLimits bounds message ingestion, zero values disable a limit
+type IngestLimits struct {
+	// Client limits each API key, or each client address without authentication.
+	// Keys with their own rate limit use that instead.
+	Client ratelimit.Limit
+	// Channel limits each rocket channel
+	Channel ratelimit.Limit
+	// MaxPendingEvents rejects all messages while more events wait to be processed
+	MaxPendingEvents int
+}
+
+// WithIngestLimits rate limits ingestion and applies backpressure from the event queue
+func WithIngestLimits(limits IngestLimits) Option {
+	return func(s *service) {
+		s.ingestLimits = limits
+		s.limiter = ratelimit.NewLimiter()
+		s.backlog = &backlogGauge{}
+	}
+}
+
+// backlogGauge caches the pending event count between checks
+type backlogGauge struct {
+	mu        sync.Mutex
+	pending   int
+	checkedAt time.Time
+}
+
+// checkIngestLimits returns a RateLimitError when the message must be rejected
+func (s service) checkIngestLimits(ctx context.Context, channel models.UUID) error {
+	if s.limiter == nil {
+		return nil
+	}
+	now := time.Now()
+
+	if max := s.ingestLimits.MaxPendingEvents; max > 0 && s.pendingEvents(ctx, now) > max {
+		return &RateLimitError{Scope: RateLimitScopeQueue, RetryAfter: backlogCheckInterval}
+	}
+
+	if client, limit := s.clientLimit(ctx); client != "" {
+		if allowed, retryAfter := s.limiter.Allow(client, limit, now); !allowed {
+			return &RateLimitError{Scope: RateLimitScopeClient, RetryAfter: retryAfter}
+		}
+	}
+
+	if allowed, retryAfter := s.limiter.Allow("channel:"+channel, s.ingestLimits.Channel, now); !allowed {
+		return &RateLimitError{Scope: RateLimitScopeChannel, RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// clientLimit returns the limiter key and limit of the client sending a message
+func (s service) clientLimit(ctx context.Context) (string, ratelimit.Limit) {
+	if caller, ok := pkgContext.GetCaller(ctx); ok {
+		if caller.RateLimit != nil {
+			return "key:" + strconv.FormatInt(caller.KeyID, 10),
+				ratelimit.Limit{Rate: caller.RateLimit.Rate, Burst: caller.RateLimit.Burst}
+		}
+		return "key:" + strconv.FormatInt(caller.KeyID, 10), s.ingestLimits.Client
+	}
+	if addr := pkgContext.GetClientAddr(ctx); addr != "" {
+		return "addr:" + addr, s.ingestLimits.Client
+	}
+	return "", ratelimit.Limit{}
+}
+
+// pendingEvents returns the cached pending event count, refreshed at most once per
+// backlogCheckInterval. Ingestion is not blocked when the count cannot be queried.
+func (s service) pendingEvents(ctx context.Context, now time.Time) int {
+	s.backlog.mu.Lock()
+	defer s.backlog.mu.Unlock()
+
+	if now.Sub(s.backlog.checkedAt) < backlogCheckInterval {
+		return s.backlog.pending
+	}
+
+	stats, err := s.repository.GetEventQueueStats(ctx)
+	if err != nil {
+		_ = level.Error(s.logger).Log("requestId", pkgContext.GetRequestID(ctx), "msg", "failed to check event backlog",
+			"error", err)
+		return s.backlog.pending
+	}
+
+	s.backlog.pending = stats.Pending
+	s.backlog.checkedAt = now
+	return s.backlog.pending
+}
+
+// SetAPIKeyRateLimit overrides the ingestion rate limit of a key, nil restores the default.
+// A burst below one defaults to one second worth of messages. Returns false if the key does not exist.
+func (s service) SetAPIKeyRateLimit(ctx context.Context, id int64, limit *models.RateLimit) (bool, error) {
+	requestID := pkgContext.GetRequestID(ctx)
+	if s.auth == nil {
+		return false, errAuthDisabled
+	}
+
+	if limit != nil {
+		if limit.Rate <= 0 || math.IsInf(limit.Rate, 0) || math.IsNaN(limit.Rate) {
+			return false, fmt.Errorf("rate must be a positive number of messages per second")
+		}
+		if limit.Burst < 1 {
+			limit.Burst = int(math.Max(1, math.Ceil(limit.Rate)))
+		}
+	}
+
+	updated, err := s.auth.SetAPIKeyRateLimit(ctx, id, limit)
+	if err != nil {
+		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to set api key rate limit", "keyId", id,
+			"error", err)
+		return false, err
+	}
+
+	rate, burst := "default", "default"
+	if limit != nil {
+		rate, burst = strconv.FormatFloat(limit.Rate, 'f', -1, 64), strconv.Itoa(limit.Burst)
+	}
+	_ = level.Info(s.logger).Log("requestId", requestID, "msg", "api key rate limit set", "keyId", id,
+		"found", updated, "rate", rate, "burst", burst, "apiKey", callerName(ctx))
+	if updated {
+		s.audit(ctx, "apikey.rate_limit", "api_key/"+strconv.FormatInt(id, 10))
+	}
+	return updated, nil
+}
@@ -0,0 +1,129 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"rockets-backend/models"
+	pkgContext "rockets-backend/pkg/context"
+	"rockets-backend/ratelimit"
+	"rockets-backend/repository"
+	"rockets-backend/service"
+	"rockets-backend/testutil"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/google/uuid"
+)
+
+// speedMessage returns a message for the channel UUID derived from name
+func speedMessage(name string, number int) models.IncomingMessage {
+	return models.IncomingMessage{
+		Metadata: models.MessageMetadata{
+			Channel:       uuid.NewSHA1(uuid.NameSpaceOID, []byte(name)).String(),
+			MessageNumber: number,
+			MessageTime:   time.Now(),
+			MessageType:   "RocketSpeedIncreased",
+		},
+		Message: map[string]interface{}{"by": 100},
+	}
+}
+
+func assertRateLimited(t *testing.T, err error, scope string) {
+	t.Helper()
+
+	var limitErr *service.RateLimitError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("Expected a rate limit error, got %v", err)
+	}
+	testutil.AssertEqual(t, scope, limitErr.Scope)
+	if limitErr.RetryAfter <= 0 {
+		t.Fatalf("Expected a positive retry delay, got %v", limitErr.RetryAfter)
+	}
+}
+
+func TestIngestRateLimits(t *testing.T) {
+	svc := service.NewService(log.NewNopLogger(), repository.NewMemoryRocketRepository(),
+		service.WithIngestLimits(service.IngestLimits{
+			Client:  ratelimit.Limit{Rate: 0.001, Burst: 3},
+			Channel: ratelimit.Limit{Rate: 0.001, Burst: 2},
+		}))
+
+	client := func(name string, id int64, limit *models.RateLimit) context.Context {
+		return pkgContext.WithCaller(context.Background(), pkgContext.Caller{KeyID: id, KeyName: name, RateLimit: limit})
+	}
+	first := client("first", 1, nil)
+	second := client("second", 2, nil)
+
+	t.Run("per channel", func(t *testing.T) {
+		_, err := svc.IngestMessage(first, speedMessage("channel-a", 1))
+		testutil.AssertNoError(t, err)
+		_, err = svc.IngestMessage(second, speedMessage("channel-a", 2))
+		testutil.AssertNoError(t, err)
+
+		_, err = svc.IngestMessage(second, speedMessage("channel-a", 3))
+		assertRateLimited(t, err, service.RateLimitScopeChannel)
+	})
+
+	t.Run("per client", func(t *testing.T) {
+		// The first client already sent one of its three messages above
+		_, err := svc.IngestMessage(first, speedMessage("channel-b", 1))
+		testutil.AssertNoError(t, err)
+		_, err = svc.IngestMessage(first, speedMessage("channel-c", 1))
+		testutil.AssertNoError(t, err)
+
+		_, err = svc.IngestMessage(first, speedMessage("channel-d", 1))
+		assertRateLimited(t, err, service.RateLimitScopeClient)
+	})
+
+	t.Run("per key override", func(t *testing.T) {
+		generous := client("generous", 3, &models.RateLimit{Rate: 1000, Burst: 1000})
+		for i := 1; i <= 10; i++ {
+			_, err := svc.IngestMessage(generous, speedMessage(fmt.Sprintf("channel-%d", i), 1))
+			testutil.AssertNoError(t, err)
+		}
+	})
+}
+
+func TestIngestBackpressure(t *testing.T) {
+	repo := repository.NewMemoryRocketRepository()
+	svc := service.NewService(log.NewNopLogger(), repo,
+		service.WithIngestLimits(service.IngestLimits{MaxPendingEvents: 2}))
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		testutil.AssertNoError(t, repo.CreateRocketEvent(ctx, &models.RocketEvent{
+			Channel: "backlog", MessageNumber: i, MessageType: "RocketSpeedIncreased",
+			MessageData: []byte(`{"by":100}`), MessageTime: time.Now(), Status: models.EventStatusPending,
+		}))
+	}
+
+	_, err := svc.IngestMessage(ctx, speedMessage("other", 1))
+	assertRateLimited(t, err, service.RateLimitScopeQueue)
+}
+
+func TestSetAPIKeyRateLimit(t *testing.T) {
+	svc, auth := newAuthService()
+	ctx := context.Background()
+
+	created, err := svc.CreateAPIKey(ctx, "telemetry", models.RoleIngest)
+	testutil.AssertNoError(t, err)
+
+	updated, err := svc.SetAPIKeyRateLimit(ctx, created.ID, &models.RateLimit{Rate: 2.5})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, true, updated)
+
+	stored, err := auth.GetAPIKeyByPrefix(ctx, created.Prefix)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, models.RateLimit{Rate: 2.5, Burst: 3}, *stored.RateLimit)
+
+	_, err = svc.SetAPIKeyRateLimit(ctx, created.ID, &models.RateLimit{Rate: -1})
+	if err == nil {
+		t.Fatal("Expected a negative rate to be rejected")
+	}
+
+	updated, err = svc.SetAPIKeyRateLimit(ctx, 999, nil)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, false, updated)
+}
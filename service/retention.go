@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"rockets-backend/models"
+	pkgContext "rockets-backend/pkg/context"
+	"time"
+
+	"github.com/go-kit/log/level"
+)
+
+// defaultRetentionBatch is the number of events archived per transaction when the policy sets none
+const defaultRetentionBatch = 1000
+
+// RetentionPolicy is how long processed and failed events stay in rocket_events before
+// they are moved to the archive, 0 keeps them. Pending events are never archived.
+type RetentionPolicy struct {
+	Processed time.Duration
+	Failed    time.Duration
+	BatchSize int
+}
+
+// WithRetention sets the retention policy applied by RunRetention
+func WithRetention(policy RetentionPolicy) Option {
+	return func(s *service) {
+		s.retention = policy
+	}
+}
+
+// RunRetention archives the events that are older than the retention policy allows,
+// in batches so no transaction holds many rows. A failed run keeps what it moved.
+func (s service) RunRetention(ctx context.Context) (*models.RetentionReport, error) {
+	requestID := pkgContext.GetRequestID(ctx)
+	start := time.Now()
+
+	batchSize := s.retention.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultRetentionBatch
+	}
+
+	report := &models.RetentionReport{Results: []models.RetentionResult{}}
+	var total int
+	var err error
+	for _, rule := range []struct {
+		status string
+		keep   time.Duration
+	}{
+		{models.EventStatusProcessed, s.retention.Processed},
+		{models.EventStatusFailed, s.retention.Failed},
+	} {
+		if rule.keep <= 0 {
+			continue
+		}
+
+		result := models.RetentionResult{Status: rule.status, Cutoff: start.Add(-rule.keep).UTC()}
+		for err == nil {
+			var moved int
+			moved, err = s.repository.ArchiveEvents(ctx, rule.status, rule.keep, batchSize)
+			result.Archived += moved
+			if moved < batchSize {
+				break
+			}
+			err = ctx.Err()
+		}
+		report.Results = append(report.Results, result)
+		total += result.Archived
+		if err != nil {
+			break
+		}
+	}
+	report.DurationMs = time.Since(start).Milliseconds()
+
+	if err != nil {
+		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "event retention failed", "archived", total,
+			"error", err)
+		return report, fmt.Errorf("event retention failed after archiving %d events: %w", total, err)
+	}
+
+	_ = level.Info(s.logger).Log("requestId", requestID, "msg", "event retention finished", "archived", total,
+		"durationMs", report.DurationMs, "apiKey", callerName(ctx))
+	if total > 0 {
+		s.audit(ctx, "retention.run", "rocket_events")
+	}
+
+	return report, nil
+}
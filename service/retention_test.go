@@ -0,0 +1,62 @@
+package service_test
+
+import (
+	"context"
+	"rockets-backend/models"
+	"rockets-backend/repository"
+	"rockets-backend/service"
+	"rockets-backend/testutil"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+)
+
+func TestRunRetention(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRocketRepository()
+
+	statuses := []string{models.EventStatusProcessed, models.EventStatusProcessed, models.EventStatusProcessed,
+		models.EventStatusFailed, models.EventStatusPending}
+	for i, status := range statuses {
+		event := &models.RocketEvent{
+			Channel:       "retention-channel",
+			MessageNumber: i + 1,
+			MessageType:   "RocketSpeedIncreased",
+			MessageData:   []byte(`{"by":100}`),
+		}
+		testutil.AssertNoError(t, repo.CreateRocketEvent(ctx, event))
+		if status != models.EventStatusPending {
+			testutil.AssertNoError(t, repo.UpdateEventStatus(ctx, event.ID, status, nil))
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	t.Run("keeps everything without a policy", func(t *testing.T) {
+		svc := service.NewService(log.NewNopLogger(), repo)
+
+		report, err := svc.RunRetention(ctx)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 0, len(report.Results))
+	})
+
+	t.Run("archives in batches per status", func(t *testing.T) {
+		svc := service.NewService(log.NewNopLogger(), repo, service.WithRetention(service.RetentionPolicy{
+			Processed: time.Millisecond,
+			Failed:    time.Hour,
+			BatchSize: 2,
+		}))
+
+		report, err := svc.RunRetention(ctx)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 2, len(report.Results))
+		testutil.AssertEqual(t, models.EventStatusProcessed, report.Results[0].Status)
+		testutil.AssertEqual(t, 3, report.Results[0].Archived)
+		testutil.AssertEqual(t, models.EventStatusFailed, report.Results[1].Status)
+		testutil.AssertEqual(t, 0, report.Results[1].Archived)
+
+		stats, err := repo.GetEventQueueStats(ctx)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, stats.Pending)
+	})
+}
@@ -2,52 +2,212 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"rockets-backend/models"
 	pkgContext "rockets-backend/pkg/context"
+	"rockets-backend/ratelimit"
 	"rockets-backend/repository"
+	"rockets-backend/tracing"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
 )
 
 type Service interface {
 	HealthCheck() interface{}
+	Liveness(ctx context.Context) *models.HealthReport
+	Readiness(ctx context.Context) *models.HealthReport
 
 	// Message ingestion (fast, async)
 	IngestMessage(ctx context.Context, msg models.IncomingMessage) (*models.RocketEvent, error)
 
 	// Event processing (background)
-	ProcessEvent(ctx context.Context, event *models.RocketEvent) error
+	ProcessEvent(ctx context.Context, event *models.RocketEvent) (ProcessOutcome, error)
 
 	// Rocket queries
 	GetRocket(ctx context.Context, id models.UUID) (*models.Rocket, error)
-	GetAllRockets(ctx context.Context, sortBy string) ([]models.Rocket, error)
+	GetAllRockets(ctx context.Context, sortBy string, filter models.RocketFilter) ([]models.Rocket, error)
+	GetRocketStats(ctx context.Context) (*models.RocketStats, error)
+	GetRocketHistory(ctx context.Context, id models.UUID) ([]models.RocketStatusChange, error)
+	ReplayRocket(ctx context.Context, id models.UUID, messageNumber int) (*models.RocketReplay, error)
+	VerifySnapshots(ctx context.Context, id models.UUID) (*models.SnapshotVerification, error)
+
+	// Stale rocket detection (background)
+	DetectStaleRockets(ctx context.Context, threshold time.Duration) (int, error)
 
 	// Event status
 	GetEventStatus(ctx context.Context, eventID int64) (*models.RocketEvent, error)
+
+	// Alert rules
+	CreateAlertRule(ctx context.Context, rule models.AlertRule) (*models.AlertRule, error)
+	GetAlertRule(ctx context.Context, id int64) (*models.AlertRule, error)
+	GetAlertRules(ctx context.Context) ([]models.AlertRule, error)
+	UpdateAlertRule(ctx context.Context, rule models.AlertRule) (*models.AlertRule, error)
+	DeleteAlertRule(ctx context.Context, id int64) (bool, error)
+
+	// Alerts
+	GetAlerts(ctx context.Context, filter models.AlertFilter) ([]models.Alert, error)
+	AcknowledgeAlert(ctx context.Context, id int64) (*models.Alert, error)
+	ResolveAlert(ctx context.Context, id int64) (*models.Alert, error)
+
+	// Admin
+	GetPoolStats(ctx context.Context) (*models.PoolStats, error)
+	GetWorkerStatus(ctx context.Context) (*models.WorkerStatus, error)
+	UpdateWorkerSettings(ctx context.Context, settings models.WorkerSettings) (*models.WorkerStatus, error)
+	GetLeaderStatus(ctx context.Context) (*models.LeaderStatus, error)
+	RunRetention(ctx context.Context) (*models.RetentionReport, error)
+	GetEventPartitions(ctx context.Context) ([]models.EventPartition, error)
+	MaintainPartitions(ctx context.Context) (*models.PartitionReport, error)
+
+	// API keys and audit log
+	AuthenticateAPIKey(ctx context.Context, key string) (*models.APIKey, error)
+	CreateAPIKey(ctx context.Context, name string, role string) (*models.CreatedAPIKey, error)
+	GetAPIKeys(ctx context.Context) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int64) (bool, error)
+	GetAuditLog(ctx context.Context, limit int) ([]models.AuditRecord, error)
+	SetAPIKeyRateLimit(ctx context.Context, id int64, limit *models.RateLimit) (bool, error)
+
+	// Signed message sources
+	VerifyMessageSignature(ctx context.Context, source string, timestamp string, signature string, body []byte) (*models.MessageSource, error)
+	CreateMessageSource(ctx context.Context, name string) (*models.CreatedMessageSource, error)
+	GetMessageSources(ctx context.Context) ([]models.MessageSource, error)
+	RevokeMessageSource(ctx context.Context, id int64) (bool, error)
+}
+
+// ProcessOutcome tells how ProcessEvent handled an event
+type ProcessOutcome string
+
+const (
+	ProcessOutcomeProcessed ProcessOutcome = "processed"
+	ProcessOutcomeIgnored   ProcessOutcome = "ignored" // out-of-order or duplicate message
+	ProcessOutcomeFailed    ProcessOutcome = "failed"
+)
+
+// rocketStatusTransitions lists the statuses each status may move to
+var rocketStatusTransitions = map[string][]string{
+	models.RocketStatusActive:          {models.RocketStatusExploded, models.RocketStatusMissionComplete, models.RocketStatusLostContact},
+	models.RocketStatusMissionComplete: {models.RocketStatusExploded},
+	models.RocketStatusExploded:        {},
+	models.RocketStatusLostContact:     {models.RocketStatusActive},
+}
+
+// transitionStatus moves the rocket to the given status if the state machine allows it
+func transitionStatus(rocket *models.Rocket, to string) error {
+	if rocket.Status == to {
+		return nil
+	}
+	for _, allowed := range rocketStatusTransitions[rocket.Status] {
+		if allowed == to {
+			rocket.Status = to
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid status transition from %s to %s", rocket.Status, to)
 }
 
 type service struct {
 	logger     log.Logger
 	repository repository.RocketRepository
+	alerts     repository.AlertRepository
+	auth       repository.AuthRepository
+	sources    repository.SourceRepository
+	poolStats  func() sql.DBStats
+
+	workerControl func() WorkerController
+	instance      string
+	leases        func(ctx context.Context) ([]models.LeaderLease, error)
+
+	readinessChecks  []namedReadinessCheck
+	maxPendingEvents int
+	maxPendingAge    time.Duration
+
+	replayWindow time.Duration
+	bindChannels bool
+
+	retention     RetentionPolicy
+	snapshotEvery int
+
+	partitions         PartitionManager
+	partitionDaysAhead int
+
+	ingestLimits IngestLimits
+	limiter      *ratelimit.Limiter
+	backlog      *backlogGauge
+}
+
+// Option configures optional service dependencies
+type Option func(*service)
+
+// WithAlertRepository enables alert rule evaluation and the alert APIs
+func WithAlertRepository(alerts repository.AlertRepository) Option {
+	return func(s *service) {
+		s.alerts = alerts
+	}
 }
 
 func (s service) HealthCheck() interface{} {
 	return map[string]string{"status": "OK", "service": "rockets-backend"}
 }
 
+// ErrInvalidMessage is returned, wrapped with the reason, for messages that can never be
+// stored. Sending them again cannot succeed.
+var ErrInvalidMessage = errors.New("invalid message")
+
+// maxMessageTypeLength is the size of the message_type column
+const maxMessageTypeLength = 50
+
+// validateMessage rejects the metadata the rocket_events columns cannot store
+func validateMessage(msg models.IncomingMessage) error {
+	metadata := msg.Metadata
+	if _, err := uuid.Parse(metadata.Channel); err != nil {
+		return fmt.Errorf("%w: channel must be a UUID, got %q", ErrInvalidMessage, metadata.Channel)
+	}
+	if metadata.MessageNumber < 0 || metadata.MessageNumber > math.MaxInt32 {
+		return fmt.Errorf("%w: messageNumber must be between 0 and %d, got %d", ErrInvalidMessage, math.MaxInt32,
+			metadata.MessageNumber)
+	}
+	if metadata.MessageType == "" {
+		return fmt.Errorf("%w: messageType is required", ErrInvalidMessage)
+	}
+	if len(metadata.MessageType) > maxMessageTypeLength {
+		return fmt.Errorf("%w: messageType must be at most %d characters", ErrInvalidMessage, maxMessageTypeLength)
+	}
+	return nil
+}
+
 // IngestMessage quickly stores the incoming message for async processing
 func (s service) IngestMessage(ctx context.Context, msg models.IncomingMessage) (*models.RocketEvent, error) {
 	requestID := pkgContext.GetRequestID(ctx)
 
+	if err := validateMessage(msg); err != nil {
+		_ = level.Warn(s.logger).Log("requestId", requestID, "msg", "message rejected", "channel", msg.Metadata.Channel,
+			"messageNumber", msg.Metadata.MessageNumber, "error", err)
+		return nil, err
+	}
+
+	if err := s.checkIngestLimits(ctx, msg.Metadata.Channel); err != nil {
+		_ = level.Warn(s.logger).Log("requestId", requestID, "msg", "message rate limited", "channel", msg.Metadata.Channel,
+			"messageNumber", msg.Metadata.MessageNumber, "apiKey", callerName(ctx), "error", err)
+		return nil, err
+	}
+
+	if err := s.checkChannelSource(ctx, msg.Metadata.Channel); err != nil {
+		_ = level.Warn(s.logger).Log("requestId", requestID, "msg", "message rejected", "channel", msg.Metadata.Channel,
+			"messageNumber", msg.Metadata.MessageNumber, "source", sourceName(ctx), "error", err)
+		return nil, err
+	}
+
 	// Convert message to JSON for storage
 	messageData, err := json.Marshal(msg.Message)
 	if err != nil {
 		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to marshal message", "error", err)
-		return nil, fmt.Errorf("failed to marshal message data: %w", err)
+		return nil, fmt.Errorf("%w: failed to marshal message data: %v", ErrInvalidMessage, err)
 	}
 
 	// Create rocket event
@@ -56,11 +216,20 @@ func (s service) IngestMessage(ctx context.Context, msg models.IncomingMessage)
 		MessageNumber: msg.Metadata.MessageNumber,
 		MessageType:   msg.Metadata.MessageType,
 		MessageData:   messageData,
+		MessageTime:   msg.Metadata.MessageTime,
 		Status:        models.EventStatusPending,
 	}
 
+	// Keep the request ID and trace context so the event processor can continue them
+	if requestID != "" {
+		event.RequestID = &requestID
+	}
+	if traceParent := tracing.Traceparent(ctx); traceParent != "" {
+		event.TraceParent = &traceParent
+	}
+
 	// Store event in database
-	err = s.repository.CreateRocketEvent(event)
+	err = s.repository.CreateRocketEvent(ctx, event)
 	if err != nil {
 		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to store event",
 			"channel", msg.Metadata.Channel, "messageNumber", msg.Metadata.MessageNumber, "error", err)
@@ -68,29 +237,28 @@ func (s service) IngestMessage(ctx context.Context, msg models.IncomingMessage)
 	}
 
 	_ = level.Info(s.logger).Log("requestId", requestID, "msg", "message ingested", "eventId", event.ID,
-		"channel", msg.Metadata.Channel, "messageNumber", msg.Metadata.MessageNumber, "type", msg.Metadata.MessageType)
+		"channel", msg.Metadata.Channel, "messageNumber", msg.Metadata.MessageNumber, "type", msg.Metadata.MessageType,
+		"apiKey", callerName(ctx), "source", sourceName(ctx))
 
 	return event, nil
 }
 
 // ProcessEvent processes a single event and updates rocket state
-func (s service) ProcessEvent(ctx context.Context, event *models.RocketEvent) error {
+func (s service) ProcessEvent(ctx context.Context, event *models.RocketEvent) (ProcessOutcome, error) {
 	requestID := pkgContext.GetRequestID(ctx)
 
 	// Mark event as processing
-	err := s.repository.UpdateEventStatus(event.ID, models.EventStatusProcessing, nil)
+	err := s.repository.UpdateEventStatus(ctx, event.ID, models.EventStatusProcessing, nil)
 	if err != nil {
 		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to mark event as processing",
 			"eventId", event.ID, "error", err)
-		return fmt.Errorf("failed to mark event as processing: %w", err)
+		return ProcessOutcomeFailed, fmt.Errorf("failed to mark event as processing: %w", err)
 	}
 
 	// Get existing rocket or prepare new one
-	rocket, err := s.repository.GetRocket(event.Channel)
+	rocket, err := s.repository.GetRocket(ctx, event.Channel)
 	if err != nil {
-		errorMsg := fmt.Sprintf("failed to get rocket: %v", err)
-		s.repository.UpdateEventStatus(event.ID, models.EventStatusFailed, &errorMsg)
-		return fmt.Errorf("failed to get rocket: %w", err)
+		return ProcessOutcomeFailed, s.failEvent(ctx, event, fmt.Errorf("failed to get rocket: %w", err))
 	}
 
 	// Check message ordering - only process if message number is higher
@@ -98,73 +266,172 @@ func (s service) ProcessEvent(ctx context.Context, event *models.RocketEvent) er
 		_ = level.Debug(s.logger).Log("requestId", requestID, "msg", "ignoring out-of-order event",
 			"eventId", event.ID, "channel", event.Channel, "messageNumber", event.MessageNumber,
 			"lastProcessed", rocket.LastMessageNumber)
-		s.repository.MarkEventProcessed(event.ID)
-		return nil
+		if err := s.repository.MarkEventProcessed(ctx, event.ID); err != nil {
+			_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to mark ignored event as processed",
+				"eventId", event.ID, "error", err)
+			return ProcessOutcomeFailed, fmt.Errorf("failed to mark event as processed: %w", err)
+		}
+		return ProcessOutcomeIgnored, nil
+	}
+
+	// Keep the pre-event state around for alert evaluation
+	var previous *models.Rocket
+	if rocket != nil {
+		snapshot := *rocket
+		previous = &snapshot
 	}
 
 	// Process the event based on type
 	if rocket == nil {
-		rocket = &models.Rocket{
-			ID:                event.Channel,
-			Status:            "active",
-			LastMessageNumber: 0,
-		}
+		rocket = newRocket(event.Channel)
 	}
 
-	// Unmarshal message data and process
-	var messageData interface{}
-	if err := json.Unmarshal(event.MessageData, &messageData); err != nil {
-		errorMsg := fmt.Sprintf("failed to unmarshal message data: %v", err)
-		s.repository.UpdateEventStatus(event.ID, models.EventStatusFailed, &errorMsg)
-		return fmt.Errorf("failed to unmarshal message data: %w", err)
+	// Status changes caused by this event, recorded once the rocket is saved
+	var statusChanges []models.RocketStatusChange
+	if previous == nil {
+		statusChanges = append(statusChanges, newStatusChange(rocket.ID, "", rocket.Status, event.MessageType, event.MessageNumber))
 	}
 
-	switch event.MessageType {
-	case "RocketLaunched":
-		err = s.processRocketLaunchedFromData(rocket, event.MessageData)
-	case "RocketSpeedIncreased":
-		err = s.processRocketSpeedIncreasedFromData(rocket, event.MessageData)
-	case "RocketSpeedDecreased":
-		err = s.processRocketSpeedDecreasedFromData(rocket, event.MessageData)
-	case "RocketExploded":
-		err = s.processRocketExplodedFromData(rocket, event.MessageData)
-	case "RocketMissionChanged":
-		err = s.processRocketMissionChangedFromData(rocket, event.MessageData)
-	default:
-		errorMsg := fmt.Sprintf("unknown message type: %s", event.MessageType)
-		s.repository.UpdateEventStatus(event.ID, models.EventStatusFailed, &errorMsg)
-		return fmt.Errorf("unknown message type: %s", event.MessageType)
+	// Any new message means a silent rocket is back in contact
+	if rocket.Status == models.RocketStatusLostContact {
+		rocket.Status = models.RocketStatusActive
+		statusChanges = append(statusChanges, newStatusChange(rocket.ID, models.RocketStatusLostContact,
+			models.RocketStatusActive, "contact restored", event.MessageNumber))
 	}
+	statusBefore := rocket.Status
 
-	if err != nil {
-		errorMsg := fmt.Sprintf("failed to process %s message: %v", event.MessageType, err)
-		s.repository.UpdateEventStatus(event.ID, models.EventStatusFailed, &errorMsg)
-		return fmt.Errorf("failed to process %s message: %w", event.MessageType, err)
+	if err := s.applyEvent(rocket, event); err != nil {
+		return ProcessOutcomeFailed, s.failEvent(ctx, event, err)
 	}
-
-	// Update rocket state
-	rocket.LastMessageNumber = event.MessageNumber
 	rocket.LastUpdated = time.Now()
 
 	// Save rocket (upsert - create or update)
-	err = s.repository.UpsertRocket(rocket)
+	overwritten, err := s.repository.UpsertRocket(ctx, rocket)
 	if err != nil {
-		errorMsg := fmt.Sprintf("failed to save rocket: %v", err)
-		s.repository.UpdateEventStatus(event.ID, models.EventStatusFailed, &errorMsg)
-		return fmt.Errorf("failed to save rocket: %w", err)
+		return ProcessOutcomeFailed, s.failEvent(ctx, event, fmt.Errorf("failed to save rocket: %w", err))
+	}
+
+	// The stale detector flagged the rocket after it was read above, this message restored contact
+	if previous != nil && previous.Status == models.RocketStatusActive && overwritten == models.RocketStatusLostContact {
+		statusChanges = append(statusChanges, newStatusChange(rocket.ID, models.RocketStatusLostContact,
+			models.RocketStatusActive, "contact restored", event.MessageNumber))
 	}
 
+	if rocket.Status != statusBefore {
+		statusChanges = append(statusChanges, newStatusChange(rocket.ID, statusBefore, rocket.Status,
+			event.MessageType, event.MessageNumber))
+	}
+	s.recordStatusChanges(ctx, statusChanges)
+
+	lastBefore := 0
+	if previous != nil {
+		lastBefore = previous.LastMessageNumber
+	}
+	if s.snapshotDue(lastBefore, rocket.LastMessageNumber) {
+		s.saveSnapshot(ctx, rocket)
+	}
+
+	s.evaluateAlertRules(ctx, event, previous, rocket)
+
 	// Mark event as processed
-	err = s.repository.MarkEventProcessed(event.ID)
+	err = s.repository.MarkEventProcessed(ctx, event.ID)
 	if err != nil {
 		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to mark event as processed",
 			"eventId", event.ID, "error", err)
-		return fmt.Errorf("failed to mark event as processed: %w", err)
+		return ProcessOutcomeFailed, fmt.Errorf("failed to mark event as processed: %w", err)
 	}
 
 	_ = level.Info(s.logger).Log("requestId", requestID, "msg", "event processed successfully", "eventId", event.ID,
 		"type", event.MessageType, "channel", event.Channel, "messageNumber", event.MessageNumber)
-	return nil
+	return ProcessOutcomeProcessed, nil
+}
+
+// failEvent records cause as the error of a failed event. If the event cannot be marked
+// failed it stays processing until its claim times out, and that error is returned too.
+func (s service) failEvent(ctx context.Context, event *models.RocketEvent, cause error) error {
+	errorMsg := cause.Error()
+	if err := s.repository.UpdateEventStatus(ctx, event.ID, models.EventStatusFailed, &errorMsg); err != nil {
+		_ = level.Error(s.logger).Log("requestId", pkgContext.GetRequestID(ctx), "msg", "failed to mark event as failed",
+			"eventId", event.ID, "error", err)
+		return errors.Join(cause, fmt.Errorf("failed to mark event as failed: %w", err))
+	}
+	return cause
+}
+
+// newRocket is the state of a rocket before its first message
+func newRocket(id models.UUID) *models.Rocket {
+	return &models.Rocket{
+		ID:                id,
+		Status:            models.RocketStatusActive,
+		LastMessageNumber: 0,
+	}
+}
+
+// newStatusChange builds a history entry, an empty from status marks the initial status
+func newStatusChange(rocketID models.UUID, from, to, reason string, messageNumber int) models.RocketStatusChange {
+	change := models.RocketStatusChange{
+		RocketID: rocketID,
+		ToStatus: to,
+		Reason:   reason,
+	}
+	if from != "" {
+		change.FromStatus = &from
+	}
+	if messageNumber > 0 {
+		change.MessageNumber = &messageNumber
+	}
+	return change
+}
+
+// recordStatusChanges stores status history, failures are logged since rocket state is already saved
+func (s service) recordStatusChanges(ctx context.Context, changes []models.RocketStatusChange) {
+	requestID := pkgContext.GetRequestID(ctx)
+	for i := range changes {
+		if err := s.repository.CreateStatusChange(ctx, &changes[i]); err != nil {
+			_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to record status change",
+				"rocketId", changes[i].RocketID, "toStatus", changes[i].ToStatus, "error", err)
+		}
+	}
+}
+
+// DetectStaleRockets flags active rockets without updates for longer than threshold as lost contact
+func (s service) DetectStaleRockets(ctx context.Context, threshold time.Duration) (int, error) {
+	requestID := pkgContext.GetRequestID(ctx)
+
+	cutoff := time.Now().Add(-threshold)
+	rockets, err := s.repository.GetStaleRockets(ctx, cutoff)
+	if err != nil {
+		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to get stale rockets", "error", err)
+		return 0, err
+	}
+
+	flagged := 0
+	for _, rocket := range rockets {
+		// Conditional on the rocket still being active and stale, so a message saved since the
+		// read above keeps it active. A message being processed saves it as active again,
+		// ProcessEvent records that contact was restored.
+		updated, err := s.repository.UpdateRocketStatus(ctx, rocket.ID, models.RocketStatusActive,
+			models.RocketStatusLostContact, cutoff)
+		if err != nil {
+			_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to flag stale rocket",
+				"rocketId", rocket.ID, "error", err)
+			continue
+		}
+		if !updated {
+			continue
+		}
+
+		flagged++
+		reason := fmt.Sprintf("no messages since %s", rocket.LastUpdated.Format(time.RFC3339))
+		s.recordStatusChanges(ctx, []models.RocketStatusChange{
+			newStatusChange(rocket.ID, models.RocketStatusActive, models.RocketStatusLostContact, reason, 0),
+		})
+
+		_ = level.Warn(s.logger).Log("requestId", requestID, "msg", "rocket lost contact", "rocketId", rocket.ID,
+			"lastUpdated", rocket.LastUpdated)
+	}
+
+	return flagged, nil
 }
 
 // GetEventStatus returns the current status of an event
@@ -172,7 +439,7 @@ func (s service) GetEventStatus(ctx context.Context, eventID int64) (*models.Roc
 	requestID := pkgContext.GetRequestID(ctx)
 	_ = level.Debug(s.logger).Log("requestId", requestID, "msg", "getting event status", "eventId", eventID)
 
-	event, err := s.repository.GetRocketEvent(eventID)
+	event, err := s.repository.GetRocketEvent(ctx, eventID)
 	if err != nil {
 		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to get event", "eventId", eventID,
 			"error", err)
@@ -185,17 +452,119 @@ func (s service) GetEventStatus(ctx context.Context, eventID int64) (*models.Roc
 }
 
 // Processing methods that work with raw JSON data (for async event processing)
-func (s service) processRocketLaunchedFromData(rocket *models.Rocket, messageData json.RawMessage) error {
+// speedMessageTypes are the message types that produce a speed sample for the derived kinematics
+var speedMessageTypes = map[string]bool{
+	"RocketLaunched":       true,
+	"RocketSpeedIncreased": true,
+	"RocketSpeedDecreased": true,
+}
+
+// applyEvent folds an event into the rocket state. Event processing and replay share it,
+// so a replayed rocket ends up in the state the processor stored.
+func (s service) applyEvent(rocket *models.Rocket, event *models.RocketEvent) error {
+	var messageData interface{}
+	if err := json.Unmarshal(event.MessageData, &messageData); err != nil {
+		return fmt.Errorf("failed to unmarshal message data: %w", err)
+	}
+
+	speedBefore := rocket.CurrentSpeed
+
+	var err error
+	switch event.MessageType {
+	case "RocketLaunched":
+		err = s.processRocketLaunchedFromData(rocket, event.MessageData, event.MessageTime)
+	case "RocketSpeedIncreased":
+		err = s.processRocketSpeedIncreasedFromData(rocket, event.MessageData)
+	case "RocketSpeedDecreased":
+		err = s.processRocketSpeedDecreasedFromData(rocket, event.MessageData)
+	case "RocketExploded":
+		err = s.processRocketExplodedFromData(rocket, event.MessageData)
+	case "RocketMissionChanged":
+		err = s.processRocketMissionChangedFromData(rocket, event.MessageData)
+	case "RocketAltitudeChanged":
+		err = s.processRocketAltitudeChangedFromData(rocket, event.MessageData)
+	case "RocketPositionReported":
+		err = s.processRocketPositionReportedFromData(rocket, event.MessageData)
+	case "RocketFuelLevelReported":
+		err = s.processRocketFuelLevelReportedFromData(rocket, event.MessageData)
+	case "RocketStageSeparated":
+		err = s.processRocketStageSeparatedFromData(rocket, event.MessageData, event.MessageTime)
+	case "RocketPayloadDeployed":
+		err = s.processRocketPayloadDeployedFromData(rocket, event.MessageData, event.MessageTime)
+	default:
+		return fmt.Errorf("unknown message type: %s", event.MessageType)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to process %s message: %w", event.MessageType, err)
+	}
+
+	if speedMessageTypes[event.MessageType] {
+		updateKinematics(rocket, speedBefore, event.MessageTime)
+	}
+
+	rocket.LastMessageNumber = event.MessageNumber
+	return nil
+}
+
+// updateKinematics folds the rocket's current speed, observed at the given message time, into its
+// peak, average and acceleration
+func updateKinematics(rocket *models.Rocket, previousSpeed int, at time.Time) {
+	if rocket.LastSpeedAt != nil {
+		rocket.Acceleration = nil
+		if elapsed := at.Sub(*rocket.LastSpeedAt).Seconds(); elapsed > 0 {
+			acceleration := float64(rocket.CurrentSpeed-previousSpeed) / elapsed
+			rocket.Acceleration = &acceleration
+		}
+	}
+
+	rocket.SpeedSamples++
+	rocket.AverageSpeed += (float64(rocket.CurrentSpeed) - rocket.AverageSpeed) / float64(rocket.SpeedSamples)
+
+	if rocket.SpeedSamples == 1 || rocket.CurrentSpeed > rocket.PeakSpeed {
+		rocket.PeakSpeed = rocket.CurrentSpeed
+		rocket.TimeAtMaxSpeed = &at
+	}
+	rocket.LastSpeedAt = &at
+}
+
+func (s service) processRocketLaunchedFromData(rocket *models.Rocket, messageData json.RawMessage, messageTime time.Time) error {
 	var payload models.RocketLaunchedMessage
 	if err := json.Unmarshal(messageData, &payload); err != nil {
 		return fmt.Errorf("failed to parse RocketLaunched payload: %w", err)
 	}
 
+	// Exploded and completed rockets stay that way, only an active rocket can be launched again
+	if err := transitionStatus(rocket, models.RocketStatusActive); err != nil {
+		return err
+	}
+
 	rocket.Type = payload.Type
 	rocket.CurrentSpeed = payload.LaunchSpeed
 	rocket.Mission = payload.Mission
-	rocket.LaunchTime = time.Now() // Use current time since we don't have message time in stored data
-	rocket.Status = "active"
+	rocket.LaunchTime = messageTime
+
+	// A (re)launch starts the kinematics from scratch
+	rocket.PeakSpeed = 0
+	rocket.TimeAtMaxSpeed = nil
+	rocket.AverageSpeed = 0
+	rocket.SpeedSamples = 0
+	rocket.Acceleration = nil
+	rocket.LastSpeedAt = nil
+
+	rocket.Stages = nil
+	rocket.ActiveStage = 0
+	for i := 1; i <= payload.Stages; i++ {
+		rocket.Stages = append(rocket.Stages, models.RocketStage{Number: i})
+	}
+	if payload.Stages > 0 {
+		rocket.ActiveStage = 1
+	}
+
+	rocket.Payloads = nil
+	for _, name := range payload.Payloads {
+		rocket.Payloads = append(rocket.Payloads, models.RocketPayload{Name: name})
+	}
 
 	return nil
 }
@@ -229,7 +598,9 @@ func (s service) processRocketExplodedFromData(rocket *models.Rocket, messageDat
 		return fmt.Errorf("failed to parse RocketExploded payload: %w", err)
 	}
 
-	rocket.Status = "exploded"
+	if err := transitionStatus(rocket, models.RocketStatusExploded); err != nil {
+		return err
+	}
 	rocket.ExplosionReason = &payload.Reason
 	rocket.CurrentSpeed = 0
 
@@ -246,11 +617,115 @@ func (s service) processRocketMissionChangedFromData(rocket *models.Rocket, mess
 	return nil
 }
 
+func (s service) processRocketStageSeparatedFromData(rocket *models.Rocket, messageData json.RawMessage, messageTime time.Time) error {
+	var payload models.RocketStageSeparatedMessage
+	if err := json.Unmarshal(messageData, &payload); err != nil {
+		return fmt.Errorf("failed to parse RocketStageSeparated payload: %w", err)
+	}
+
+	if payload.Stage != rocket.ActiveStage {
+		return fmt.Errorf("stage %d is not the active stage (active: %d)", payload.Stage, rocket.ActiveStage)
+	}
+
+	for i := range rocket.Stages {
+		if rocket.Stages[i].Number == payload.Stage {
+			rocket.Stages[i].SeparatedAt = &messageTime
+		}
+	}
+
+	// The next stage takes over, 0 once the last stage has separated
+	rocket.ActiveStage = 0
+	if payload.Stage < len(rocket.Stages) {
+		rocket.ActiveStage = payload.Stage + 1
+	}
+	return nil
+}
+
+func (s service) processRocketPayloadDeployedFromData(rocket *models.Rocket, messageData json.RawMessage, messageTime time.Time) error {
+	var payload models.RocketPayloadDeployedMessage
+	if err := json.Unmarshal(messageData, &payload); err != nil {
+		return fmt.Errorf("failed to parse RocketPayloadDeployed payload: %w", err)
+	}
+
+	var deployed *models.RocketPayload
+	for i := range rocket.Payloads {
+		if rocket.Payloads[i].Name == payload.Payload {
+			deployed = &rocket.Payloads[i]
+		}
+	}
+	if deployed == nil {
+		return fmt.Errorf("unknown payload: %s", payload.Payload)
+	}
+	if deployed.Deployed {
+		return fmt.Errorf("payload already deployed: %s", payload.Payload)
+	}
+
+	deployed.Deployed = true
+	deployed.DeployedAt = &messageTime
+
+	for _, p := range rocket.Payloads {
+		if !p.Deployed {
+			return nil
+		}
+	}
+	return transitionStatus(rocket, models.RocketStatusMissionComplete)
+}
+
+func (s service) processRocketAltitudeChangedFromData(rocket *models.Rocket, messageData json.RawMessage) error {
+	var payload models.RocketAltitudeChangedMessage
+	if err := json.Unmarshal(messageData, &payload); err != nil {
+		return fmt.Errorf("failed to parse RocketAltitudeChanged payload: %w", err)
+	}
+
+	if payload.Altitude < 0 {
+		return fmt.Errorf("invalid altitude: %d", payload.Altitude)
+	}
+
+	rocket.Altitude = payload.Altitude
+	return nil
+}
+
+func (s service) processRocketPositionReportedFromData(rocket *models.Rocket, messageData json.RawMessage) error {
+	var payload models.RocketPositionReportedMessage
+	if err := json.Unmarshal(messageData, &payload); err != nil {
+		return fmt.Errorf("failed to parse RocketPositionReported payload: %w", err)
+	}
+
+	if payload.Latitude < -90 || payload.Latitude > 90 {
+		return fmt.Errorf("invalid latitude: %v", payload.Latitude)
+	}
+	if payload.Longitude < -180 || payload.Longitude > 180 {
+		return fmt.Errorf("invalid longitude: %v", payload.Longitude)
+	}
+	if payload.Altitude < 0 {
+		return fmt.Errorf("invalid altitude: %d", payload.Altitude)
+	}
+
+	rocket.Latitude = &payload.Latitude
+	rocket.Longitude = &payload.Longitude
+	rocket.Altitude = payload.Altitude
+	return nil
+}
+
+func (s service) processRocketFuelLevelReportedFromData(rocket *models.Rocket, messageData json.RawMessage) error {
+	var payload models.RocketFuelLevelReportedMessage
+	if err := json.Unmarshal(messageData, &payload); err != nil {
+		return fmt.Errorf("failed to parse RocketFuelLevelReported payload: %w", err)
+	}
+
+	if payload.FuelLevel < 0 || payload.FuelLevel > 100 {
+		return fmt.Errorf("invalid fuel level: %v", payload.FuelLevel)
+	}
+
+	rocket.FuelLevel = &payload.FuelLevel
+	return nil
+}
+
 func (s service) GetRocket(ctx context.Context, id models.UUID) (*models.Rocket, error) {
 	requestID := pkgContext.GetRequestID(ctx)
 	_ = level.Debug(s.logger).Log("requestId", requestID, "msg", "getting rocket", "rocketId", id)
 
-	rocket, err := s.repository.GetRocket(id)
+	rocket, err := s.repository.GetRocket(ctx, id)
 	if err != nil {
 		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to get rocket", "rocketId", id,
 			"error", err)
@@ -262,11 +737,11 @@ func (s service) GetRocket(ctx context.Context, id models.UUID) (*models.Rocket,
 	return rocket, nil
 }
 
-func (s service) GetAllRockets(ctx context.Context, sortBy string) ([]models.Rocket, error) {
+func (s service) GetAllRockets(ctx context.Context, sortBy string, filter models.RocketFilter) ([]models.Rocket, error) {
 	requestID := pkgContext.GetRequestID(ctx)
 	_ = level.Debug(s.logger).Log("requestId", requestID, "msg", "getting all rockets", "sortBy", sortBy)
 
-	rockets, err := s.repository.GetAllRockets(sortBy)
+	rockets, err := s.repository.GetAllRockets(ctx, sortBy, filter)
 	if err != nil {
 		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to get rockets", "sortBy", sortBy,
 			"error", err)
@@ -278,10 +753,40 @@ func (s service) GetAllRockets(ctx context.Context, sortBy string) ([]models.Roc
 	return rockets, nil
 }
 
+func (s service) GetRocketStats(ctx context.Context) (*models.RocketStats, error) {
+	requestID := pkgContext.GetRequestID(ctx)
+
+	stats, err := s.repository.GetRocketStats(ctx)
+	if err != nil {
+		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to get rocket stats", "error", err)
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// GetRocketHistory returns the status history of a rocket, oldest first
+func (s service) GetRocketHistory(ctx context.Context, id models.UUID) ([]models.RocketStatusChange, error) {
+	requestID := pkgContext.GetRequestID(ctx)
+
+	history, err := s.repository.GetStatusHistory(ctx, id)
+	if err != nil {
+		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to get rocket history", "rocketId", id,
+			"error", err)
+		return nil, err
+	}
+
+	return history, nil
+}
+
 // NewService returns a rockets backend service
-func NewService(logger log.Logger, repo repository.RocketRepository) Service {
-	return &service{
+func NewService(logger log.Logger, repo repository.RocketRepository, opts ...Option) Service {
+	s := &service{
 		logger:     logger,
 		repository: repo,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
@@ -0,0 +1,351 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"rockets-backend/models"
+	"rockets-backend/repository"
+	"rockets-backend/service"
+	"rockets-backend/testutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/google/uuid"
+)
+
+// rocketFixture processes messages for a single channel against in-memory repositories
+type rocketFixture struct {
+	t       *testing.T
+	repo    repository.RocketRepository
+	svc     service.Service
+	channel string
+	next    int
+}
+
+func newRocketFixture(t *testing.T, opts ...service.Option) *rocketFixture {
+	repo := repository.NewMemoryRocketRepository()
+	return &rocketFixture{
+		t:       t,
+		repo:    repo,
+		svc:     service.NewService(log.NewNopLogger(), repo, opts...),
+		channel: uuid.New().String(),
+	}
+}
+
+// send ingests and processes the next message on the channel
+func (f *rocketFixture) send(messageType string, message interface{}, messageTime time.Time) error {
+	f.t.Helper()
+	f.next++
+
+	event, err := f.svc.IngestMessage(context.Background(), models.IncomingMessage{
+		Metadata: models.MessageMetadata{
+			Channel:       f.channel,
+			MessageNumber: f.next,
+			MessageTime:   messageTime,
+			MessageType:   messageType,
+		},
+		Message: message,
+	})
+	testutil.AssertNoError(f.t, err)
+
+	_, err = f.svc.ProcessEvent(context.Background(), event)
+	return err
+}
+
+func (f *rocketFixture) rocket() *models.Rocket {
+	f.t.Helper()
+	rocket, err := f.repo.GetRocket(context.Background(), f.channel)
+	testutil.AssertNoError(f.t, err)
+	testutil.AssertNotNil(f.t, rocket)
+	return rocket
+}
+
+func TestProcessEventIgnoresOutOfOrderMessages(t *testing.T) {
+	f := newRocketFixture(t)
+	now := time.Now()
+
+	testutil.AssertNoError(t, f.send("RocketLaunched", map[string]interface{}{
+		"type": "Falcon-9", "launchSpeed": 500, "mission": "ARTEMIS",
+	}, now))
+	testutil.AssertNoError(t, f.send("RocketSpeedIncreased", map[string]interface{}{"by": 300}, now))
+
+	stale := &models.RocketEvent{
+		Channel:       f.channel,
+		MessageNumber: 1,
+		MessageType:   "RocketSpeedIncreased",
+		MessageData:   []byte(`{"by":1000}`),
+	}
+	testutil.AssertNoError(t, f.repo.CreateRocketEvent(context.Background(), stale))
+	outcome, err := f.svc.ProcessEvent(context.Background(), stale)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, service.ProcessOutcomeIgnored, outcome)
+
+	rocket := f.rocket()
+	testutil.AssertEqual(t, 800, rocket.CurrentSpeed)
+	testutil.AssertEqual(t, 2, rocket.LastMessageNumber)
+
+	event, err := f.repo.GetRocketEvent(context.Background(), stale.ID)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, models.EventStatusProcessed, event.Status)
+}
+
+func TestProcessEventFailsUnknownMessageType(t *testing.T) {
+	f := newRocketFixture(t)
+
+	if err := f.send("RocketTeleported", map[string]interface{}{}, time.Now()); err == nil {
+		t.Fatal("Expected unknown message type to fail")
+	}
+
+	event, err := f.repo.GetRocketEvent(context.Background(), 1)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, models.EventStatusFailed, event.Status)
+	testutil.AssertEqual(t, "unknown message type: RocketTeleported", *event.ErrorMessage)
+}
+
+// failStatusRepository fails to mark events failed
+type failStatusRepository struct {
+	repository.RocketRepository
+}
+
+func (r *failStatusRepository) UpdateEventStatus(ctx context.Context, id int64, status string,
+	errorMessage *string) error {
+	if status == models.EventStatusFailed {
+		return errors.New("connection reset")
+	}
+	return r.RocketRepository.UpdateEventStatus(ctx, id, status, errorMessage)
+}
+
+func TestProcessEventReportsFailedStatusUpdate(t *testing.T) {
+	repo := &failStatusRepository{RocketRepository: repository.NewMemoryRocketRepository()}
+	f := &rocketFixture{t: t, repo: repo, svc: service.NewService(log.NewNopLogger(), repo), channel: uuid.New().String()}
+
+	err := f.send("RocketTeleported", map[string]interface{}{}, time.Now())
+	if err == nil || !strings.Contains(err.Error(), "unknown message type") ||
+		!strings.Contains(err.Error(), "failed to mark event as failed: connection reset") {
+		t.Fatalf("Expected both the processing and the status error, got: %v", err)
+	}
+
+	// Left processing, the claim timeout hands it out again
+	event, err := f.repo.GetRocketEvent(context.Background(), 1)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, models.EventStatusProcessing, event.Status)
+}
+
+func TestProcessEventDoesNotRelaunchExplodedRocket(t *testing.T) {
+	f := newRocketFixture(t)
+	now := time.Now()
+	launch := map[string]interface{}{"type": "Falcon-9", "launchSpeed": 500, "mission": "ARTEMIS"}
+
+	testutil.AssertNoError(t, f.send("RocketLaunched", launch, now))
+	testutil.AssertNoError(t, f.send("RocketExploded", map[string]interface{}{"reason": "PRESSURE_VESSEL_FAILURE"}, now))
+	if err := f.send("RocketLaunched", launch, now); err == nil {
+		t.Fatal("Expected launching an exploded rocket to fail")
+	}
+
+	event, err := f.repo.GetRocketEvent(context.Background(), 3)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, models.EventStatusFailed, event.Status)
+	testutil.AssertEqual(t, "failed to process RocketLaunched message: invalid status transition from exploded to active",
+		*event.ErrorMessage)
+
+	rocket := f.rocket()
+	testutil.AssertEqual(t, models.RocketStatusExploded, rocket.Status)
+	testutil.AssertEqual(t, 2, rocket.LastMessageNumber)
+}
+
+func TestIngestRejectsInvalidMessages(t *testing.T) {
+	repo := repository.NewMemoryRocketRepository()
+	svc := service.NewService(log.NewNopLogger(), repo)
+
+	tests := []struct {
+		name     string
+		metadata models.MessageMetadata
+	}{
+		{name: "channel is not a UUID", metadata: models.MessageMetadata{Channel: "not-a-uuid", MessageNumber: 1,
+			MessageType: "RocketSpeedIncreased"}},
+		{name: "message type missing", metadata: models.MessageMetadata{Channel: uuid.New().String(), MessageNumber: 1}},
+		{name: "message type too long", metadata: models.MessageMetadata{Channel: uuid.New().String(), MessageNumber: 1,
+			MessageType: strings.Repeat("x", 51)}},
+		{name: "message number out of range", metadata: models.MessageMetadata{Channel: uuid.New().String(),
+			MessageNumber: -1, MessageType: "RocketSpeedIncreased"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.IngestMessage(context.Background(), models.IncomingMessage{Metadata: tt.metadata,
+				Message: map[string]interface{}{"by": 100}})
+			if !errors.Is(err, service.ErrInvalidMessage) {
+				t.Fatalf("Expected an invalid message error, got %v", err)
+			}
+		})
+	}
+
+	pending, err := repo.GetPendingEvents(context.Background(), 10)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 0, len(pending))
+}
+
+func TestPayloadDeploymentCompletesMission(t *testing.T) {
+	f := newRocketFixture(t)
+	now := time.Now()
+
+	testutil.AssertNoError(t, f.send("RocketLaunched", map[string]interface{}{
+		"type": "Falcon-9", "launchSpeed": 500, "mission": "ARTEMIS", "stages": 2, "payloads": []string{"SAT-1"},
+	}, now))
+	testutil.AssertNoError(t, f.send("RocketStageSeparated", map[string]interface{}{"stage": 1}, now))
+	testutil.AssertNoError(t, f.send("RocketStageSeparated", map[string]interface{}{"stage": 2}, now))
+	testutil.AssertNoError(t, f.send("RocketPayloadDeployed", map[string]interface{}{"payload": "SAT-1"}, now))
+
+	rocket := f.rocket()
+	testutil.AssertEqual(t, 0, rocket.ActiveStage)
+	testutil.AssertEqual(t, models.RocketStatusMissionComplete, rocket.Status)
+
+	if err := f.send("RocketPayloadDeployed", map[string]interface{}{"payload": "SAT-1"}, now); err == nil {
+		t.Fatal("Expected deploying a payload twice to fail")
+	}
+
+	history, err := f.svc.GetRocketHistory(context.Background(), f.channel)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 2, len(history))
+	testutil.AssertEqual(t, models.RocketStatusMissionComplete, history[1].ToStatus)
+	testutil.AssertEqual(t, "RocketPayloadDeployed", history[1].Reason)
+}
+
+func TestLostContactIsRestoredByNextMessage(t *testing.T) {
+	f := newRocketFixture(t)
+	ctx := context.Background()
+
+	testutil.AssertNoError(t, f.send("RocketLaunched", map[string]interface{}{
+		"type": "Falcon-9", "launchSpeed": 500, "mission": "ARTEMIS",
+	}, time.Now()))
+
+	time.Sleep(5 * time.Millisecond)
+	flagged, err := f.svc.DetectStaleRockets(ctx, time.Millisecond)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, flagged)
+	testutil.AssertEqual(t, models.RocketStatusLostContact, f.rocket().Status)
+
+	// A rocket can only be flagged once
+	flagged, err = f.svc.DetectStaleRockets(ctx, time.Millisecond)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 0, flagged)
+
+	testutil.AssertNoError(t, f.send("RocketExploded", map[string]interface{}{"reason": "silent failure"}, time.Now()))
+	testutil.AssertEqual(t, models.RocketStatusExploded, f.rocket().Status)
+
+	history, err := f.svc.GetRocketHistory(ctx, f.channel)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 4, len(history))
+	testutil.AssertEqual(t, models.RocketStatusLostContact, history[1].ToStatus)
+	testutil.AssertEqual(t, "contact restored", history[2].Reason)
+	testutil.AssertEqual(t, models.RocketStatusExploded, history[3].ToStatus)
+}
+
+// flagAfterReadRepository runs flag once, right after ProcessEvent read the rocket
+type flagAfterReadRepository struct {
+	repository.RocketRepository
+	flag func()
+}
+
+func (r *flagAfterReadRepository) GetRocket(ctx context.Context, id models.UUID) (*models.Rocket, error) {
+	rocket, err := r.RocketRepository.GetRocket(ctx, id)
+	if r.flag != nil {
+		flag := r.flag
+		r.flag = nil
+		flag()
+	}
+	return rocket, err
+}
+
+func TestLostContactFlaggedDuringProcessingIsRestored(t *testing.T) {
+	ctx := context.Background()
+	repo := &flagAfterReadRepository{RocketRepository: repository.NewMemoryRocketRepository()}
+	svc := service.NewService(log.NewNopLogger(), repo)
+	f := &rocketFixture{t: t, repo: repo, svc: svc, channel: uuid.New().String()}
+
+	testutil.AssertNoError(t, f.send("RocketLaunched", map[string]interface{}{
+		"type": "Falcon-9", "launchSpeed": 500, "mission": "ARTEMIS",
+	}, time.Now()))
+
+	time.Sleep(5 * time.Millisecond)
+	repo.flag = func() {
+		flagged, err := svc.DetectStaleRockets(ctx, time.Millisecond)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, flagged)
+	}
+	testutil.AssertNoError(t, f.send("RocketSpeedIncreased", map[string]interface{}{"by": 300}, time.Now()))
+	testutil.AssertEqual(t, models.RocketStatusActive, f.rocket().Status)
+
+	history, err := svc.GetRocketHistory(ctx, f.channel)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 3, len(history))
+	testutil.AssertEqual(t, models.RocketStatusLostContact, history[1].ToStatus)
+	testutil.AssertEqual(t, "contact restored", history[2].Reason)
+	testutil.AssertEqual(t, models.RocketStatusActive, history[2].ToStatus)
+}
+
+func TestKinematicsFollowMessageTime(t *testing.T) {
+	f := newRocketFixture(t)
+	launchedAt := time.Date(2022, 2, 2, 19, 39, 5, 0, time.UTC)
+
+	testutil.AssertNoError(t, f.send("RocketLaunched", map[string]interface{}{
+		"type": "Falcon-9", "launchSpeed": 1000, "mission": "ARTEMIS",
+	}, launchedAt))
+	testutil.AssertNoError(t, f.send("RocketSpeedIncreased", map[string]interface{}{"by": 2000}, launchedAt.Add(4*time.Second)))
+	testutil.AssertNoError(t, f.send("RocketSpeedDecreased", map[string]interface{}{"by": 1000}, launchedAt.Add(5*time.Second)))
+
+	rocket := f.rocket()
+	testutil.AssertEqual(t, true, rocket.LaunchTime.Equal(launchedAt))
+	testutil.AssertEqual(t, 3000, rocket.PeakSpeed)
+	testutil.AssertEqual(t, true, rocket.TimeAtMaxSpeed.Equal(launchedAt.Add(4*time.Second)))
+	testutil.AssertEqual(t, 3, rocket.SpeedSamples)
+	testutil.AssertEqual(t, 2000.0, rocket.AverageSpeed)
+	testutil.AssertEqual(t, -1000.0, *rocket.Acceleration)
+}
+
+func TestAlertRulesFireOnStateChanges(t *testing.T) {
+	alerts := repository.NewMemoryAlertRepository()
+	f := newRocketFixture(t, service.WithAlertRepository(alerts))
+	ctx := context.Background()
+	now := time.Now()
+
+	starship := "Starship"
+	_, err := f.svc.CreateAlertRule(ctx, models.AlertRule{
+		Name: "starship only", Metric: models.AlertMetricSpeed, Operator: ">", Threshold: 0, RocketType: &starship, Enabled: true,
+	})
+	testutil.AssertNoError(t, err)
+	_, err = f.svc.CreateAlertRule(ctx, models.AlertRule{
+		Name: "low fuel", Metric: models.AlertMetricFuelLevel, Operator: "<", Threshold: 10, Enabled: true,
+	})
+	testutil.AssertNoError(t, err)
+	_, err = f.svc.CreateAlertRule(ctx, models.AlertRule{
+		Name: "disabled", Metric: models.AlertMetricSpeed, Operator: ">", Threshold: 0, Enabled: false,
+	})
+	testutil.AssertNoError(t, err)
+
+	testutil.AssertNoError(t, f.send("RocketLaunched", map[string]interface{}{
+		"type": "Falcon-9", "launchSpeed": 500, "mission": "ARTEMIS",
+	}, now))
+	testutil.AssertNoError(t, f.send("RocketFuelLevelReported", map[string]interface{}{"fuelLevel": 5}, now))
+	testutil.AssertNoError(t, f.send("RocketFuelLevelReported", map[string]interface{}{"fuelLevel": 4}, now))
+
+	fired, err := f.svc.GetAlerts(ctx, models.AlertFilter{})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, len(fired))
+	testutil.AssertEqual(t, "low fuel", fired[0].RuleName)
+	testutil.AssertEqual(t, 2, fired[0].MessageNumber)
+	testutil.AssertEqual(t, `{"fuelLevel":5}`, string(fired[0].MessageData))
+
+	// Deleting the rule keeps the alert
+	deleted, err := f.svc.DeleteAlertRule(ctx, *fired[0].RuleID)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, true, deleted)
+
+	resolved, err := f.svc.ResolveAlert(ctx, fired[0].ID)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, models.AlertStatusResolved, resolved.Status)
+	if resolved.RuleID != nil {
+		t.Fatal("Expected rule reference to be cleared after deleting the rule")
+	}
+}
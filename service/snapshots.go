@@ -0,0 +1,242 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"rockets-backend/models"
+	pkgContext "rockets-backend/pkg/context"
+	"sort"
+
+	"github.com/go-kit/log/level"
+)
+
+// WithSnapshots stores a rocket snapshot each time the message number crosses a multiple
+// of every, 0 disables snapshots
+func WithSnapshots(every int) Option {
+	return func(s *service) {
+		s.snapshotEvery = every
+	}
+}
+
+// snapshotDue reports whether moving from message number before to after crosses a
+// snapshot boundary. Message numbers may skip, so a boundary is not always hit exactly.
+func (s service) snapshotDue(before, after int) bool {
+	if s.snapshotEvery <= 0 {
+		return false
+	}
+	return before/s.snapshotEvery < after/s.snapshotEvery
+}
+
+// saveSnapshot stores the rocket state. A failed snapshot only makes later replays longer,
+// so it is logged and event processing goes on.
+func (s service) saveSnapshot(ctx context.Context, rocket *models.Rocket) {
+	requestID := pkgContext.GetRequestID(ctx)
+
+	snapshot, err := newSnapshot(rocket)
+	if err == nil {
+		err = s.repository.CreateRocketSnapshot(ctx, snapshot)
+	}
+	if err != nil {
+		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to save rocket snapshot",
+			"rocketId", rocket.ID, "messageNumber", rocket.LastMessageNumber, "error", err)
+		return
+	}
+
+	_ = level.Debug(s.logger).Log("requestId", requestID, "msg", "rocket snapshot saved",
+		"rocketId", rocket.ID, "messageNumber", rocket.LastMessageNumber)
+}
+
+func newSnapshot(rocket *models.Rocket) (*models.RocketSnapshot, error) {
+	state, err := models.EncodeRocketState(*rocket)
+	if err != nil {
+		return nil, err
+	}
+	checksum, err := models.RocketStateChecksum(*rocket)
+	if err != nil {
+		return nil, err
+	}
+	return &models.RocketSnapshot{
+		RocketID:      rocket.ID,
+		MessageNumber: rocket.LastMessageNumber,
+		State:         state,
+		Checksum:      checksum,
+	}, nil
+}
+
+// snapshotRocket decodes a snapshot and checks it against its checksum
+func snapshotRocket(snapshot *models.RocketSnapshot) (*models.Rocket, bool, error) {
+	rocket, err := models.DecodeRocketState(snapshot.State)
+	if err != nil {
+		return nil, false, err
+	}
+	checksum, err := models.RocketStateChecksum(*rocket)
+	if err != nil {
+		return nil, false, err
+	}
+	return rocket, checksum == snapshot.Checksum, nil
+}
+
+// replayEvents folds processed events into the rocket in the order they were processed,
+// skipping the ones the processor ignored as out of order. Returns the number applied.
+func (s service) replayEvents(rocket *models.Rocket, events []models.RocketEvent) (int, error) {
+	applied := 0
+	for i := range events {
+		event := &events[i]
+		if event.MessageNumber <= rocket.LastMessageNumber {
+			continue
+		}
+		if err := s.applyEvent(rocket, event); err != nil {
+			return applied, fmt.Errorf("failed to replay event %d: %w", event.ID, err)
+		}
+		if event.ProcessedAt != nil {
+			rocket.LastUpdated = *event.ProcessedAt
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+// ReplayRocket rebuilds a rocket from its event log as of the given message number, 0 for
+// the latest state. Replay starts from the latest snapshot at or before that message.
+func (s service) ReplayRocket(ctx context.Context, id models.UUID, messageNumber int) (*models.RocketReplay, error) {
+	requestID := pkgContext.GetRequestID(ctx)
+
+	upTo := messageNumber
+	if upTo <= 0 {
+		upTo = math.MaxInt32
+	}
+
+	replay := &models.RocketReplay{}
+	rocket := newRocket(id)
+
+	snapshot, err := s.repository.GetLatestRocketSnapshot(ctx, id, upTo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rocket snapshot: %w", err)
+	}
+	if snapshot != nil {
+		stored, valid, err := snapshotRocket(snapshot)
+		if err != nil {
+			return nil, err
+		}
+		if !valid {
+			return nil, fmt.Errorf("snapshot at message %d does not match its checksum", snapshot.MessageNumber)
+		}
+		rocket = stored
+		replay.SnapshotMessageNumber = &snapshot.MessageNumber
+	}
+
+	events, err := s.repository.GetProcessedEvents(ctx, id, rocket.LastMessageNumber, upTo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rocket events: %w", err)
+	}
+	replay.EventsApplied, err = s.replayEvents(rocket, events)
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshot == nil && replay.EventsApplied == 0 {
+		return nil, fmt.Errorf("rocket not found")
+	}
+
+	replay.Rocket = *rocket
+	replay.MessageNumber = rocket.LastMessageNumber
+
+	_ = level.Debug(s.logger).Log("requestId", requestID, "msg", "rocket replayed", "rocketId", id,
+		"messageNumber", replay.MessageNumber, "eventsApplied", replay.EventsApplied)
+	return replay, nil
+}
+
+// VerifySnapshots checks every snapshot of a rocket against its checksum and against the
+// state replayed from the previous snapshot, or from the first event for the oldest one
+func (s service) VerifySnapshots(ctx context.Context, id models.UUID) (*models.SnapshotVerification, error) {
+	requestID := pkgContext.GetRequestID(ctx)
+
+	snapshots, err := s.repository.GetRocketSnapshots(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rocket snapshots: %w", err)
+	}
+
+	verification := &models.SnapshotVerification{RocketID: id, Valid: true, Results: []models.SnapshotCheck{}}
+	rocket := newRocket(id)
+	for i := range snapshots {
+		snapshot := &snapshots[i]
+		check := models.SnapshotCheck{
+			MessageNumber: snapshot.MessageNumber,
+			CreatedAt:     snapshot.CreatedAt,
+			Status:        models.SnapshotStatusOK,
+		}
+
+		events, err := s.repository.GetProcessedEvents(ctx, id, rocket.LastMessageNumber, snapshot.MessageNumber)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rocket events: %w", err)
+		}
+		if _, err := s.replayEvents(rocket, events); err != nil {
+			return nil, err
+		}
+
+		stored, valid, err := snapshotRocket(snapshot)
+		if err != nil {
+			return nil, err
+		}
+		if !valid {
+			check.Status = models.SnapshotStatusChecksumMismatch
+		} else if check.Fields, err = stateDiff(*rocket, *stored); err != nil {
+			return nil, err
+		} else if len(check.Fields) > 0 {
+			check.Status = models.SnapshotStatusStateMismatch
+		} else {
+			// Continue from the snapshot so a bad stretch of the log only fails one check
+			rocket = stored
+		}
+
+		verification.Valid = verification.Valid && check.Status == models.SnapshotStatusOK
+		verification.Results = append(verification.Results, check)
+	}
+
+	_ = level.Info(s.logger).Log("requestId", requestID, "msg", "rocket snapshots verified", "rocketId", id,
+		"snapshots", len(snapshots), "valid", verification.Valid)
+	return verification, nil
+}
+
+// stateDiff returns the snapshot fields that differ between two rocket states. LastUpdated
+// is the wall clock of the processor and is not compared.
+func stateDiff(replayed, stored models.Rocket) ([]string, error) {
+	fields := func(rocket models.Rocket) (map[string]json.RawMessage, error) {
+		rocket.LastUpdated = stored.LastUpdated
+		data, err := models.EncodeRocketState(rocket)
+		if err != nil {
+			return nil, err
+		}
+		var decoded map[string]json.RawMessage
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return nil, fmt.Errorf("failed to decode rocket state: %w", err)
+		}
+		return decoded, nil
+	}
+
+	a, err := fields(replayed)
+	if err != nil {
+		return nil, err
+	}
+	b, err := fields(stored)
+	if err != nil {
+		return nil, err
+	}
+
+	var diff []string
+	for name, value := range a {
+		if !bytes.Equal(value, b[name]) {
+			diff = append(diff, name)
+		}
+	}
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			diff = append(diff, name)
+		}
+	}
+	sort.Strings(diff)
+	return diff, nil
+}
@@ -0,0 +1,130 @@
+package service_test
+
+import (
+	"context"
+	"rockets-backend/models"
+	"rockets-backend/service"
+	"rockets-backend/testutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestReplayStartsFromLatestSnapshot(t *testing.T) {
+	ctx := context.Background()
+	f := newRocketFixture(t, service.WithSnapshots(3))
+	launchedAt := time.Date(2022, 2, 2, 19, 39, 5, 0, time.UTC)
+
+	testutil.AssertNoError(t, f.send("RocketLaunched", map[string]interface{}{
+		"type": "Falcon-9", "launchSpeed": 1000, "mission": "ARTEMIS",
+	}, launchedAt))
+	for i := 1; i <= 6; i++ {
+		testutil.AssertNoError(t, f.send("RocketSpeedIncreased", map[string]interface{}{"by": 100},
+			launchedAt.Add(time.Duration(i)*time.Second)))
+	}
+
+	snapshots, err := f.repo.GetRocketSnapshots(ctx, f.channel)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 2, len(snapshots))
+	testutil.AssertEqual(t, 3, snapshots[0].MessageNumber)
+	testutil.AssertEqual(t, 6, snapshots[1].MessageNumber)
+
+	t.Run("latest state", func(t *testing.T) {
+		replay, err := f.svc.ReplayRocket(ctx, f.channel, 0)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 6, *replay.SnapshotMessageNumber)
+		testutil.AssertEqual(t, 1, replay.EventsApplied)
+		testutil.AssertEqual(t, 7, replay.MessageNumber)
+
+		rocket := f.rocket()
+		testutil.AssertEqual(t, rocket.CurrentSpeed, replay.Rocket.CurrentSpeed)
+		testutil.AssertEqual(t, rocket.PeakSpeed, replay.Rocket.PeakSpeed)
+		testutil.AssertEqual(t, rocket.AverageSpeed, replay.Rocket.AverageSpeed)
+		testutil.AssertEqual(t, true, replay.Rocket.TimeAtMaxSpeed.Equal(*rocket.TimeAtMaxSpeed))
+	})
+
+	t.Run("earlier message", func(t *testing.T) {
+		replay, err := f.svc.ReplayRocket(ctx, f.channel, 4)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 3, *replay.SnapshotMessageNumber)
+		testutil.AssertEqual(t, 1, replay.EventsApplied)
+		testutil.AssertEqual(t, 1300, replay.Rocket.CurrentSpeed)
+
+		replay, err = f.svc.ReplayRocket(ctx, f.channel, 2)
+		testutil.AssertNoError(t, err)
+		if replay.SnapshotMessageNumber != nil {
+			t.Fatalf("Expected a replay from the first event, got snapshot %d", *replay.SnapshotMessageNumber)
+		}
+		testutil.AssertEqual(t, 2, replay.EventsApplied)
+		testutil.AssertEqual(t, 1100, replay.Rocket.CurrentSpeed)
+	})
+
+	t.Run("archived events", func(t *testing.T) {
+		_, err := f.repo.ArchiveEvents(ctx, models.EventStatusProcessed, 0, 100)
+		testutil.AssertNoError(t, err)
+
+		replay, err := f.svc.ReplayRocket(ctx, f.channel, 5)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1400, replay.Rocket.CurrentSpeed)
+	})
+
+	t.Run("unknown rocket", func(t *testing.T) {
+		_, err := f.svc.ReplayRocket(ctx, uuid.New().String(), 0)
+		if err == nil || err.Error() != "rocket not found" {
+			t.Fatalf("Expected rocket not found, got %v", err)
+		}
+	})
+}
+
+func TestVerifySnapshots(t *testing.T) {
+	ctx := context.Background()
+	f := newRocketFixture(t, service.WithSnapshots(2))
+	now := time.Now()
+
+	testutil.AssertNoError(t, f.send("RocketLaunched", map[string]interface{}{
+		"type": "Falcon-9", "launchSpeed": 500, "mission": "ARTEMIS",
+	}, now))
+	for i := 1; i <= 5; i++ {
+		testutil.AssertNoError(t, f.send("RocketAltitudeChanged", map[string]interface{}{"altitude": i * 1000}, now))
+	}
+
+	verification, err := f.svc.VerifySnapshots(ctx, f.channel)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, true, verification.Valid)
+	testutil.AssertEqual(t, 3, len(verification.Results))
+	for _, check := range verification.Results {
+		testutil.AssertEqual(t, models.SnapshotStatusOK, check.Status)
+	}
+
+	// A snapshot whose state no longer follows from the log
+	snapshot, err := f.repo.GetLatestRocketSnapshot(ctx, f.channel, 4)
+	testutil.AssertNoError(t, err)
+	altered, err := models.DecodeRocketState(snapshot.State)
+	testutil.AssertNoError(t, err)
+	altered.Altitude = 99999
+	snapshot.State, err = models.EncodeRocketState(*altered)
+	testutil.AssertNoError(t, err)
+	snapshot.Checksum, err = models.RocketStateChecksum(*altered)
+	testutil.AssertNoError(t, err)
+	testutil.AssertNoError(t, f.repo.CreateRocketSnapshot(ctx, snapshot))
+
+	// A snapshot edited without updating its checksum
+	snapshot, err = f.repo.GetLatestRocketSnapshot(ctx, f.channel, 6)
+	testutil.AssertNoError(t, err)
+	snapshot.Checksum = "0000"
+	testutil.AssertNoError(t, f.repo.CreateRocketSnapshot(ctx, snapshot))
+
+	verification, err = f.svc.VerifySnapshots(ctx, f.channel)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, false, verification.Valid)
+	testutil.AssertEqual(t, models.SnapshotStatusOK, verification.Results[0].Status)
+	testutil.AssertEqual(t, models.SnapshotStatusStateMismatch, verification.Results[1].Status)
+	testutil.AssertEqual(t, 1, len(verification.Results[1].Fields))
+	testutil.AssertEqual(t, "altitude", verification.Results[1].Fields[0])
+	testutil.AssertEqual(t, models.SnapshotStatusChecksumMismatch, verification.Results[2].Status)
+
+	if _, err := f.svc.ReplayRocket(ctx, f.channel, 0); err == nil {
+		t.Fatal("Expected replay from a snapshot with a bad checksum to fail")
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"rockets-backend/models"
+	pkgContext "rockets-backend/pkg/context"
+	"rockets-backend/repository"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log/level"
+)
+
+var errSourcesDisabled = errors.New("message sources are not configured")
+
+// ErrInvalidSignature is returned, wrapped with the reason, for messages whose
+// signature cannot be verified
+var ErrInvalidSignature = errors.New("invalid message signature")
+
+// Errors for messages on a channel bound to a message source, sending them again cannot succeed
+var (
+	ErrChannelBoundToOtherSource = errors.New("channel is bound to another message source")
+	ErrChannelRequiresSignature  = errors.New("messages for this channel must be signed")
+)
+
+// IsChannelRejected reports whether err rejects a message for the source its channel is bound to
+func IsChannelRejected(err error) bool {
+	return errors.Is(err, ErrChannelBoundToOtherSource) || errors.Is(err, ErrChannelRequiresSignature)
+}
+
+// WithMessageSources enables signed messages. Signatures older or newer than
+// replayWindow are rejected. With bindChannels each channel is bound to the first
+// source that signs a message for it, and other sources are rejected for it afterwards.
+func WithMessageSources(sources repository.SourceRepository, replayWindow time.Duration, bindChannels bool) Option {
+	return func(s *service) {
+		s.sources = sources
+		s.replayWindow = replayWindow
+		s.bindChannels = bindChannels
+	}
+}
+
+// SignMessage returns the hex HMAC-SHA256 of "<timestamp>.<body>" a source sends in X-Signature
+func SignMessage(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyMessageSignature checks a message body signed by the named source at timestamp,
+// a unix time in seconds. Verification failures wrap ErrInvalidSignature.
+func (s service) VerifyMessageSignature(ctx context.Context, sourceName string, timestamp string, signature string,
+	body []byte) (*models.MessageSource, error) {
+	if s.sources == nil {
+		return nil, errSourcesDisabled
+	}
+
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed timestamp", ErrInvalidSignature)
+	}
+	if skew := time.Since(time.Unix(signedAt, 0)); skew > s.replayWindow || skew < -s.replayWindow {
+		return nil, fmt.Errorf("%w: timestamp outside the replay window", ErrInvalidSignature)
+	}
+
+	source, err := s.sources.GetSourceByName(ctx, sourceName)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil || source.RevokedAt != nil {
+		return nil, fmt.Errorf("%w: unknown message source", ErrInvalidSignature)
+	}
+
+	expected := SignMessage(source.Secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(strings.TrimPrefix(signature, "sha256="))) {
+		return nil, fmt.Errorf("%w: signature mismatch", ErrInvalidSignature)
+	}
+
+	return source, nil
+}
+
+// sourceName returns the name of the source that signed the request, or "" if it was unsigned
+func sourceName(ctx context.Context) string {
+	source, _ := pkgContext.GetSource(ctx)
+	return source.Name
+}
+
+// checkChannelSource enforces channel bindings for a message being ingested
+func (s service) checkChannelSource(ctx context.Context, channel models.UUID) error {
+	if s.sources == nil || !s.bindChannels {
+		return nil
+	}
+
+	source, signed := pkgContext.GetSource(ctx)
+	if !signed {
+		boundTo, err := s.sources.GetChannelSource(ctx, channel)
+		if err != nil {
+			return err
+		}
+		if boundTo != nil {
+			return ErrChannelRequiresSignature
+		}
+		return nil
+	}
+
+	boundTo, err := s.sources.BindChannel(ctx, channel, source.ID)
+	if err != nil {
+		return err
+	}
+	if boundTo != source.ID {
+		return ErrChannelBoundToOtherSource
+	}
+	return nil
+}
+
+// CreateMessageSource creates a source with a new secret. The secret cannot be retrieved again.
+func (s service) CreateMessageSource(ctx context.Context, name string) (*models.CreatedMessageSource, error) {
+	requestID := pkgContext.GetRequestID(ctx)
+	if s.sources == nil {
+		return nil, errSourcesDisabled
+	}
+	if name == "" {
+		return nil, fmt.Errorf("message source name is required")
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("failed to generate message source secret: %w", err)
+	}
+
+	source := models.MessageSource{Name: name, Secret: hex.EncodeToString(random)}
+	if err := s.sources.CreateSource(ctx, &source); err != nil {
+		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to create message source", "error", err)
+		return nil, err
+	}
+
+	_ = level.Info(s.logger).Log("requestId", requestID, "msg", "message source created", "sourceId", source.ID,
+		"name", name, "apiKey", callerName(ctx))
+	s.audit(ctx, "source.create", "source/"+strconv.FormatInt(source.ID, 10))
+
+	return &models.CreatedMessageSource{MessageSource: source, Secret: source.Secret}, nil
+}
+
+func (s service) GetMessageSources(ctx context.Context) ([]models.MessageSource, error) {
+	if s.sources == nil {
+		return nil, errSourcesDisabled
+	}
+	return s.sources.GetSources(ctx)
+}
+
+// RevokeMessageSource stops accepting a source's signatures, its channels stay bound to it.
+// Returns false if the source does not exist or is already revoked.
+func (s service) RevokeMessageSource(ctx context.Context, id int64) (bool, error) {
+	requestID := pkgContext.GetRequestID(ctx)
+	if s.sources == nil {
+		return false, errSourcesDisabled
+	}
+
+	revoked, err := s.sources.RevokeSource(ctx, id)
+	if err != nil {
+		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to revoke message source", "sourceId", id,
+			"error", err)
+		return false, err
+	}
+
+	_ = level.Info(s.logger).Log("requestId", requestID, "msg", "message source revoked", "sourceId", id,
+		"found", revoked, "apiKey", callerName(ctx))
+	if revoked {
+		s.audit(ctx, "source.revoke", "source/"+strconv.FormatInt(id, 10))
+	}
+	return revoked, nil
+}
@@ -0,0 +1,131 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"rockets-backend/models"
+	pkgContext "rockets-backend/pkg/context"
+	"rockets-backend/repository"
+	"rockets-backend/service"
+	"rockets-backend/testutil"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+)
+
+func newSourceService(bindChannels bool) service.Service {
+	return service.NewService(log.NewNopLogger(), repository.NewMemoryRocketRepository(),
+		service.WithAuthRepository(repository.NewMemoryAuthRepository()),
+		service.WithMessageSources(repository.NewMemorySourceRepository(), 5*time.Minute, bindChannels))
+}
+
+func TestVerifyMessageSignature(t *testing.T) {
+	svc := newSourceService(false)
+	ctx := context.Background()
+
+	created, err := svc.CreateMessageSource(ctx, "ground-station")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 64, len(created.Secret))
+
+	body := []byte(`{"metadata":{"channel":"193270a9-c9cf-404a-8f83-838e71d9ae67"}}`)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := service.SignMessage(created.Secret, now, body)
+
+	source, err := svc.VerifyMessageSignature(ctx, "ground-station", now, signature, body)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, created.ID, source.ID)
+
+	_, err = svc.VerifyMessageSignature(ctx, "ground-station", now, "sha256="+signature, body)
+	testutil.AssertNoError(t, err)
+
+	stale := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+	invalid := []struct {
+		name      string
+		source    string
+		timestamp string
+		signature string
+		body      []byte
+	}{
+		{"tampered body", "ground-station", now, signature, []byte(`{}`)},
+		{"wrong signature", "ground-station", now, service.SignMessage("other", now, body), body},
+		{"unknown source", "unknown", now, signature, body},
+		{"malformed timestamp", "ground-station", "yesterday", signature, body},
+		{"replayed", "ground-station", stale, service.SignMessage(created.Secret, stale, body), body},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.VerifyMessageSignature(ctx, tc.source, tc.timestamp, tc.signature, tc.body)
+			if !errors.Is(err, service.ErrInvalidSignature) {
+				t.Fatalf("Expected an invalid signature error, got %v", err)
+			}
+		})
+	}
+
+	revoked, err := svc.RevokeMessageSource(ctx, created.ID)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, true, revoked)
+
+	_, err = svc.VerifyMessageSignature(ctx, "ground-station", now, signature, body)
+	if !errors.Is(err, service.ErrInvalidSignature) {
+		t.Fatalf("Expected a revoked source to be rejected, got %v", err)
+	}
+}
+
+func TestChannelsAreBoundToTheirSource(t *testing.T) {
+	svc := newSourceService(true)
+	ctx := context.Background()
+
+	first, err := svc.CreateMessageSource(ctx, "first")
+	testutil.AssertNoError(t, err)
+	second, err := svc.CreateMessageSource(ctx, "second")
+	testutil.AssertNoError(t, err)
+
+	ingest := func(ctx context.Context, number int) error {
+		_, err := svc.IngestMessage(ctx, models.IncomingMessage{
+			Metadata: models.MessageMetadata{
+				Channel:       "193270a9-c9cf-404a-8f83-838e71d9ae67",
+				MessageNumber: number,
+				MessageTime:   time.Now(),
+				MessageType:   "RocketSpeedIncreased",
+			},
+			Message: map[string]interface{}{"by": 100},
+		})
+		return err
+	}
+	signedBy := func(source *models.CreatedMessageSource) context.Context {
+		return pkgContext.WithSource(ctx, pkgContext.Source{ID: source.ID, Name: source.Name})
+	}
+
+	testutil.AssertNoError(t, ingest(signedBy(first), 1))
+	testutil.AssertNoError(t, ingest(signedBy(first), 2))
+
+	err = ingest(signedBy(second), 3)
+	testutil.AssertEqual(t, true, errors.Is(err, service.ErrChannelBoundToOtherSource))
+
+	err = ingest(ctx, 4)
+	testutil.AssertEqual(t, true, errors.Is(err, service.ErrChannelRequiresSignature))
+}
+
+func TestMessageSourceChangesAreAudited(t *testing.T) {
+	svc := newSourceService(false)
+	ctx := pkgContext.WithCaller(context.Background(), pkgContext.Caller{KeyID: 1, KeyName: "ops", Role: models.RoleAdmin})
+
+	created, err := svc.CreateMessageSource(ctx, "ground-station")
+	testutil.AssertNoError(t, err)
+	_, err = svc.RevokeMessageSource(ctx, created.ID)
+	testutil.AssertNoError(t, err)
+
+	records, err := svc.GetAuditLog(ctx, 0)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 2, len(records))
+	testutil.AssertEqual(t, "source.revoke", records[0].Action)
+	testutil.AssertEqual(t, "source.create", records[1].Action)
+	testutil.AssertEqual(t, "ops", *records[1].APIKeyName)
+
+	_, err = svc.CreateMessageSource(ctx, "")
+	if err == nil {
+		t.Fatal("Expected a source without a name to be rejected")
+	}
+}
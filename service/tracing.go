@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"rockets-backend/models"
+	"rockets-backend/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TracingMiddleware wraps service calls in spans. Health probes are not traced, they
+// are called every few seconds and would drown out the traces of real work.
+func TracingMiddleware() Middleware {
+	return func(next Service) Service {
+		return tracingService{Service: next}
+	}
+}
+
+type tracingService struct {
+	Service
+}
+
+func (s tracingService) IngestMessage(ctx context.Context, msg models.IncomingMessage) (event *models.RocketEvent, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.IngestMessage")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	span.SetAttributes(
+		attribute.String("rocket.channel", msg.Metadata.Channel),
+		attribute.Int("rocket.message_number", msg.Metadata.MessageNumber),
+		attribute.String("rocket.message_type", msg.Metadata.MessageType),
+	)
+	return s.Service.IngestMessage(ctx, msg)
+}
+
+func (s tracingService) ProcessEvent(ctx context.Context, event *models.RocketEvent) (outcome ProcessOutcome, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.ProcessEvent")
+	defer func() {
+		span.SetAttributes(attribute.String("rocket.outcome", string(outcome)))
+		tracing.EndSpan(span, err)
+	}()
+
+	span.SetAttributes(
+		attribute.Int64("rocket.event_id", event.ID),
+		attribute.String("rocket.channel", event.Channel),
+		attribute.Int("rocket.message_number", event.MessageNumber),
+		attribute.String("rocket.message_type", event.MessageType),
+	)
+	return s.Service.ProcessEvent(ctx, event)
+}
+
+func (s tracingService) GetRocket(ctx context.Context, id models.UUID) (rocket *models.Rocket, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.GetRocket")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	span.SetAttributes(attribute.String("rocket.channel", id))
+	return s.Service.GetRocket(ctx, id)
+}
+
+func (s tracingService) GetAllRockets(ctx context.Context, sortBy string, filter models.RocketFilter) (rockets []models.Rocket, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.GetAllRockets")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.GetAllRockets(ctx, sortBy, filter)
+}
+
+func (s tracingService) GetRocketStats(ctx context.Context) (stats *models.RocketStats, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.GetRocketStats")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.GetRocketStats(ctx)
+}
+
+func (s tracingService) GetRocketHistory(ctx context.Context, id models.UUID) (history []models.RocketStatusChange, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.GetRocketHistory")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	span.SetAttributes(attribute.String("rocket.channel", id))
+	return s.Service.GetRocketHistory(ctx, id)
+}
+
+func (s tracingService) ReplayRocket(ctx context.Context, id models.UUID, messageNumber int) (replay *models.RocketReplay, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.ReplayRocket")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	span.SetAttributes(attribute.String("rocket.channel", id), attribute.Int("rocket.message_number", messageNumber))
+	replay, err = s.Service.ReplayRocket(ctx, id, messageNumber)
+	if replay != nil {
+		span.SetAttributes(attribute.Int("rocket.events_applied", replay.EventsApplied))
+	}
+	return replay, err
+}
+
+func (s tracingService) VerifySnapshots(ctx context.Context, id models.UUID) (verification *models.SnapshotVerification, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.VerifySnapshots")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	span.SetAttributes(attribute.String("rocket.channel", id))
+	return s.Service.VerifySnapshots(ctx, id)
+}
+
+func (s tracingService) DetectStaleRockets(ctx context.Context, threshold time.Duration) (flagged int, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.DetectStaleRockets")
+	defer func() {
+		span.SetAttributes(attribute.Int("rocket.flagged", flagged))
+		tracing.EndSpan(span, err)
+	}()
+
+	return s.Service.DetectStaleRockets(ctx, threshold)
+}
+
+func (s tracingService) GetEventStatus(ctx context.Context, eventID int64) (event *models.RocketEvent, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.GetEventStatus")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	span.SetAttributes(attribute.Int64("rocket.event_id", eventID))
+	return s.Service.GetEventStatus(ctx, eventID)
+}
+
+func (s tracingService) CreateAlertRule(ctx context.Context, rule models.AlertRule) (created *models.AlertRule, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.CreateAlertRule")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.CreateAlertRule(ctx, rule)
+}
+
+func (s tracingService) GetAlertRule(ctx context.Context, id int64) (rule *models.AlertRule, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.GetAlertRule")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.GetAlertRule(ctx, id)
+}
+
+func (s tracingService) GetAlertRules(ctx context.Context) (rules []models.AlertRule, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.GetAlertRules")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.GetAlertRules(ctx)
+}
+
+func (s tracingService) UpdateAlertRule(ctx context.Context, rule models.AlertRule) (updated *models.AlertRule, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.UpdateAlertRule")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.UpdateAlertRule(ctx, rule)
+}
+
+func (s tracingService) DeleteAlertRule(ctx context.Context, id int64) (deleted bool, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.DeleteAlertRule")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.DeleteAlertRule(ctx, id)
+}
+
+func (s tracingService) GetAlerts(ctx context.Context, filter models.AlertFilter) (alerts []models.Alert, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.GetAlerts")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.GetAlerts(ctx, filter)
+}
+
+func (s tracingService) AcknowledgeAlert(ctx context.Context, id int64) (alert *models.Alert, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.AcknowledgeAlert")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.AcknowledgeAlert(ctx, id)
+}
+
+func (s tracingService) ResolveAlert(ctx context.Context, id int64) (alert *models.Alert, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.ResolveAlert")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.ResolveAlert(ctx, id)
+}
+
+func (s tracingService) GetPoolStats(ctx context.Context) (stats *models.PoolStats, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.GetPoolStats")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.GetPoolStats(ctx)
+}
+
+func (s tracingService) GetWorkerStatus(ctx context.Context) (status *models.WorkerStatus, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.GetWorkerStatus")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.GetWorkerStatus(ctx)
+}
+
+func (s tracingService) UpdateWorkerSettings(ctx context.Context, settings models.WorkerSettings) (status *models.WorkerStatus, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.UpdateWorkerSettings")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	span.SetAttributes(attribute.Int("worker.count", settings.WorkerCount), attribute.Bool("worker.paused", settings.Paused))
+	return s.Service.UpdateWorkerSettings(ctx, settings)
+}
+
+func (s tracingService) GetLeaderStatus(ctx context.Context) (status *models.LeaderStatus, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.GetLeaderStatus")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.GetLeaderStatus(ctx)
+}
+
+func (s tracingService) RunRetention(ctx context.Context) (report *models.RetentionReport, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.RunRetention")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.RunRetention(ctx)
+}
+
+func (s tracingService) GetEventPartitions(ctx context.Context) (partitions []models.EventPartition, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.GetEventPartitions")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.GetEventPartitions(ctx)
+}
+
+func (s tracingService) MaintainPartitions(ctx context.Context) (report *models.PartitionReport, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.MaintainPartitions")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.MaintainPartitions(ctx)
+}
+
+func (s tracingService) AuthenticateAPIKey(ctx context.Context, key string) (apiKey *models.APIKey, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.AuthenticateAPIKey")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.AuthenticateAPIKey(ctx, key)
+}
+
+func (s tracingService) CreateAPIKey(ctx context.Context, name string, role string) (created *models.CreatedAPIKey, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.CreateAPIKey")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	span.SetAttributes(attribute.String("auth.role", role))
+	return s.Service.CreateAPIKey(ctx, name, role)
+}
+
+func (s tracingService) GetAPIKeys(ctx context.Context) (keys []models.APIKey, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.GetAPIKeys")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.GetAPIKeys(ctx)
+}
+
+func (s tracingService) RevokeAPIKey(ctx context.Context, id int64) (revoked bool, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.RevokeAPIKey")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.RevokeAPIKey(ctx, id)
+}
+
+func (s tracingService) GetAuditLog(ctx context.Context, limit int) (records []models.AuditRecord, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.GetAuditLog")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.GetAuditLog(ctx, limit)
+}
+
+func (s tracingService) VerifyMessageSignature(ctx context.Context, source string, timestamp string, signature string,
+	body []byte) (verified *models.MessageSource, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.VerifyMessageSignature")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	span.SetAttributes(attribute.String("message.source", source))
+	return s.Service.VerifyMessageSignature(ctx, source, timestamp, signature, body)
+}
+
+func (s tracingService) CreateMessageSource(ctx context.Context, name string) (created *models.CreatedMessageSource, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.CreateMessageSource")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.CreateMessageSource(ctx, name)
+}
+
+func (s tracingService) GetMessageSources(ctx context.Context) (sources []models.MessageSource, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.GetMessageSources")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.GetMessageSources(ctx)
+}
+
+func (s tracingService) RevokeMessageSource(ctx context.Context, id int64) (revoked bool, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.RevokeMessageSource")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.RevokeMessageSource(ctx, id)
+}
+
+func (s tracingService) SetAPIKeyRateLimit(ctx context.Context, id int64, limit *models.RateLimit) (updated bool, err error) {
+	ctx, span := tracing.StartSpan(ctx, "service.SetAPIKeyRateLimit")
+	defer func() { tracing.EndSpan(span, err) }()
+
+	return s.Service.SetAPIKeyRateLimit(ctx, id, limit)
+}
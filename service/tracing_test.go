@@ -0,0 +1,73 @@
+package service_test
+
+import (
+	"context"
+	"rockets-backend/models"
+	pkgContext "rockets-backend/pkg/context"
+	"rockets-backend/repository"
+	"rockets-backend/service"
+	"rockets-backend/testutil"
+	"rockets-backend/tracing"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/google/uuid"
+)
+
+func TestEventProcessingContinuesIngestTrace(t *testing.T) {
+	spans := testutil.SetupTracing(t)
+	repo := repository.NewMemoryRocketRepository()
+	svc := service.TracingMiddleware()(service.NewService(log.NewNopLogger(), repo))
+
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	ctx := tracing.WithTraceparent(context.Background(), "00-"+traceID+"-00f067aa0ba902b7-01")
+	ctx = pkgContext.WithRequestID(ctx, "req-1")
+
+	event, err := svc.IngestMessage(ctx, models.IncomingMessage{
+		Metadata: models.MessageMetadata{
+			Channel:       uuid.New().String(),
+			MessageNumber: 1,
+			MessageTime:   time.Now(),
+			MessageType:   "RocketLaunched",
+		},
+		Message: map[string]interface{}{"type": "Falcon-9", "launchSpeed": 500, "mission": "ARTEMIS"},
+	})
+	testutil.AssertNoError(t, err)
+
+	// The stored event carries the request ID and the ingest span as parent
+	stored, err := repo.GetRocketEvent(context.Background(), event.ID)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "req-1", *stored.RequestID)
+	ingest := testutil.FindSpan(t, spans, "service.IngestMessage")
+	testutil.AssertEqual(t, "00-"+traceID+"-"+ingest.SpanContext.SpanID().String()+"-01", *stored.TraceParent)
+
+	// Processing from a fresh context, as the event processor does, joins the same trace
+	processCtx := tracing.WithTraceparent(context.Background(), *stored.TraceParent)
+	outcome, err := svc.ProcessEvent(processCtx, stored)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, service.ProcessOutcomeProcessed, outcome)
+
+	process := testutil.FindSpan(t, spans, "service.ProcessEvent")
+	testutil.AssertEqual(t, traceID, process.SpanContext.TraceID().String())
+	testutil.AssertEqual(t, ingest.SpanContext.SpanID(), process.Parent.SpanID())
+}
+
+func TestIngestWithoutTraceStartsNewTrace(t *testing.T) {
+	testutil.SetupTracing(t)
+	repo := repository.NewMemoryRocketRepository()
+	svc := service.TracingMiddleware()(service.NewService(log.NewNopLogger(), repo))
+
+	event, err := svc.IngestMessage(context.Background(), models.IncomingMessage{
+		Metadata: models.MessageMetadata{Channel: uuid.New().String(), MessageNumber: 1, MessageType: "RocketLaunched"},
+		Message:  map[string]interface{}{},
+	})
+	testutil.AssertNoError(t, err)
+
+	if event.RequestID != nil {
+		t.Fatalf("Expected no request ID, got %s", *event.RequestID)
+	}
+	if event.TraceParent == nil {
+		t.Fatal("Expected the ingest span to start a new trace")
+	}
+}
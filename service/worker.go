@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"rockets-backend/models"
+	pkgContext "rockets-backend/pkg/context"
+
+	"github.com/go-kit/log/level"
+)
+
+var errWorkerControlUnavailable = errors.New("event processor not available")
+
+// WorkerController changes the event processor while it runs. Settings are shared by the
+// event processors of every instance.
+type WorkerController interface {
+	Status() models.WorkerStatus
+	// Refresh applies the shared settings if another instance changed them
+	Refresh(ctx context.Context) error
+	// Reconfigure changes the shared settings and applies them on this instance
+	Reconfigure(ctx context.Context, settings models.WorkerSettings) (models.WorkerStatus, error)
+}
+
+// WithWorkerControl exposes the event processor settings on the admin API. The
+// controller is looked up on every call because the event processor is created
+// after the service, it returns nil until then.
+func WithWorkerControl(controller func() WorkerController) Option {
+	return func(s *service) {
+		s.workerControl = controller
+	}
+}
+
+// worker returns the event processor controller, or nil if there is none
+func (s service) worker() WorkerController {
+	if s.workerControl == nil {
+		return nil
+	}
+	return s.workerControl()
+}
+
+func (s service) GetWorkerStatus(ctx context.Context) (*models.WorkerStatus, error) {
+	controller := s.worker()
+	if controller == nil {
+		return nil, errWorkerControlUnavailable
+	}
+	// Report the shared settings even if they changed since the last poll
+	if err := controller.Refresh(ctx); err != nil {
+		_ = level.Error(s.logger).Log("requestId", pkgContext.GetRequestID(ctx), "msg", "failed to refresh worker settings",
+			"error", err)
+		return nil, err
+	}
+	status := controller.Status()
+	status.Instance = s.instance
+	return &status, nil
+}
+
+// UpdateWorkerSettings replaces the event processor settings, including whether it is paused.
+// This instance applies them at once, the others at their next poll.
+func (s service) UpdateWorkerSettings(ctx context.Context, settings models.WorkerSettings) (*models.WorkerStatus, error) {
+	requestID := pkgContext.GetRequestID(ctx)
+	controller := s.worker()
+	if controller == nil {
+		return nil, errWorkerControlUnavailable
+	}
+
+	status, err := controller.Reconfigure(ctx, settings)
+	if err != nil {
+		_ = level.Error(s.logger).Log("requestId", requestID, "msg", "failed to update worker settings", "error", err)
+		return nil, err
+	}
+
+	_ = level.Info(s.logger).Log("requestId", requestID, "msg", "worker settings updated", "workers",
+		settings.WorkerCount, "pollIntervalMs", settings.PollIntervalMs, "batchSize", settings.BatchSize, "paused",
+		settings.Paused, "apiKey", callerName(ctx))
+	s.audit(ctx, "worker.update", "worker")
+
+	status.Instance = s.instance
+	return &status, nil
+}
@@ -89,13 +89,12 @@ print_status "Creating test database..."
 docker compose exec -T postgres psql -U postgres -c "DROP DATABASE IF EXISTS rockets_test;" 2>/dev/null || true
 docker compose exec -T postgres psql -U postgres -c "CREATE DATABASE rockets_test;" 
 
-# Apply schema to test database
-docker compose exec -T postgres psql -U postgres -d rockets_test < schema.sql
+# The schema is applied by the tests themselves from database/migrations
 
 print_status "Running integration tests..."
 
-# Run the tests
-if go test -v ./... -count=1; then
+# Run the tests, one package at a time since database tests share rockets_test
+if go test -p 1 -v ./... -count=1; then
     print_status "All tests passed!"
     exit_code=0
 else
@@ -1,9 +1,11 @@
 package testutil
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
+	"rockets-backend/database"
 	"rockets-backend/pkg"
 	"testing"
 
@@ -45,7 +47,7 @@ func CleanupTestDB(t *testing.T, db *sql.DB) {
 	t.Helper()
 
 	// Clean up test data in reverse dependency order
-	tables := []string{"rocket_events", "rockets"}
+	tables := []string{"worker_settings", "leader_leases", "source_channels", "message_sources", "audit_log", "api_keys", "alerts", "alert_rules", "rocket_status_history", "rocket_snapshots", "rocket_events_archive", "rocket_event_keys", "rocket_events", "rockets"}
 	for _, table := range tables {
 		_, err := db.Exec(fmt.Sprintf("DELETE FROM %s", table))
 		if err != nil {
@@ -54,46 +56,16 @@ func CleanupTestDB(t *testing.T, db *sql.DB) {
 	}
 }
 
-// setupTestSchema creates the test database schema
+// setupTestSchema applies the same migrations the service runs at startup
 func setupTestSchema(t *testing.T, db *sql.DB) {
 	t.Helper()
 
-	schema := `
-	CREATE TABLE IF NOT EXISTS rockets (
-		id UUID PRIMARY KEY,
-		type VARCHAR(100) NOT NULL,
-		current_speed INTEGER NOT NULL DEFAULT 0,
-		mission VARCHAR(255) NOT NULL,
-		status VARCHAR(50) NOT NULL DEFAULT 'active',
-		explosion_reason VARCHAR(255) NULL,
-		launch_time TIMESTAMP NOT NULL,
-		last_updated TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-		last_message_number INTEGER NOT NULL DEFAULT 0
-	);
-
-	CREATE TABLE IF NOT EXISTS rocket_events (
-		id SERIAL PRIMARY KEY,
-		channel UUID NOT NULL,
-		message_number INTEGER NOT NULL,
-		message_type VARCHAR(50) NOT NULL,
-		message_data JSONB NOT NULL,
-		received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-		processed_at TIMESTAMP NULL,
-		status VARCHAR(20) NOT NULL DEFAULT 'pending',
-		error_message TEXT NULL,
-		UNIQUE(channel, message_number)
-	);
-
-	CREATE INDEX IF NOT EXISTS idx_rockets_status ON rockets(status);
-	CREATE INDEX IF NOT EXISTS idx_rockets_last_updated ON rockets(last_updated);
-	CREATE INDEX IF NOT EXISTS idx_rockets_type ON rockets(type);
-	CREATE INDEX IF NOT EXISTS idx_rocket_events_status ON rocket_events(status);
-	CREATE INDEX IF NOT EXISTS idx_rocket_events_channel ON rocket_events(channel);
-	CREATE INDEX IF NOT EXISTS idx_rocket_events_received_at ON rocket_events(received_at);
-	`
-
-	_, err := db.Exec(schema)
+	migrator, err := database.NewMigrator(db)
 	if err != nil {
+		t.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	if _, err := migrator.Up(context.Background()); err != nil {
 		t.Fatalf("Failed to setup test schema: %v", err)
 	}
 }
@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the tracer every span of the service is started from
+const instrumentationName = "rockets-backend"
+
+// traceparentHeader is the W3C trace context header
+const traceparentHeader = "traceparent"
+
+// Exporter names accepted by Config.Exporter
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+)
+
+// Config holds configuration for tracing
+type Config struct {
+	Exporter    string  // Where finished spans are sent, none disables tracing
+	SampleRatio float64 // Fraction of new traces recorded, incoming traces keep their sampling decision
+}
+
+// NewExporter creates the span exporter named by the config. It returns nil for
+// ExporterNone, in which case tracing stays disabled.
+func NewExporter(config Config) (sdktrace.SpanExporter, error) {
+	switch config.Exporter {
+	case "", ExporterNone:
+		return nil, nil
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter: %s", config.Exporter)
+	}
+}
+
+// Setup installs a global tracer provider sending spans to exporter and the W3C
+// trace context propagator. The returned function flushes and stops the provider.
+// With a nil exporter only the propagator is installed and spans are not recorded,
+// incoming trace context is still passed on to stored events.
+func Setup(exporter sdktrace.SpanExporter, config Config) func(context.Context) error {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	if exporter == nil {
+		return func(context.Context) error { return nil }
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", instrumentationName))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown
+}
+
+// StartSpan starts a span as a child of the span or remote trace context in ctx
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// EndSpan records err on the span, if any, and ends it
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Traceparent returns the W3C traceparent of the current span in ctx, or "" without one
+func Traceparent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	return carrier.Get(traceparentHeader)
+}
+
+// WithTraceparent returns ctx continuing the trace of a W3C traceparent. An empty or
+// malformed traceparent leaves ctx unchanged, so a new trace is started.
+func WithTraceparent(ctx context.Context, traceparent string) context.Context {
+	if traceparent == "" {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier{traceparentHeader: traceparent})
+}
@@ -0,0 +1,87 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"rockets-backend/testutil"
+	"rockets-backend/tracing"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	testutil.SetupTracing(t)
+
+	tests := []struct {
+		name        string
+		traceparent string
+		want        string
+	}{
+		{
+			name:        "sampled",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			want:        "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		},
+		{
+			name:        "not sampled",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+			want:        "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+		},
+		{
+			name:        "malformed",
+			traceparent: "00-not-a-trace",
+		},
+		{
+			name:        "zero trace ID",
+			traceparent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tracing.WithTraceparent(context.Background(), tt.traceparent)
+			testutil.AssertEqual(t, tt.want, tracing.Traceparent(ctx))
+		})
+	}
+}
+
+func TestStartSpanContinuesTraceparent(t *testing.T) {
+	spans := testutil.SetupTracing(t)
+	ctx := tracing.WithTraceparent(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	ctx, span := tracing.StartSpan(ctx, "child")
+	tracing.EndSpan(span, errors.New("boom"))
+
+	recorded := testutil.FindSpan(t, spans, "child")
+	testutil.AssertEqual(t, "4bf92f3577b34da6a3ce929d0e0e4736", recorded.SpanContext.TraceID().String())
+	testutil.AssertEqual(t, "00f067aa0ba902b7", recorded.Parent.SpanID().String())
+	testutil.AssertEqual(t, codes.Error, recorded.Status.Code)
+	testutil.AssertEqual(t, "boom", recorded.Status.Description)
+
+	// The traceparent now points at the child span
+	want := "00-4bf92f3577b34da6a3ce929d0e0e4736-" + trace.SpanContextFromContext(ctx).SpanID().String() + "-01"
+	testutil.AssertEqual(t, want, tracing.Traceparent(ctx))
+}
+
+func TestNewExporter(t *testing.T) {
+	exporter, err := tracing.NewExporter(tracing.Config{Exporter: tracing.ExporterNone})
+	testutil.AssertNoError(t, err)
+	if exporter != nil {
+		t.Fatal("Expected no exporter when tracing is disabled")
+	}
+
+	exporter, err = tracing.NewExporter(tracing.Config{Exporter: tracing.ExporterStdout})
+	testutil.AssertNoError(t, err)
+	if exporter == nil {
+		t.Fatal("Expected a stdout exporter")
+	}
+
+	if _, err := tracing.NewExporter(tracing.Config{Exporter: "zipkin"}); err == nil {
+		t.Fatal("Expected an unknown exporter to fail")
+	}
+}
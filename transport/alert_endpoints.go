@@ -0,0 +1,112 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"rockets-backend/models"
+	"rockets-backend/service"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type AlertRuleRequest struct {
+	Rule models.AlertRule `json:"rule"`
+}
+
+type AlertIDRequest struct {
+	ID int64 `json:"id"`
+}
+
+type GetAlertsRequest struct {
+	Filter models.AlertFilter `json:"filter"`
+}
+
+func MakeCreateAlertRuleEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(AlertRuleRequest)
+		return svc.CreateAlertRule(ctx, req.Rule)
+	}
+}
+
+func MakeGetAlertRuleEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(AlertIDRequest)
+		rule, err := svc.GetAlertRule(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+		if rule == nil {
+			return nil, fmt.Errorf("alert rule not found")
+		}
+		return rule, nil
+	}
+}
+
+func MakeGetAlertRulesEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		return svc.GetAlertRules(ctx)
+	}
+}
+
+func MakeUpdateAlertRuleEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(AlertRuleRequest)
+		rule, err := svc.UpdateAlertRule(ctx, req.Rule)
+		if err != nil {
+			return nil, err
+		}
+		if rule == nil {
+			return nil, fmt.Errorf("alert rule not found")
+		}
+		return rule, nil
+	}
+}
+
+func MakeDeleteAlertRuleEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(AlertIDRequest)
+		deleted, err := svc.DeleteAlertRule(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !deleted {
+			return nil, fmt.Errorf("alert rule not found")
+		}
+		return map[string]interface{}{"status": "deleted", "id": req.ID}, nil
+	}
+}
+
+func MakeGetAlertsEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetAlertsRequest)
+		return svc.GetAlerts(ctx, req.Filter)
+	}
+}
+
+func MakeAcknowledgeAlertEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(AlertIDRequest)
+		alert, err := svc.AcknowledgeAlert(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+		if alert == nil {
+			return nil, fmt.Errorf("alert not found")
+		}
+		return alert, nil
+	}
+}
+
+func MakeResolveAlertEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(AlertIDRequest)
+		alert, err := svc.ResolveAlert(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+		if alert == nil {
+			return nil, fmt.Errorf("alert not found")
+		}
+		return alert, nil
+	}
+}
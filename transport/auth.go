@@ -0,0 +1,57 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"rockets-backend/models"
+	pkgContext "rockets-backend/pkg/context"
+)
+
+// Authenticator returns the active API key matching key, or nil if there is none
+type Authenticator func(ctx context.Context, key string) (*models.APIKey, error)
+
+var (
+	ErrMissingAPIKey = errors.New("missing API key")
+	ErrInvalidAPIKey = errors.New("invalid API key")
+
+	// ErrAuthUnavailable wraps a failed key lookup, clients only get this message
+	ErrAuthUnavailable = errors.New("authentication unavailable")
+
+	// ErrSignatureRequired rejects messages over gRPC and TCP while signatures are required,
+	// messages can only be signed over HTTP
+	ErrSignatureRequired = errors.New("messages must be signed, which is only supported over HTTP")
+)
+
+// RoleError rejects a valid API key whose role does not allow the call
+type RoleError struct {
+	Role     string
+	Required string
+}
+
+func (e *RoleError) Error() string {
+	return fmt.Sprintf("API key role %s is not allowed, %s is required", e.Role, e.Required)
+}
+
+// Authenticate looks up key and checks that its role allows required. The returned context
+// carries the key identity for logging, auditing and rate limiting. The API key is also
+// returned with a RoleError.
+func (a Authenticator) Authenticate(ctx context.Context, key, required string) (context.Context, *models.APIKey, error) {
+	if key == "" {
+		return ctx, nil, ErrMissingAPIKey
+	}
+	apiKey, err := a(ctx, key)
+	if err != nil {
+		return ctx, nil, fmt.Errorf("%w: %v", ErrAuthUnavailable, err)
+	}
+	if apiKey == nil {
+		return ctx, nil, ErrInvalidAPIKey
+	}
+	if !models.RoleAllows(apiKey.Role, required) {
+		return ctx, apiKey, &RoleError{Role: apiKey.Role, Required: required}
+	}
+
+	ctx = pkgContext.WithCaller(ctx, pkgContext.Caller{KeyID: apiKey.ID, KeyName: apiKey.Name, Role: apiKey.Role,
+		RateLimit: apiKey.RateLimit})
+	return ctx, apiKey, nil
+}
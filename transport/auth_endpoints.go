@@ -0,0 +1,77 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"rockets-backend/models"
+	"rockets-backend/service"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type CreateAPIKeyRequest struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+type APIKeyIDRequest struct {
+	ID int64 `json:"id"`
+}
+
+// SetAPIKeyRateLimitRequest restores the default rate limit when Limit is nil
+type SetAPIKeyRateLimitRequest struct {
+	ID    int64             `json:"id"`
+	Limit *models.RateLimit `json:"limit"`
+}
+
+type GetAuditLogRequest struct {
+	Limit int `json:"limit"`
+}
+
+func MakeCreateAPIKeyEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(CreateAPIKeyRequest)
+		return svc.CreateAPIKey(ctx, req.Name, req.Role)
+	}
+}
+
+func MakeGetAPIKeysEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		return svc.GetAPIKeys(ctx)
+	}
+}
+
+func MakeRevokeAPIKeyEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(APIKeyIDRequest)
+		revoked, err := svc.RevokeAPIKey(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !revoked {
+			return nil, fmt.Errorf("api key not found")
+		}
+		return map[string]interface{}{"status": "revoked", "id": req.ID}, nil
+	}
+}
+
+func MakeSetAPIKeyRateLimitEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(SetAPIKeyRateLimitRequest)
+		updated, err := svc.SetAPIKeyRateLimit(ctx, req.ID, req.Limit)
+		if err != nil {
+			return nil, err
+		}
+		if !updated {
+			return nil, fmt.Errorf("api key not found")
+		}
+		return map[string]interface{}{"id": req.ID, "rateLimit": req.Limit}, nil
+	}
+}
+
+func MakeGetAuditLogEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetAuditLogRequest)
+		return svc.GetAuditLog(ctx, req.Limit)
+	}
+}
@@ -0,0 +1,53 @@
+package consumer_transport
+
+import (
+	"context"
+	"io"
+	"rockets-backend/models"
+	"sync/atomic"
+)
+
+// ChannelSource reads messages published in-process on a Go channel. Offsets count the
+// messages received, starting at 1. Closing the channel ends the source.
+type ChannelSource struct {
+	name      string
+	messages  <-chan models.IncomingMessage
+	received  int64
+	committed atomic.Int64
+}
+
+func NewChannelSource(name string, messages <-chan models.IncomingMessage) *ChannelSource {
+	return &ChannelSource{name: name, messages: messages}
+}
+
+func (s *ChannelSource) Name() string {
+	return s.name
+}
+
+func (s *ChannelSource) Fetch(ctx context.Context) (Record, error) {
+	select {
+	case <-ctx.Done():
+		return Record{}, ctx.Err()
+	case msg, ok := <-s.messages:
+		if !ok {
+			return Record{}, io.EOF
+		}
+		s.received++
+		return Record{Offset: s.received, Message: msg}, nil
+	}
+}
+
+func (s *ChannelSource) Commit(ctx context.Context, offset int64) error {
+	s.committed.Store(offset)
+	return nil
+}
+
+// Committed returns the number of messages stored or skipped so far. A producer can
+// wait for it to reach the number of messages it sent.
+func (s *ChannelSource) Committed() int64 {
+	return s.committed.Load()
+}
+
+func (s *ChannelSource) Close() error {
+	return nil
+}
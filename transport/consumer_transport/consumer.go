@@ -0,0 +1,263 @@
+// Package consumer_transport ingests messages read from a Source, such as a tailed file
+// or a message bus partition, the way http_transport ingests POST /messages.
+package consumer_transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"rockets-backend/metrics"
+	"rockets-backend/models"
+	pkgContext "rockets-backend/pkg/context"
+	"rockets-backend/service"
+	"rockets-backend/tracing"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultRetryBackoff    = 500 * time.Millisecond
+	defaultMaxRetryBackoff = 30 * time.Second
+)
+
+// Consumer outcomes of a record, the result label of rockets_consumer_records_total
+const (
+	resultIngested = "ingested"
+	resultRejected = "rejected"
+	resultInvalid  = "invalid"
+)
+
+// Record is a message read from a source. Offset is the position to commit once the
+// message is stored. Err is set for a record the source could not decode, it is skipped.
+type Record struct {
+	Offset  int64
+	Message models.IncomingMessage
+	Err     error
+}
+
+// Source is an ordered stream of incoming messages. Fetch and Commit are only called
+// from the consumer's goroutine.
+type Source interface {
+	// Name identifies the source in logs and metrics
+	Name() string
+	// Fetch blocks until the next record is available. It returns io.EOF once the
+	// source has no more records, and the context error when ctx is done.
+	Fetch(ctx context.Context) (Record, error)
+	// Commit marks every record up to and including offset as stored, so they are not
+	// fetched again after a restart
+	Commit(ctx context.Context, offset int64) error
+	Close() error
+}
+
+// Option configures a Consumer
+type Option func(*Consumer)
+
+// WithRetryBackoff sets the first and the longest wait before a failed message is tried
+// again. Rate limited messages wait as long as the limit asks instead.
+func WithRetryBackoff(initial, max time.Duration) Option {
+	return func(c *Consumer) {
+		c.retryBackoff = initial
+		c.maxRetryBackoff = max
+	}
+}
+
+// Consumer feeds the records of a source to the ingest endpoint one at a time, and commits
+// a record once it is stored. A record is retried until it is stored or rejected, so the
+// source's order is kept. After a crash the records since the last commit are ingested
+// again, which the (channel, messageNumber) deduplication makes harmless.
+type Consumer struct {
+	source          Source
+	ingest          endpoint.Endpoint
+	m               *metrics.Metrics
+	logger          log.Logger
+	retryBackoff    time.Duration
+	maxRetryBackoff time.Duration
+	cancel          context.CancelFunc
+	wg              sync.WaitGroup
+	running         bool
+	mu              sync.RWMutex
+}
+
+// NewConsumer creates a consumer of source for the ProcessMessage endpoint. The consumer
+// owns the source and closes it on Stop.
+func NewConsumer(source Source, ingest endpoint.Endpoint, m *metrics.Metrics, logger log.Logger,
+	opts ...Option) *Consumer {
+	c := &Consumer{
+		source:          source,
+		ingest:          ingest,
+		m:               m,
+		logger:          log.With(logger, "source", source.Name()),
+		retryBackoff:    defaultRetryBackoff,
+		maxRetryBackoff: defaultMaxRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Start consumes the source in the background
+func (c *Consumer) Start(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.running {
+		return nil // Already running
+	}
+
+	// Cancelled on Stop so that a blocked fetch or a retry wait returns
+	ctx, c.cancel = context.WithCancel(ctx)
+
+	c.running = true
+	_ = level.Info(c.logger).Log("msg", "starting consumer")
+
+	c.wg.Add(1)
+	go c.loop(ctx)
+
+	return nil
+}
+
+// Stop shuts down the consumer and closes the source. A record that is not stored yet is
+// not committed, it is fetched again on the next start.
+func (c *Consumer) Stop() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.running {
+		return nil // Already stopped
+	}
+
+	c.cancel()
+	c.wg.Wait()
+
+	c.running = false
+	_ = level.Info(c.logger).Log("msg", "consumer stopped")
+
+	return c.source.Close()
+}
+
+// IsRunning returns whether the consumer is currently running
+func (c *Consumer) IsRunning() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.running
+}
+
+func (c *Consumer) loop(ctx context.Context) {
+	defer c.wg.Done()
+
+	backoff := c.retryBackoff
+	for {
+		record, err := c.source.Fetch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, io.EOF) {
+			_ = level.Info(c.logger).Log("msg", "source has no more records")
+			return
+		}
+		if err != nil {
+			_ = level.Error(c.logger).Log("msg", "failed to fetch record", "retryIn", backoff, "error", err)
+			if !sleep(ctx, backoff) {
+				return
+			}
+			backoff = min(2*backoff, c.maxRetryBackoff)
+			continue
+		}
+		backoff = c.retryBackoff
+
+		if !c.handle(ctx, record) {
+			return
+		}
+		if err := c.source.Commit(ctx, record.Offset); err != nil {
+			// A later commit covers this record, until then it would be ingested again
+			_ = level.Error(c.logger).Log("msg", "failed to commit offset", "offset", record.Offset, "error", err)
+		}
+	}
+}
+
+// handle ingests a record, retrying until it is stored or rejected. Returns false when ctx
+// is done first, the record must not be committed then.
+func (c *Consumer) handle(ctx context.Context, record Record) bool {
+	ctx = pkgContext.WithRequestID(ctx, pkgContext.GenerateRequestID())
+	requestID := pkgContext.GetRequestID(ctx)
+
+	if record.Err != nil {
+		_ = level.Warn(c.logger).Log("requestId", requestID, "msg", "skipping invalid record",
+			"offset", record.Offset, "error", record.Err)
+		c.count(resultInvalid)
+		return true
+	}
+
+	backoff := c.retryBackoff
+	for {
+		err := c.ingestRecord(ctx, record)
+		if err == nil {
+			c.count(resultIngested)
+			return true
+		}
+
+		wait := backoff
+		var limitErr *service.RateLimitError
+		if errors.As(err, &limitErr) {
+			wait = limitErr.RetryAfter
+		} else if errors.Is(err, service.ErrInvalidMessage) {
+			_ = level.Warn(c.logger).Log("requestId", requestID, "msg", "skipping invalid message",
+				"offset", record.Offset, "channel", record.Message.Metadata.Channel,
+				"messageNumber", record.Message.Metadata.MessageNumber, "error", err)
+			c.count(resultInvalid)
+			return true
+		} else if service.IsChannelRejected(err) {
+			_ = level.Warn(c.logger).Log("requestId", requestID, "msg", "skipping rejected message",
+				"offset", record.Offset, "channel", record.Message.Metadata.Channel,
+				"messageNumber", record.Message.Metadata.MessageNumber, "error", err)
+			c.count(resultRejected)
+			return true
+		} else {
+			backoff = min(2*backoff, c.maxRetryBackoff)
+		}
+
+		_ = level.Warn(c.logger).Log("requestId", requestID, "msg", "failed to ingest message, retrying",
+			"offset", record.Offset, "retryIn", wait, "error", err)
+		if !sleep(ctx, wait) {
+			return false
+		}
+	}
+}
+
+// ingestRecord calls the endpoint in a span of its own, which the event processor continues
+func (c *Consumer) ingestRecord(ctx context.Context, record Record) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "consume "+c.source.Name(), trace.WithSpanKind(trace.SpanKindConsumer))
+	defer func() { tracing.EndSpan(span, err) }()
+	span.SetAttributes(
+		attribute.String("consumer.source", c.source.Name()),
+		attribute.Int64("consumer.offset", record.Offset),
+	)
+
+	_, err = c.ingest(ctx, record.Message)
+	return err
+}
+
+func (c *Consumer) count(result string) {
+	if c.m != nil {
+		c.m.ConsumerRecords.With("source", c.source.Name(), "result", result).Add(1)
+	}
+}
+
+// sleep waits for d, returns false when ctx is done first
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,135 @@
+package consumer_transport_test
+
+import (
+	"context"
+	"errors"
+	"rockets-backend/metrics"
+	"rockets-backend/models"
+	"rockets-backend/ratelimit"
+	"rockets-backend/repository"
+	"rockets-backend/service"
+	"rockets-backend/testutil"
+	"rockets-backend/transport"
+	"rockets-backend/transport/consumer_transport"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+)
+
+const testChannel = "193270a9-c9cf-404a-8f83-838e71d9ae67"
+
+func speedMessage(number int) models.IncomingMessage {
+	return models.IncomingMessage{
+		Metadata: models.MessageMetadata{
+			Channel:       testChannel,
+			MessageNumber: number,
+			MessageTime:   time.Date(2022, 2, 2, 19, 39, 5, 0, time.UTC),
+			MessageType:   "RocketSpeedIncreased",
+		},
+		Message: map[string]interface{}{"by": 100},
+	}
+}
+
+// waitCommitted waits until the source committed offset, or fails the test
+func waitCommitted(t *testing.T, source *consumer_transport.ChannelSource, offset int64) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for source.Committed() < offset {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected offset %d to be committed, got %d", offset, source.Committed())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestConsumerIngestsInOrder(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRocketRepository()
+	// Every second message of the channel is rate limited at first
+	svc := service.NewService(log.NewNopLogger(), repo,
+		service.WithIngestLimits(service.IngestLimits{Channel: ratelimit.Limit{Rate: 50, Burst: 1}}))
+
+	messages := make(chan models.IncomingMessage, 3)
+	source := consumer_transport.NewChannelSource("test", messages)
+	consumer := consumer_transport.NewConsumer(source, transport.MakeEndpoints(svc).ProcessMessage, metrics.New(),
+		log.NewNopLogger(), consumer_transport.WithRetryBackoff(time.Millisecond, 10*time.Millisecond))
+	testutil.AssertNoError(t, consumer.Start(ctx))
+	t.Cleanup(func() { consumer.Stop() })
+
+	for number := 1; number <= 3; number++ {
+		messages <- speedMessage(number)
+	}
+	waitCommitted(t, source, 3)
+
+	events, err := repo.GetPendingEvents(ctx, 10)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 3, len(events))
+	for i, event := range events {
+		testutil.AssertEqual(t, i+1, event.MessageNumber)
+	}
+
+	// A closed channel ends the source
+	close(messages)
+	testutil.AssertNoError(t, consumer.Stop())
+}
+
+func TestConsumerCommitsOnlyStoredMessages(t *testing.T) {
+	ctx := context.Background()
+	failing := func(ctx context.Context, request interface{}) (interface{}, error) {
+		return nil, errors.New("failed to store rocket event: connection refused")
+	}
+
+	messages := make(chan models.IncomingMessage, 1)
+	source := consumer_transport.NewChannelSource("test", messages)
+	consumer := consumer_transport.NewConsumer(source, failing, metrics.New(), log.NewNopLogger(),
+		consumer_transport.WithRetryBackoff(time.Millisecond, 5*time.Millisecond))
+	testutil.AssertNoError(t, consumer.Start(ctx))
+
+	messages <- speedMessage(1)
+	time.Sleep(50 * time.Millisecond)
+	testutil.AssertNoError(t, consumer.Stop())
+	testutil.AssertEqual(t, int64(0), source.Committed())
+}
+
+func TestConsumerSkipsRejectedMessages(t *testing.T) {
+	ctx := context.Background()
+	rejecting := func(ctx context.Context, request interface{}) (interface{}, error) {
+		return nil, service.ErrChannelRequiresSignature
+	}
+
+	messages := make(chan models.IncomingMessage, 2)
+	source := consumer_transport.NewChannelSource("test", messages)
+	consumer := consumer_transport.NewConsumer(source, rejecting, metrics.New(), log.NewNopLogger())
+	testutil.AssertNoError(t, consumer.Start(ctx))
+	t.Cleanup(func() { consumer.Stop() })
+
+	messages <- speedMessage(1)
+	messages <- speedMessage(2)
+	waitCommitted(t, source, 2)
+}
+
+func TestConsumerSkipsInvalidMessages(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRocketRepository()
+	svc := service.NewService(log.NewNopLogger(), repo)
+
+	messages := make(chan models.IncomingMessage, 2)
+	source := consumer_transport.NewChannelSource("test", messages)
+	consumer := consumer_transport.NewConsumer(source, transport.MakeEndpoints(svc).ProcessMessage, metrics.New(),
+		log.NewNopLogger(), consumer_transport.WithRetryBackoff(time.Millisecond, 10*time.Millisecond))
+	testutil.AssertNoError(t, consumer.Start(ctx))
+	t.Cleanup(func() { consumer.Stop() })
+
+	// The poison message can never be stored, it must not hold back the next one
+	poison := speedMessage(1)
+	poison.Metadata.Channel = "not-a-uuid"
+	messages <- poison
+	messages <- speedMessage(2)
+	waitCommitted(t, source, 2)
+
+	events, err := repo.GetPendingEvents(ctx, 10)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, len(events))
+	testutil.AssertEqual(t, 2, events[0].MessageNumber)
+}
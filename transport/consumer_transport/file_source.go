@@ -0,0 +1,199 @@
+package consumer_transport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileSource tails a file of newline-delimited JSON messages, one IncomingMessage per line.
+// Offsets are byte positions after a line. The committed offset is kept in a separate
+// file, so a restart resumes after the last stored message.
+//
+// Like tail -F, the source waits for the file to be created, and starts over from the
+// beginning when the file is truncated or replaced, e.g. by log rotation. A line is only
+// read once its newline is written.
+type FileSource struct {
+	path         string
+	offsetPath   string
+	pollInterval time.Duration
+
+	file     *os.File
+	reader   *bufio.Reader
+	position int64  // offset after the last line read
+	partial  []byte // start of a line whose newline is not written yet
+}
+
+// NewFileSource creates a source tailing path from the offset committed to offsetPath,
+// checking for new lines every pollInterval
+func NewFileSource(path, offsetPath string, pollInterval time.Duration) (*FileSource, error) {
+	position, err := readOffset(offsetPath)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSource{
+		path:         path,
+		offsetPath:   offsetPath,
+		pollInterval: pollInterval,
+		position:     position,
+	}, nil
+}
+
+func (s *FileSource) Name() string {
+	return "file:" + filepath.Base(s.path)
+}
+
+func (s *FileSource) Fetch(ctx context.Context) (Record, error) {
+	for {
+		if s.file == nil {
+			if err := s.open(); err != nil {
+				return Record{}, err
+			}
+		}
+
+		if s.file != nil {
+			line, err := s.reader.ReadBytes('\n')
+			s.partial = append(s.partial, line...)
+			if err == nil {
+				record, ok := s.record()
+				if ok {
+					return record, nil
+				}
+				continue // blank line
+			}
+			if !errors.Is(err, io.EOF) {
+				return Record{}, fmt.Errorf("failed to read %s: %w", s.path, err)
+			}
+			if err := s.checkReplaced(); err != nil {
+				return Record{}, err
+			}
+			if s.file == nil {
+				continue // read the new file right away
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return Record{}, ctx.Err()
+		case <-time.After(s.pollInterval):
+		}
+	}
+}
+
+// record decodes the complete line in partial
+func (s *FileSource) record() (Record, bool) {
+	line := s.partial
+	s.partial = nil
+	s.position += int64(len(line))
+
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return Record{}, false
+	}
+
+	record := Record{Offset: s.position}
+	if err := json.Unmarshal(line, &record.Message); err != nil {
+		record.Err = fmt.Errorf("invalid message before offset %d: %w", s.position, err)
+	}
+	return record, true
+}
+
+// open opens the file at the current position, leaving s.file nil while it does not exist
+func (s *FileSource) open() error {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", s.path, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open %s: %w", s.path, err)
+	}
+	if info.Size() < s.position {
+		s.position = 0 // truncated while we were not reading it
+	}
+	if _, err := file.Seek(s.position, io.SeekStart); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to seek %s: %w", s.path, err)
+	}
+
+	s.file = file
+	s.reader = bufio.NewReader(file)
+	s.partial = nil
+	return nil
+}
+
+// checkReplaced closes the file at its end when it was truncated, or when another file
+// took its place, so the next fetch starts the new content from the beginning
+func (s *FileSource) checkReplaced() error {
+	current, err := s.file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", s.path, err)
+	}
+	latest, err := os.Stat(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil // removed, the new file is picked up once it is created
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", s.path, err)
+	}
+
+	truncated := current.Size() < s.position+int64(len(s.partial))
+	if truncated || !os.SameFile(current, latest) {
+		s.file.Close()
+		s.file = nil
+		s.position = 0
+	}
+	return nil
+}
+
+// Commit writes offset to the offset file. The file is replaced in one rename, so a crash
+// leaves either the previous or the new offset.
+func (s *FileSource) Commit(ctx context.Context, offset int64) error {
+	tmp := s.offsetPath + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatInt(offset, 10)+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to write offset: %w", err)
+	}
+	if err := os.Rename(tmp, s.offsetPath); err != nil {
+		return fmt.Errorf("failed to write offset: %w", err)
+	}
+	return nil
+}
+
+func (s *FileSource) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// readOffset returns the committed offset, 0 when nothing was committed yet
+func readOffset(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read offset: %w", err)
+	}
+	offset, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid offset in %s: %q", path, data)
+	}
+	return offset, nil
+}
@@ -0,0 +1,96 @@
+package consumer_transport_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"rockets-backend/testutil"
+	"rockets-backend/transport/consumer_transport"
+	"testing"
+	"time"
+)
+
+// appendLater appends to the file after the source started waiting for it
+func appendLater(t *testing.T, path, data string) {
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err == nil {
+			_, err = file.WriteString(data)
+			file.Close()
+		}
+		if err != nil {
+			t.Errorf("failed to append to %s: %v", path, err)
+		}
+	}()
+}
+
+func encodedMessage(t *testing.T, number int) string {
+	t.Helper()
+	data, err := json.Marshal(speedMessage(number))
+	testutil.AssertNoError(t, err)
+	return string(data)
+}
+
+func TestFileSource(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "messages.ndjson")
+	offsets := filepath.Join(dir, "messages.offset")
+
+	fetch := func(source *consumer_transport.FileSource) consumer_transport.Record {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		defer cancel()
+		record, err := source.Fetch(ctx)
+		testutil.AssertNoError(t, err)
+		return record
+	}
+
+	source, err := consumer_transport.NewFileSource(path, offsets, 5*time.Millisecond)
+	testutil.AssertNoError(t, err)
+	t.Cleanup(func() { source.Close() })
+
+	// The file does not exist yet, and the last line is not complete
+	appendLater(t, path, encodedMessage(t, 1)+"\n\n{not json}\n"+encodedMessage(t, 2))
+	first := fetch(source)
+	testutil.AssertNoError(t, first.Err)
+	testutil.AssertEqual(t, 1, first.Message.Metadata.MessageNumber)
+
+	invalid := fetch(source)
+	if invalid.Err == nil {
+		t.Fatal("Expected an error for the invalid line")
+	}
+	testutil.AssertNoError(t, source.Commit(ctx, invalid.Offset))
+
+	appendLater(t, path, "\n")
+	second := fetch(source)
+	testutil.AssertEqual(t, 2, second.Message.Metadata.MessageNumber)
+	testutil.AssertNoError(t, source.Close())
+
+	t.Run("resumes after the committed offset", func(t *testing.T) {
+		resumed, err := consumer_transport.NewFileSource(path, offsets, 5*time.Millisecond)
+		testutil.AssertNoError(t, err)
+		defer resumed.Close()
+
+		record := fetch(resumed)
+		testutil.AssertEqual(t, 2, record.Message.Metadata.MessageNumber)
+		testutil.AssertEqual(t, second.Offset, record.Offset)
+	})
+
+	t.Run("starts over when the file is replaced", func(t *testing.T) {
+		resumed, err := consumer_transport.NewFileSource(path, offsets, 5*time.Millisecond)
+		testutil.AssertNoError(t, err)
+		defer resumed.Close()
+		fetch(resumed)
+
+		rotated := filepath.Join(dir, "messages.ndjson.1")
+		testutil.AssertNoError(t, os.Rename(path, rotated))
+		appendLater(t, path, encodedMessage(t, 3)+"\n")
+
+		record := fetch(resumed)
+		testutil.AssertEqual(t, 3, record.Message.Metadata.MessageNumber)
+		testutil.AssertEqual(t, int64(len(encodedMessage(t, 3))+1), record.Offset)
+	})
+}
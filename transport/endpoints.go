@@ -11,19 +11,89 @@ import (
 
 type Endpoints struct {
 	HealthCheck    endpoint.Endpoint
+	Liveness       endpoint.Endpoint
+	Readiness      endpoint.Endpoint
 	ProcessMessage endpoint.Endpoint
 	GetRocket      endpoint.Endpoint
 	GetAllRockets  endpoint.Endpoint
 	GetEventStatus endpoint.Endpoint
+
+	GetRocketStats   endpoint.Endpoint
+	GetRocketHistory endpoint.Endpoint
+	ReplayRocket     endpoint.Endpoint
+	VerifySnapshots  endpoint.Endpoint
+
+	CreateAlertRule  endpoint.Endpoint
+	GetAlertRule     endpoint.Endpoint
+	GetAlertRules    endpoint.Endpoint
+	UpdateAlertRule  endpoint.Endpoint
+	DeleteAlertRule  endpoint.Endpoint
+	GetAlerts        endpoint.Endpoint
+	AcknowledgeAlert endpoint.Endpoint
+	ResolveAlert     endpoint.Endpoint
+
+	GetPoolStats         endpoint.Endpoint
+	GetWorkerStatus      endpoint.Endpoint
+	UpdateWorkerSettings endpoint.Endpoint
+	GetLeaderStatus      endpoint.Endpoint
+	RunRetention         endpoint.Endpoint
+	GetEventPartitions   endpoint.Endpoint
+	MaintainPartitions   endpoint.Endpoint
+
+	CreateAPIKey endpoint.Endpoint
+	GetAPIKeys   endpoint.Endpoint
+	RevokeAPIKey endpoint.Endpoint
+	GetAuditLog  endpoint.Endpoint
+
+	SetAPIKeyRateLimit endpoint.Endpoint
+
+	CreateMessageSource endpoint.Endpoint
+	GetMessageSources   endpoint.Endpoint
+	RevokeMessageSource endpoint.Endpoint
 }
 
 func MakeEndpoints(svc service.Service) Endpoints {
 	return Endpoints{
 		HealthCheck:    MakeHealthCheckEndpoint(svc),
+		Liveness:       MakeLivenessEndpoint(svc),
+		Readiness:      MakeReadinessEndpoint(svc),
 		ProcessMessage: MakeProcessMessageEndpoint(svc),
 		GetRocket:      MakeGetRocketEndpoint(svc),
 		GetAllRockets:  MakeGetAllRocketsEndpoint(svc),
 		GetEventStatus: MakeGetEventStatusEndpoint(svc),
+
+		GetRocketStats:   MakeGetRocketStatsEndpoint(svc),
+		GetRocketHistory: MakeGetRocketHistoryEndpoint(svc),
+		ReplayRocket:     MakeReplayRocketEndpoint(svc),
+		VerifySnapshots:  MakeVerifySnapshotsEndpoint(svc),
+
+		CreateAlertRule:  MakeCreateAlertRuleEndpoint(svc),
+		GetAlertRule:     MakeGetAlertRuleEndpoint(svc),
+		GetAlertRules:    MakeGetAlertRulesEndpoint(svc),
+		UpdateAlertRule:  MakeUpdateAlertRuleEndpoint(svc),
+		DeleteAlertRule:  MakeDeleteAlertRuleEndpoint(svc),
+		GetAlerts:        MakeGetAlertsEndpoint(svc),
+		AcknowledgeAlert: MakeAcknowledgeAlertEndpoint(svc),
+		ResolveAlert:     MakeResolveAlertEndpoint(svc),
+
+		GetPoolStats:         MakeGetPoolStatsEndpoint(svc),
+		GetWorkerStatus:      MakeGetWorkerStatusEndpoint(svc),
+		UpdateWorkerSettings: MakeUpdateWorkerSettingsEndpoint(svc),
+		GetLeaderStatus:      MakeGetLeaderStatusEndpoint(svc),
+		RunRetention:         MakeRunRetentionEndpoint(svc),
+		GetEventPartitions:   MakeGetEventPartitionsEndpoint(svc),
+		MaintainPartitions:   MakeMaintainPartitionsEndpoint(svc),
+
+		CreateAPIKey: MakeCreateAPIKeyEndpoint(svc),
+		GetAPIKeys:   MakeGetAPIKeysEndpoint(svc),
+		RevokeAPIKey: MakeRevokeAPIKeyEndpoint(svc),
+		GetAuditLog:  MakeGetAuditLogEndpoint(svc),
+
+		SetAPIKeyRateLimit: MakeSetAPIKeyRateLimitEndpoint(svc),
+
+		CreateMessageSource: MakeCreateMessageSourceEndpoint(svc),
+		GetMessageSources:   MakeGetMessageSourcesEndpoint(svc),
+		RevokeMessageSource: MakeRevokeMessageSourceEndpoint(svc),
 	}
 }
 
@@ -33,6 +103,18 @@ func MakeHealthCheckEndpoint(svc service.Service) endpoint.Endpoint {
 	}
 }
 
+func MakeLivenessEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		return svc.Liveness(ctx), nil
+	}
+}
+
+func MakeReadinessEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		return svc.Readiness(ctx), nil
+	}
+}
+
 func MakeProcessMessageEndpoint(svc service.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(models.IncomingMessage)
@@ -66,13 +148,14 @@ func MakeGetRocketEndpoint(svc service.Service) endpoint.Endpoint {
 }
 
 type GetAllRocketsRequest struct {
-	SortBy string `json:"sortBy"`
+	SortBy string              `json:"sortBy"`
+	Filter models.RocketFilter `json:"filter"`
 }
 
 func MakeGetAllRocketsEndpoint(svc service.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(GetAllRocketsRequest)
-		rockets, err := svc.GetAllRockets(ctx, req.SortBy)
+		rockets, err := svc.GetAllRockets(ctx, req.SortBy, req.Filter)
 		if err != nil {
 			return nil, err
 		}
@@ -80,6 +163,94 @@ func MakeGetAllRocketsEndpoint(svc service.Service) endpoint.Endpoint {
 	}
 }
 
+func MakeGetRocketStatsEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		return svc.GetRocketStats(ctx)
+	}
+}
+
+func MakeGetPoolStatsEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		return svc.GetPoolStats(ctx)
+	}
+}
+
+func MakeGetWorkerStatusEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		return svc.GetWorkerStatus(ctx)
+	}
+}
+
+func MakeUpdateWorkerSettingsEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		return svc.UpdateWorkerSettings(ctx, request.(models.WorkerSettings))
+	}
+}
+
+func MakeGetLeaderStatusEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		return svc.GetLeaderStatus(ctx)
+	}
+}
+
+func MakeRunRetentionEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		return svc.RunRetention(ctx)
+	}
+}
+
+func MakeGetEventPartitionsEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		return svc.GetEventPartitions(ctx)
+	}
+}
+
+func MakeMaintainPartitionsEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		return svc.MaintainPartitions(ctx)
+	}
+}
+
+func MakeGetRocketHistoryEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetRocketRequest)
+		rocket, err := svc.GetRocket(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+		if rocket == nil {
+			return nil, fmt.Errorf("rocket not found")
+		}
+		return svc.GetRocketHistory(ctx, req.ID)
+	}
+}
+
+type ReplayRocketRequest struct {
+	ID            string `json:"id"`
+	MessageNumber int    `json:"messageNumber"` // 0 replays every message
+}
+
+func MakeReplayRocketEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ReplayRocketRequest)
+		return svc.ReplayRocket(ctx, req.ID, req.MessageNumber)
+	}
+}
+
+func MakeVerifySnapshotsEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetRocketRequest)
+		rocket, err := svc.GetRocket(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+		if rocket == nil {
+			return nil, fmt.Errorf("rocket not found")
+		}
+		return svc.VerifySnapshots(ctx, req.ID)
+	}
+}
+
 type GetEventStatusRequest struct {
 	EventID int64 `json:"event_id"`
 }
@@ -0,0 +1,161 @@
+package grpc_transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"rockets-backend/models"
+	"rockets-backend/transport"
+	"rockets-backend/transport/grpc_transport/pb"
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func decodeIngestRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.IngestRequest)
+	metadata := req.GetMetadata()
+	msg := models.IncomingMessage{
+		Metadata: models.MessageMetadata{
+			Channel:       metadata.GetChannel(),
+			MessageNumber: int(metadata.GetMessageNumber()),
+			MessageType:   metadata.GetMessageType(),
+		},
+	}
+	if metadata.GetMessageTime() != nil {
+		msg.Metadata.MessageTime = metadata.GetMessageTime().AsTime()
+	}
+	if req.GetMessage() != nil {
+		msg.Message = req.GetMessage().AsMap()
+	}
+	return msg, nil
+}
+
+func encodeIngestResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp, _ := response.(map[string]interface{})
+	status, statusOK := resp["status"].(string)
+	eventID, eventOK := resp["event_id"].(int64)
+	if !statusOK || !eventOK {
+		return nil, fmt.Errorf("unexpected ingest response: %v", response)
+	}
+	return &pb.IngestReply{Status: status, EventId: eventID}, nil
+}
+
+func decodeGetRocketRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	return transport.GetRocketRequest{ID: grpcReq.(*pb.GetRocketRequest).GetId()}, nil
+}
+
+func encodeRocketResponse(_ context.Context, response interface{}) (interface{}, error) {
+	return encodeRocket(response.(*models.Rocket)), nil
+}
+
+func decodeListRocketsRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.ListRocketsRequest)
+	filter := models.RocketFilter{
+		Status:       req.GetStatus(),
+		MinAltitude:  intPtr(req.MinAltitude),
+		MaxAltitude:  intPtr(req.MaxAltitude),
+		MinLatitude:  req.MinLatitude,
+		MaxLatitude:  req.MaxLatitude,
+		MinLongitude: req.MinLongitude,
+		MaxLongitude: req.MaxLongitude,
+		MinFuelLevel: req.MinFuelLevel,
+		MaxFuelLevel: req.MaxFuelLevel,
+	}
+	return transport.GetAllRocketsRequest{SortBy: req.GetSortBy(), Filter: filter}, nil
+}
+
+func encodeListRocketsResponse(_ context.Context, response interface{}) (interface{}, error) {
+	rockets := response.([]models.Rocket)
+	reply := &pb.ListRocketsReply{Rockets: make([]*pb.Rocket, 0, len(rockets))}
+	for i := range rockets {
+		reply.Rockets = append(reply.Rockets, encodeRocket(&rockets[i]))
+	}
+	return reply, nil
+}
+
+func decodeGetEventStatusRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	return transport.GetEventStatusRequest{EventID: grpcReq.(*pb.GetEventStatusRequest).GetEventId()}, nil
+}
+
+func encodeEventResponse(_ context.Context, response interface{}) (interface{}, error) {
+	event := response.(*models.RocketEvent)
+	reply := &pb.Event{
+		Id:            event.ID,
+		Channel:       event.Channel,
+		MessageNumber: int32(event.MessageNumber),
+		MessageType:   event.MessageType,
+		MessageTime:   timestamppb.New(event.MessageTime),
+		ReceivedAt:    timestamppb.New(event.ReceivedAt),
+		ProcessedAt:   timestamp(event.ProcessedAt),
+		Status:        event.Status,
+		ErrorMessage:  event.ErrorMessage,
+		RequestId:     event.RequestID,
+	}
+	if len(event.MessageData) > 0 {
+		var data map[string]interface{}
+		if err := json.Unmarshal(event.MessageData, &data); err != nil {
+			return nil, fmt.Errorf("failed to decode message data: %w", err)
+		}
+		messageData, err := structpb.NewStruct(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode message data: %w", err)
+		}
+		reply.MessageData = messageData
+	}
+	return reply, nil
+}
+
+func encodeRocket(rocket *models.Rocket) *pb.Rocket {
+	reply := &pb.Rocket{
+		Id:              rocket.ID,
+		Type:            rocket.Type,
+		CurrentSpeed:    int32(rocket.CurrentSpeed),
+		Mission:         rocket.Mission,
+		Status:          rocket.Status,
+		ExplosionReason: rocket.ExplosionReason,
+		Altitude:        int32(rocket.Altitude),
+		Latitude:        rocket.Latitude,
+		Longitude:       rocket.Longitude,
+		FuelLevel:       rocket.FuelLevel,
+		ActiveStage:     int32(rocket.ActiveStage),
+		PeakSpeed:       int32(rocket.PeakSpeed),
+		TimeAtMaxSpeed:  timestamp(rocket.TimeAtMaxSpeed),
+		AverageSpeed:    rocket.AverageSpeed,
+		SpeedSamples:    int32(rocket.SpeedSamples),
+		Acceleration:    rocket.Acceleration,
+		LaunchTime:      timestamppb.New(rocket.LaunchTime),
+		LastUpdated:     timestamppb.New(rocket.LastUpdated),
+	}
+	for _, stage := range rocket.Stages {
+		reply.Stages = append(reply.Stages, &pb.RocketStage{
+			Number:      int32(stage.Number),
+			SeparatedAt: timestamp(stage.SeparatedAt),
+		})
+	}
+	for _, payload := range rocket.Payloads {
+		reply.Payloads = append(reply.Payloads, &pb.RocketPayload{
+			Name:       payload.Name,
+			Deployed:   payload.Deployed,
+			DeployedAt: timestamp(payload.DeployedAt),
+		})
+	}
+	return reply
+}
+
+// timestamp converts an optional time, nil stays unset
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func intPtr(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
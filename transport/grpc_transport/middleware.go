@@ -0,0 +1,198 @@
+package grpc_transport
+
+import (
+	"context"
+	"errors"
+	"net"
+	"rockets-backend/models"
+	pkgContext "rockets-backend/pkg/context"
+	"rockets-backend/tracing"
+	"rockets-backend/transport"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata keys, gRPC lowercases them
+const (
+	requestIDKey     = "request-id"
+	traceparentKey   = "traceparent"
+	authorizationKey = "authorization"
+	apiKeyKey        = "x-api-key"
+)
+
+// ingestMethods take messages, the other methods only read
+var ingestMethods = map[string]bool{
+	"/rockets.v1.Rockets/Ingest":       true,
+	"/rockets.v1.Rockets/IngestStream": true,
+}
+
+// interceptor wraps a unary or streaming call, next handles the call with ctx
+type interceptor func(ctx context.Context, method string, next func(ctx context.Context) error) error
+
+// chainInterceptors applies the interceptors in order to both unary and streaming calls
+func chainInterceptors(interceptors ...interceptor) []grpc.ServerOption {
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+	for _, i := range interceptors {
+		unary = append(unary, i.unary)
+		stream = append(stream, i.stream)
+	}
+	return []grpc.ServerOption{grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...)}
+}
+
+func (i interceptor) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	var resp interface{}
+	err := i(ctx, info.FullMethod, func(ctx context.Context) (err error) {
+		resp, err = handler(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+func (i interceptor) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	return i(ss.Context(), info.FullMethod, func(ctx context.Context) error {
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	})
+}
+
+// serverStream replaces the context of a stream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// firstValue returns the first value of a metadata key, or ""
+func firstValue(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// requestIDInterceptor takes the request ID from the request-id metadata, or generates one,
+// and returns it in the response header. It also adds the client address for rate limiting.
+func requestIDInterceptor(ctx context.Context, method string, next func(ctx context.Context) error) error {
+	requestID := firstValue(ctx, requestIDKey)
+	if requestID == "" {
+		requestID = pkgContext.GenerateRequestID()
+	}
+	ctx = pkgContext.WithRequestID(ctx, requestID)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+
+	if p, ok := peer.FromContext(ctx); ok {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		ctx = pkgContext.WithClientAddr(ctx, host)
+	}
+
+	return next(ctx)
+}
+
+// serverErrorCodes mark a span as failed, the other codes are the client's doing
+var serverErrorCodes = map[codes.Code]bool{
+	codes.Unknown:     true,
+	codes.Internal:    true,
+	codes.Unavailable: true,
+	codes.DataLoss:    true,
+}
+
+// tracingInterceptor wraps each call in a server span that continues the trace of an
+// incoming traceparent metadata value
+func tracingInterceptor(ctx context.Context, method string, next func(ctx context.Context) error) (err error) {
+	ctx = tracing.WithTraceparent(ctx, firstValue(ctx, traceparentKey))
+	ctx, span := tracing.StartSpan(ctx, strings.TrimPrefix(method, "/"),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.method", method),
+			attribute.String("request.id", pkgContext.GetRequestID(ctx)),
+		),
+	)
+	defer func() {
+		code := status.Code(err)
+		span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(code)))
+		if serverErrorCodes[code] {
+			tracing.EndSpan(span, err)
+			return
+		}
+		span.End()
+	}()
+
+	return next(ctx)
+}
+
+// apiKeyFromMetadata reads the key from "authorization: Bearer" or x-api-key metadata
+func apiKeyFromMetadata(ctx context.Context) string {
+	if authorization := firstValue(ctx, authorizationKey); authorization != "" {
+		if scheme, key, ok := strings.Cut(authorization, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(key)
+		}
+		return ""
+	}
+	return firstValue(ctx, apiKeyKey)
+}
+
+// authInterceptor rejects calls without an API key allowed to call the method, and adds
+// the key identity to the context of accepted calls. Ingestion needs the ingest role,
+// the other methods the read role.
+func authInterceptor(authenticate transport.Authenticator, logger log.Logger) interceptor {
+	return func(ctx context.Context, method string, next func(ctx context.Context) error) error {
+		required := models.RoleRead
+		if ingestMethods[method] {
+			required = models.RoleIngest
+		}
+
+		requestID := pkgContext.GetRequestID(ctx)
+		ctx, apiKey, err := authenticate.Authenticate(ctx, apiKeyFromMetadata(ctx), required)
+		var roleErr *transport.RoleError
+		switch {
+		case errors.Is(err, transport.ErrAuthUnavailable):
+			_ = level.Error(logger).Log("requestId", requestID, "msg", "failed to authenticate call",
+				"method", method, "error", err)
+			return status.Error(codes.Unavailable, transport.ErrAuthUnavailable.Error())
+		case errors.As(err, &roleErr):
+			_ = level.Warn(logger).Log("requestId", requestID, "msg", "call rejected", "method", method,
+				"apiKey", apiKey.Name, "role", apiKey.Role, "reason", "insufficient role")
+			return status.Error(codes.PermissionDenied, err.Error())
+		case err != nil:
+			_ = level.Warn(logger).Log("requestId", requestID, "msg", "call rejected", "method", method,
+				"reason", err)
+			return status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		_ = level.Debug(logger).Log("requestId", requestID, "msg", "call authenticated", "method", method,
+			"apiKey", apiKey.Name, "role", apiKey.Role)
+
+		return next(ctx)
+	}
+}
+
+// signatureInterceptor rejects the ingestion methods
+func signatureInterceptor(logger log.Logger) interceptor {
+	return func(ctx context.Context, method string, next func(ctx context.Context) error) error {
+		if !ingestMethods[method] {
+			return next(ctx)
+		}
+		_ = level.Warn(logger).Log("requestId", pkgContext.GetRequestID(ctx), "msg", "call rejected",
+			"method", method, "reason", "unsigned messages")
+		return status.Error(codes.Unauthenticated, transport.ErrSignatureRequired.Error())
+	}
+}
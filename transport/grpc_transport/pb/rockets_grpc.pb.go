@@ -0,0 +1,322 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: rockets.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Rockets_Ingest_FullMethodName         = "/rockets.v1.Rockets/Ingest"
+	Rockets_IngestStream_FullMethodName   = "/rockets.v1.Rockets/IngestStream"
+	Rockets_GetRocket_FullMethodName      = "/rockets.v1.Rockets/GetRocket"
+	Rockets_ListRockets_FullMethodName    = "/rockets.v1.Rockets/ListRockets"
+	Rockets_GetEventStatus_FullMethodName = "/rockets.v1.Rockets/GetEventStatus"
+	Rockets_WatchRockets_FullMethodName   = "/rockets.v1.Rockets/WatchRockets"
+)
+
+// RocketsClient is the client API for Rockets service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Rockets is the gRPC counterpart of the HTTP API for ingestion and rocket state
+type RocketsClient interface {
+	// Ingest stores a message for asynchronous processing, like POST /messages
+	Ingest(ctx context.Context, in *IngestRequest, opts ...grpc.CallOption) (*IngestReply, error)
+	// IngestStream stores the streamed messages in order and replies once the client
+	// closes the stream. Rate limited messages wait until the limit allows them, which
+	// slows the stream down instead of failing it.
+	IngestStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[IngestRequest, IngestStreamReply], error)
+	GetRocket(ctx context.Context, in *GetRocketRequest, opts ...grpc.CallOption) (*Rocket, error)
+	ListRockets(ctx context.Context, in *ListRocketsRequest, opts ...grpc.CallOption) (*ListRocketsReply, error)
+	GetEventStatus(ctx context.Context, in *GetEventStatusRequest, opts ...grpc.CallOption) (*Event, error)
+	// WatchRockets sends the matching rockets, then each rocket again whenever it changes
+	WatchRockets(ctx context.Context, in *WatchRocketsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Rocket], error)
+}
+
+type rocketsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRocketsClient(cc grpc.ClientConnInterface) RocketsClient {
+	return &rocketsClient{cc}
+}
+
+func (c *rocketsClient) Ingest(ctx context.Context, in *IngestRequest, opts ...grpc.CallOption) (*IngestReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IngestReply)
+	err := c.cc.Invoke(ctx, Rockets_Ingest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rocketsClient) IngestStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[IngestRequest, IngestStreamReply], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Rockets_ServiceDesc.Streams[0], Rockets_IngestStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[IngestRequest, IngestStreamReply]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Rockets_IngestStreamClient = grpc.ClientStreamingClient[IngestRequest, IngestStreamReply]
+
+func (c *rocketsClient) GetRocket(ctx context.Context, in *GetRocketRequest, opts ...grpc.CallOption) (*Rocket, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Rocket)
+	err := c.cc.Invoke(ctx, Rockets_GetRocket_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rocketsClient) ListRockets(ctx context.Context, in *ListRocketsRequest, opts ...grpc.CallOption) (*ListRocketsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRocketsReply)
+	err := c.cc.Invoke(ctx, Rockets_ListRockets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rocketsClient) GetEventStatus(ctx context.Context, in *GetEventStatusRequest, opts ...grpc.CallOption) (*Event, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Event)
+	err := c.cc.Invoke(ctx, Rockets_GetEventStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rocketsClient) WatchRockets(ctx context.Context, in *WatchRocketsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Rocket], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Rockets_ServiceDesc.Streams[1], Rockets_WatchRockets_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRocketsRequest, Rocket]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Rockets_WatchRocketsClient = grpc.ServerStreamingClient[Rocket]
+
+// RocketsServer is the server API for Rockets service.
+// All implementations must embed UnimplementedRocketsServer
+// for forward compatibility.
+//
+// Rockets is the gRPC counterpart of the HTTP API for ingestion and rocket state
+type RocketsServer interface {
+	// Ingest stores a message for asynchronous processing, like POST /messages
+	Ingest(context.Context, *IngestRequest) (*IngestReply, error)
+	// IngestStream stores the streamed messages in order and replies once the client
+	// closes the stream. Rate limited messages wait until the limit allows them, which
+	// slows the stream down instead of failing it.
+	IngestStream(grpc.ClientStreamingServer[IngestRequest, IngestStreamReply]) error
+	GetRocket(context.Context, *GetRocketRequest) (*Rocket, error)
+	ListRockets(context.Context, *ListRocketsRequest) (*ListRocketsReply, error)
+	GetEventStatus(context.Context, *GetEventStatusRequest) (*Event, error)
+	// WatchRockets sends the matching rockets, then each rocket again whenever it changes
+	WatchRockets(*WatchRocketsRequest, grpc.ServerStreamingServer[Rocket]) error
+	mustEmbedUnimplementedRocketsServer()
+}
+
+// UnimplementedRocketsServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRocketsServer struct{}
+
+func (UnimplementedRocketsServer) Ingest(context.Context, *IngestRequest) (*IngestReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ingest not implemented")
+}
+func (UnimplementedRocketsServer) IngestStream(grpc.ClientStreamingServer[IngestRequest, IngestStreamReply]) error {
+	return status.Errorf(codes.Unimplemented, "method IngestStream not implemented")
+}
+func (UnimplementedRocketsServer) GetRocket(context.Context, *GetRocketRequest) (*Rocket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRocket not implemented")
+}
+func (UnimplementedRocketsServer) ListRockets(context.Context, *ListRocketsRequest) (*ListRocketsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRockets not implemented")
+}
+func (UnimplementedRocketsServer) GetEventStatus(context.Context, *GetEventStatusRequest) (*Event, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEventStatus not implemented")
+}
+func (UnimplementedRocketsServer) WatchRockets(*WatchRocketsRequest, grpc.ServerStreamingServer[Rocket]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchRockets not implemented")
+}
+func (UnimplementedRocketsServer) mustEmbedUnimplementedRocketsServer() {}
+func (UnimplementedRocketsServer) testEmbeddedByValue()                 {}
+
+// UnsafeRocketsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RocketsServer will
+// result in compilation errors.
+type UnsafeRocketsServer interface {
+	mustEmbedUnimplementedRocketsServer()
+}
+
+func RegisterRocketsServer(s grpc.ServiceRegistrar, srv RocketsServer) {
+	// If the following call pancis, it indicates UnimplementedRocketsServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Rockets_ServiceDesc, srv)
+}
+
+func _Rockets_Ingest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IngestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RocketsServer).Ingest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Rockets_Ingest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RocketsServer).Ingest(ctx, req.(*IngestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Rockets_IngestStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RocketsServer).IngestStream(&grpc.GenericServerStream[IngestRequest, IngestStreamReply]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Rockets_IngestStreamServer = grpc.ClientStreamingServer[IngestRequest, IngestStreamReply]
+
+func _Rockets_GetRocket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRocketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RocketsServer).GetRocket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Rockets_GetRocket_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RocketsServer).GetRocket(ctx, req.(*GetRocketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Rockets_ListRockets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRocketsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RocketsServer).ListRockets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Rockets_ListRockets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RocketsServer).ListRockets(ctx, req.(*ListRocketsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Rockets_GetEventStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEventStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RocketsServer).GetEventStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Rockets_GetEventStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RocketsServer).GetEventStatus(ctx, req.(*GetEventStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Rockets_WatchRockets_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRocketsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RocketsServer).WatchRockets(m, &grpc.GenericServerStream[WatchRocketsRequest, Rocket]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Rockets_WatchRocketsServer = grpc.ServerStreamingServer[Rocket]
+
+// Rockets_ServiceDesc is the grpc.ServiceDesc for Rockets service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Rockets_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rockets.v1.Rockets",
+	HandlerType: (*RocketsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Ingest",
+			Handler:    _Rockets_Ingest_Handler,
+		},
+		{
+			MethodName: "GetRocket",
+			Handler:    _Rockets_GetRocket_Handler,
+		},
+		{
+			MethodName: "ListRockets",
+			Handler:    _Rockets_ListRockets_Handler,
+		},
+		{
+			MethodName: "GetEventStatus",
+			Handler:    _Rockets_GetEventStatus_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "IngestStream",
+			Handler:       _Rockets_IngestStream_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchRockets",
+			Handler:       _Rockets_WatchRockets_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "rockets.proto",
+}
@@ -0,0 +1,306 @@
+// Package grpc_transport serves the ingestion and rocket endpoints over gRPC, next to the
+// HTTP API of http_transport
+package grpc_transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"rockets-backend/models"
+	"rockets-backend/service"
+	"rockets-backend/transport"
+	"rockets-backend/transport/grpc_transport/pb"
+	"strconv"
+	"sync"
+	"time"
+
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+	"github.com/go-kit/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultWatchInterval = time.Second
+
+	// maxReportedFailures bounds the failures listed in an IngestStream reply
+	maxReportedFailures = 100
+)
+
+// config holds the optional gRPC layers
+type config struct {
+	authenticate  transport.Authenticator
+	requireSigned bool
+	watchInterval time.Duration
+}
+
+// Option configures optional gRPC layers
+type Option func(*config)
+
+// WithAuthenticator requires API keys, without it every method is open
+func WithAuthenticator(authenticate transport.Authenticator) Option {
+	return func(c *config) {
+		c.authenticate = authenticate
+	}
+}
+
+// WithSignaturesRequired rejects all messages, they cannot be signed over gRPC yet
+func WithSignaturesRequired(required bool) Option {
+	return func(c *config) {
+		c.requireSigned = required
+	}
+}
+
+// WithWatchInterval sets how often WatchRockets checks the rockets for changes
+func WithWatchInterval(interval time.Duration) Option {
+	return func(c *config) {
+		c.watchInterval = interval
+	}
+}
+
+type grpcServer struct {
+	pb.UnimplementedRocketsServer
+
+	ingest         kitgrpc.Handler
+	getRocket      kitgrpc.Handler
+	listRockets    kitgrpc.Handler
+	getEventStatus kitgrpc.Handler
+
+	endpoints     transport.Endpoints
+	watchInterval time.Duration
+	shutdown      <-chan struct{}
+}
+
+// Server serves the gRPC API
+type Server struct {
+	*grpc.Server
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+}
+
+// Shutdown ends the WatchRockets streams, which would otherwise never finish, then stops
+// accepting calls and waits for the calls in flight. When ctx is done first the remaining
+// calls are cancelled and ctx's error is returned, like http.Server.Shutdown.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.shutdownOnce.Do(func() { close(s.shutdown) })
+
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.Stop()
+		return ctx.Err()
+	}
+}
+
+// NewGRPCServer builds the gRPC API
+func NewGRPCServer(endpoints transport.Endpoints, logger log.Logger, opts ...Option) *Server {
+	c := config{watchInterval: defaultWatchInterval}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	// Apply request ID, tracing, auth and signature checks to all methods, like the HTTP middleware
+	interceptors := []interceptor{requestIDInterceptor, tracingInterceptor}
+	if c.authenticate != nil {
+		interceptors = append(interceptors, authInterceptor(c.authenticate, logger))
+	}
+	if c.requireSigned {
+		interceptors = append(interceptors, signatureInterceptor(logger))
+	}
+	server := &Server{
+		Server:   grpc.NewServer(chainInterceptors(interceptors...)...),
+		shutdown: make(chan struct{}),
+	}
+
+	pb.RegisterRocketsServer(server.Server, &grpcServer{
+		ingest:         kitgrpc.NewServer(endpoints.ProcessMessage, decodeIngestRequest, encodeIngestResponse),
+		getRocket:      kitgrpc.NewServer(endpoints.GetRocket, decodeGetRocketRequest, encodeRocketResponse),
+		listRockets:    kitgrpc.NewServer(endpoints.GetAllRockets, decodeListRocketsRequest, encodeListRocketsResponse),
+		getEventStatus: kitgrpc.NewServer(endpoints.GetEventStatus, decodeGetEventStatusRequest, encodeEventResponse),
+		endpoints:      endpoints,
+		watchInterval:  c.watchInterval,
+		shutdown:       server.shutdown,
+	})
+	return server
+}
+
+func (s *grpcServer) Ingest(ctx context.Context, req *pb.IngestRequest) (*pb.IngestReply, error) {
+	_, resp, err := s.ingest.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(ctx, err)
+	}
+	return resp.(*pb.IngestReply), nil
+}
+
+func (s *grpcServer) GetRocket(ctx context.Context, req *pb.GetRocketRequest) (*pb.Rocket, error) {
+	_, resp, err := s.getRocket.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(ctx, err)
+	}
+	return resp.(*pb.Rocket), nil
+}
+
+func (s *grpcServer) ListRockets(ctx context.Context, req *pb.ListRocketsRequest) (*pb.ListRocketsReply, error) {
+	_, resp, err := s.listRockets.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(ctx, err)
+	}
+	return resp.(*pb.ListRocketsReply), nil
+}
+
+func (s *grpcServer) GetEventStatus(ctx context.Context, req *pb.GetEventStatusRequest) (*pb.Event, error) {
+	_, resp, err := s.getEventStatus.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(ctx, err)
+	}
+	return resp.(*pb.Event), nil
+}
+
+// IngestStream ingests the messages of a client stream one at a time. A rate limited
+// message waits for the limit, so a fast client is slowed down through gRPC flow control.
+// Other failures are reported in the reply and the stream goes on.
+func (s *grpcServer) IngestStream(stream pb.Rockets_IngestStreamServer) error {
+	ctx := stream.Context()
+	reply := &pb.IngestStreamReply{}
+
+	for index := int64(0); ; index++ {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(reply)
+		}
+		if err != nil {
+			return err
+		}
+
+		msg, err := decodeIngestRequest(ctx, req)
+		if err == nil {
+			err = s.ingestWhenAllowed(ctx, msg)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			reply.Failed++
+			if len(reply.Failures) < maxReportedFailures {
+				reply.Failures = append(reply.Failures, &pb.IngestFailure{
+					Index:         index,
+					Channel:       req.GetMetadata().GetChannel(),
+					MessageNumber: req.GetMetadata().GetMessageNumber(),
+					Error:         err.Error(),
+				})
+			}
+			continue
+		}
+		reply.Ingested++
+	}
+}
+
+// ingestWhenAllowed ingests a message, waiting out rate limits until ctx is done
+func (s *grpcServer) ingestWhenAllowed(ctx context.Context, msg interface{}) error {
+	for {
+		_, err := s.endpoints.ProcessMessage(ctx, msg)
+		var limitErr *service.RateLimitError
+		if !errors.As(err, &limitErr) {
+			return err
+		}
+
+		timer := time.NewTimer(limitErr.RetryAfter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// WatchRockets sends the matching rockets, then checks them every watch interval and
+// sends each rocket again once it was updated or changed status. The stream ends when the
+// client cancels it or the server shuts down.
+func (s *grpcServer) WatchRockets(req *pb.WatchRocketsRequest, stream pb.Rockets_WatchRocketsServer) error {
+	ctx := stream.Context()
+
+	ids := make(map[string]bool, len(req.GetIds()))
+	for _, id := range req.GetIds() {
+		ids[id] = true
+	}
+
+	type version struct {
+		lastUpdated int64
+		status      string
+	}
+	sent := make(map[string]version)
+
+	ticker := time.NewTicker(s.watchInterval)
+	defer ticker.Stop()
+
+	for {
+		resp, err := s.endpoints.GetAllRockets(ctx, transport.GetAllRocketsRequest{
+			Filter: models.RocketFilter{Status: req.GetStatus()},
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return encodeError(ctx, err)
+		}
+
+		for _, rocket := range resp.([]models.Rocket) {
+			if len(ids) > 0 && !ids[rocket.ID] {
+				continue
+			}
+			current := version{lastUpdated: rocket.LastUpdated.UnixNano(), status: rocket.Status}
+			if previous, ok := sent[rocket.ID]; ok && previous == current {
+				continue
+			}
+			if err := stream.Send(encodeRocket(&rocket)); err != nil {
+				return err
+			}
+			sent[rocket.ID] = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.shutdown:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case <-ticker.C:
+		}
+	}
+}
+
+// notFoundErrors are the endpoint errors reported as NotFound
+var notFoundErrors = map[string]bool{
+	"rocket not found": true,
+	"event not found":  true,
+}
+
+// encodeError converts an endpoint error to a gRPC status. Rate limited calls get
+// ResourceExhausted, or Unavailable while the queue is full, and a retry-after trailer in
+// seconds like the HTTP Retry-After header.
+func encodeError(ctx context.Context, err error) error {
+	code := codes.InvalidArgument
+	var limitErr *service.RateLimitError
+	if errors.As(err, &limitErr) {
+		code = codes.ResourceExhausted
+		if limitErr.Scope == service.RateLimitScopeQueue {
+			code = codes.Unavailable
+		}
+		seconds := int64(math.Ceil(limitErr.RetryAfter.Seconds()))
+		_ = grpc.SetTrailer(ctx, metadata.Pairs("retry-after", strconv.FormatInt(seconds, 10)))
+	} else if notFoundErrors[err.Error()] {
+		code = codes.NotFound
+	} else if service.IsChannelRejected(err) {
+		code = codes.PermissionDenied
+	}
+	return status.Error(code, err.Error())
+}
@@ -0,0 +1,198 @@
+package grpc_transport_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"rockets-backend/models"
+	"rockets-backend/repository"
+	"rockets-backend/service"
+	"rockets-backend/testutil"
+	"rockets-backend/transport"
+	"rockets-backend/transport/grpc_transport"
+	"rockets-backend/transport/grpc_transport/pb"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const testChannel = "193270a9-c9cf-404a-8f83-838e71d9ae67"
+
+// dial serves the endpoints over an in-memory connection and returns a client for them
+func dial(t *testing.T, endpoints transport.Endpoints, opts ...grpc_transport.Option) pb.RocketsClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc_transport.NewGRPCServer(endpoints, log.NewNopLogger(), opts...)
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.AssertNoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewRocketsClient(conn)
+}
+
+func ingestRequest(t *testing.T, number int32, messageType string, message map[string]interface{}) *pb.IngestRequest {
+	t.Helper()
+	body, err := structpb.NewStruct(message)
+	testutil.AssertNoError(t, err)
+	return &pb.IngestRequest{
+		Metadata: &pb.MessageMetadata{
+			Channel:       testChannel,
+			MessageNumber: number,
+			MessageTime:   timestamppb.New(time.Date(2022, 2, 2, 19, 39, 5, 0, time.UTC)),
+			MessageType:   messageType,
+		},
+		Message: body,
+	}
+}
+
+func launchRequest(t *testing.T) *pb.IngestRequest {
+	return ingestRequest(t, 1, "RocketLaunched",
+		map[string]interface{}{"type": "Falcon-9", "launchSpeed": 500, "mission": "ARTEMIS"})
+}
+
+func TestIngestAndWatch(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRocketRepository()
+	svc := service.NewService(log.NewNopLogger(), repo)
+	client := dial(t, transport.MakeEndpoints(svc), grpc_transport.WithWatchInterval(10*time.Millisecond))
+
+	var header metadata.MD
+	ctx = metadata.AppendToOutgoingContext(ctx, "request-id", "grpc-test")
+	reply, err := client.Ingest(ctx, launchRequest(t), grpc.Header(&header))
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "ingested", reply.Status)
+	testutil.AssertEqual(t, "grpc-test", header.Get("request-id")[0])
+
+	event, err := client.GetEventStatus(ctx, &pb.GetEventStatusRequest{EventId: reply.EventId})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, models.EventStatusPending, event.Status)
+	testutil.AssertEqual(t, "ARTEMIS", event.MessageData.AsMap()["mission"])
+	testutil.AssertEqual(t, "grpc-test", event.GetRequestId())
+
+	_, err = client.GetRocket(ctx, &pb.GetRocketRequest{Id: testChannel})
+	testutil.AssertEqual(t, codes.NotFound, status.Code(err))
+
+	watchCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	watch, err := client.WatchRockets(watchCtx, &pb.WatchRocketsRequest{Ids: []string{testChannel}})
+	testutil.AssertNoError(t, err)
+
+	// The rocket is sent once the launch is processed
+	pending, err := repo.GetPendingEvents(ctx, 10)
+	testutil.AssertNoError(t, err)
+	_, err = svc.ProcessEvent(ctx, &pending[0])
+	testutil.AssertNoError(t, err)
+
+	rocket, err := watch.Recv()
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, testChannel, rocket.Id)
+	testutil.AssertEqual(t, "ARTEMIS", rocket.Mission)
+	testutil.AssertEqual(t, int32(500), rocket.CurrentSpeed)
+
+	list, err := client.ListRockets(ctx, &pb.ListRocketsRequest{Status: rocket.Status})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, len(list.Rockets))
+}
+
+func TestIngestStream(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRocketRepository()
+	endpoints := transport.MakeEndpoints(service.NewService(log.NewNopLogger(), repo))
+	ingest := endpoints.ProcessMessage
+	endpoints.ProcessMessage = func(ctx context.Context, request interface{}) (interface{}, error) {
+		if request.(models.IncomingMessage).Metadata.MessageNumber == 2 {
+			return nil, errors.New("failed to store rocket event: connection refused")
+		}
+		return ingest(ctx, request)
+	}
+	client := dial(t, endpoints)
+
+	stream, err := client.IngestStream(ctx)
+	testutil.AssertNoError(t, err)
+	testutil.AssertNoError(t, stream.Send(launchRequest(t)))
+	testutil.AssertNoError(t, stream.Send(ingestRequest(t, 2, "RocketSpeedIncreased",
+		map[string]interface{}{"by": 100})))
+	testutil.AssertNoError(t, stream.Send(ingestRequest(t, 3, "RocketSpeedIncreased",
+		map[string]interface{}{"by": 100})))
+
+	reply, err := stream.CloseAndRecv()
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, int64(2), reply.Ingested)
+	testutil.AssertEqual(t, int64(1), reply.Failed)
+	testutil.AssertEqual(t, int64(1), reply.Failures[0].Index)
+	testutil.AssertEqual(t, int32(2), reply.Failures[0].MessageNumber)
+
+	pending, err := repo.GetPendingEvents(ctx, 10)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 2, len(pending))
+}
+
+func TestGRPCAuth(t *testing.T) {
+	svc := service.NewService(log.NewNopLogger(), repository.NewMemoryRocketRepository(),
+		service.WithAuthRepository(repository.NewMemoryAuthRepository()))
+	client := dial(t, transport.MakeEndpoints(svc), grpc_transport.WithAuthenticator(svc.AuthenticateAPIKey))
+
+	keys := map[string]string{}
+	for _, role := range []string{models.RoleIngest, models.RoleRead} {
+		created, err := svc.CreateAPIKey(context.Background(), role+" key", role)
+		testutil.AssertNoError(t, err)
+		keys[role] = created.Key
+	}
+
+	ingest := func(ctx context.Context) error {
+		_, err := client.Ingest(ctx, launchRequest(t))
+		return err
+	}
+	list := func(ctx context.Context) error {
+		_, err := client.ListRockets(ctx, &pb.ListRocketsRequest{})
+		return err
+	}
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context) error
+		md   []string
+		want codes.Code
+	}{
+		{name: "missing key", call: ingest, want: codes.Unauthenticated},
+		{name: "unknown key", call: ingest, md: []string{"x-api-key", "rk_0_0"}, want: codes.Unauthenticated},
+		{name: "ingest key ingests", call: ingest, md: []string{"authorization", "Bearer " + keys[models.RoleIngest]}, want: codes.OK},
+		{name: "read key cannot ingest", call: ingest, md: []string{"x-api-key", keys[models.RoleRead]}, want: codes.PermissionDenied},
+		{name: "ingest key cannot read", call: list, md: []string{"x-api-key", keys[models.RoleIngest]}, want: codes.PermissionDenied},
+		{name: "read key reads", call: list, md: []string{"x-api-key", keys[models.RoleRead]}, want: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.AppendToOutgoingContext(context.Background(), tt.md...)
+			testutil.AssertEqual(t, tt.want, status.Code(tt.call(ctx)))
+		})
+	}
+}
+
+func TestSignaturesRequired(t *testing.T) {
+	ctx := context.Background()
+	client := dial(t, transport.MakeEndpoints(service.NewService(log.NewNopLogger(),
+		repository.NewMemoryRocketRepository())), grpc_transport.WithSignaturesRequired(true))
+
+	_, err := client.Ingest(ctx, launchRequest(t))
+	testutil.AssertEqual(t, codes.Unauthenticated, status.Code(err))
+
+	// Reading is still allowed
+	_, err = client.ListRockets(ctx, &pb.ListRocketsRequest{})
+	testutil.AssertNoError(t, err)
+}
@@ -0,0 +1,112 @@
+package http_transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"rockets-backend/models"
+	"rockets-backend/transport"
+	"strconv"
+
+	goKitHttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+)
+
+func registerAlertRoutes(r *mux.Router, endpoints transport.Endpoints, options []goKitHttp.ServerOption) {
+	// Alert rule management
+	r.Methods("GET").Path("/alerts/rules").Handler(goKitHttp.NewServer(
+		endpoints.GetAlertRules,
+		decodeEmptyRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods("POST").Path("/alerts/rules").Handler(goKitHttp.NewServer(
+		endpoints.CreateAlertRule,
+		decodeAlertRuleRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods("GET").Path("/alerts/rules/{id:[0-9]+}").Handler(goKitHttp.NewServer(
+		endpoints.GetAlertRule,
+		decodeAlertIDRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods("PUT").Path("/alerts/rules/{id:[0-9]+}").Handler(goKitHttp.NewServer(
+		endpoints.UpdateAlertRule,
+		decodeAlertRuleRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods("DELETE").Path("/alerts/rules/{id:[0-9]+}").Handler(goKitHttp.NewServer(
+		endpoints.DeleteAlertRule,
+		decodeAlertIDRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// Fired alerts
+	r.Methods("GET").Path("/alerts").Handler(goKitHttp.NewServer(
+		endpoints.GetAlerts,
+		decodeGetAlertsRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods("POST").Path("/alerts/{id:[0-9]+}/acknowledge").Handler(goKitHttp.NewServer(
+		endpoints.AcknowledgeAlert,
+		decodeAlertIDRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods("POST").Path("/alerts/{id:[0-9]+}/resolve").Handler(goKitHttp.NewServer(
+		endpoints.ResolveAlert,
+		decodeAlertIDRequest,
+		encodeResponse,
+		options...,
+	))
+}
+
+func decodeAlertRuleRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	// Rules are enabled unless the request says otherwise
+	rule := models.AlertRule{Enabled: true}
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		return nil, err
+	}
+
+	// Rule ID comes from the path on updates
+	if idStr, ok := mux.Vars(r)["id"]; ok {
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid alert rule ID: %s", idStr)
+		}
+		rule.ID = id
+	}
+
+	return transport.AlertRuleRequest{Rule: rule}, nil
+}
+
+func decodeAlertIDRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	idStr := mux.Vars(r)["id"]
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID: %s", idStr)
+	}
+
+	return transport.AlertIDRequest{ID: id}, nil
+}
+
+func decodeGetAlertsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	query := r.URL.Query()
+	return transport.GetAlertsRequest{Filter: models.AlertFilter{
+		Status:   query.Get("status"),
+		RocketID: query.Get("rocketId"),
+	}}, nil
+}
@@ -0,0 +1,107 @@
+package http_transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"rockets-backend/models"
+	pkgContext "rockets-backend/pkg/context"
+	"rockets-backend/pkg/response"
+	"rockets-backend/transport"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gorilla/mux"
+)
+
+// publicRoutes are reachable without an API key, so probes and scrapers need no credentials
+var publicRoutes = map[string]bool{
+	"/health":       true,
+	"/health/live":  true,
+	"/health/ready": true,
+	"/metrics":      true,
+}
+
+// requiredRole returns the role needed to call a route, or "" for public routes.
+// Ingestion needs the ingest role, other reads the read role, and everything that
+// changes state or lives under /admin needs the admin role.
+func requiredRole(method, route string) string {
+	switch {
+	case publicRoutes[route]:
+		return ""
+	case method == http.MethodPost && route == "/messages":
+		return models.RoleIngest
+	case strings.HasPrefix(route, "/admin/"):
+		return models.RoleAdmin
+	case method == http.MethodGet:
+		return models.RoleRead
+	default:
+		return models.RoleAdmin
+	}
+}
+
+// apiKeyFromRequest reads the key from an "Authorization: Bearer" or X-API-Key header
+func apiKeyFromRequest(r *http.Request) string {
+	if authorization := r.Header.Get("Authorization"); authorization != "" {
+		if scheme, key, ok := strings.Cut(authorization, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(key)
+		}
+		return ""
+	}
+	return r.Header.Get("X-API-Key")
+}
+
+// authMiddleware rejects requests without an API key allowed to call the matched route,
+// and adds the key identity to the context of accepted requests
+func authMiddleware(authenticate transport.Authenticator, logger log.Logger) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			route := routeTemplate(r)
+			required := requiredRole(r.Method, route)
+			if required == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			requestID := pkgContext.GetRequestID(ctx)
+			ctx, apiKey, err := authenticate.Authenticate(ctx, apiKeyFromRequest(r), required)
+			var roleErr *transport.RoleError
+			switch {
+			case errors.Is(err, transport.ErrAuthUnavailable):
+				_ = level.Error(logger).Log("requestId", requestID, "msg", "failed to authenticate request",
+					"method", r.Method, "route", route, "error", err)
+				writeAuthError(ctx, w, http.StatusServiceUnavailable, transport.ErrAuthUnavailable)
+				return
+			case errors.As(err, &roleErr):
+				_ = level.Warn(logger).Log("requestId", requestID, "msg", "request rejected", "method", r.Method,
+					"route", route, "apiKey", apiKey.Name, "role", apiKey.Role, "reason", "insufficient role")
+				writeAuthError(ctx, w, http.StatusForbidden, err)
+				return
+			case err != nil:
+				_ = level.Warn(logger).Log("requestId", requestID, "msg", "request rejected", "method", r.Method,
+					"route", route, "reason", err)
+				writeAuthError(ctx, w, http.StatusUnauthorized, err)
+				return
+			}
+
+			_ = level.Debug(logger).Log("requestId", requestID, "msg", "request authenticated", "method", r.Method,
+				"route", route, "apiKey", apiKey.Name, "role", apiKey.Role)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func writeAuthError(ctx context.Context, w http.ResponseWriter, statusCode int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	if statusCode == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+	w.WriteHeader(statusCode)
+
+	requestID := pkgContext.GetRequestID(ctx)
+	json.NewEncoder(w).Encode(response.New(requestID, nil, err))
+}
@@ -0,0 +1,98 @@
+package http_transport_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"rockets-backend/metrics"
+	"rockets-backend/models"
+	"rockets-backend/repository"
+	"rockets-backend/service"
+	"rockets-backend/testutil"
+	"rockets-backend/transport"
+	"rockets-backend/transport/http_transport"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+)
+
+const launchMessage = `{"metadata":{"channel":"193270a9-c9cf-404a-8f83-838e71d9ae67","messageNumber":1,
+	"messageTime":"2022-02-02T19:39:05Z","messageType":"RocketLaunched"},
+	"message":{"type":"Falcon-9","launchSpeed":500,"mission":"ARTEMIS"}}`
+
+const alertRule = `{"name":"fast","metric":"speed","operator":">","threshold":1000}`
+
+func TestAPIKeyRoles(t *testing.T) {
+	svc := service.NewService(log.NewNopLogger(), repository.NewMemoryRocketRepository(),
+		service.WithAlertRepository(repository.NewMemoryAlertRepository()),
+		service.WithAuthRepository(repository.NewMemoryAuthRepository()))
+	handler := http_transport.NewHttpService(transport.MakeEndpoints(svc), metrics.New(), log.NewNopLogger(),
+		http_transport.WithAuthenticator(svc.AuthenticateAPIKey))
+
+	keys := map[string]string{}
+	for _, role := range []string{models.RoleIngest, models.RoleRead, models.RoleAdmin} {
+		created, err := svc.CreateAPIKey(context.Background(), role+" key", role)
+		testutil.AssertNoError(t, err)
+		keys[role] = created.Key
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		key    string
+		want   int
+	}{
+		{name: "probes are public", method: "GET", path: "/health/live", want: http.StatusOK},
+		{name: "metrics are public", method: "GET", path: "/metrics", want: http.StatusOK},
+		{name: "missing key", method: "POST", path: "/messages", body: launchMessage, want: http.StatusUnauthorized},
+		{name: "unknown key", method: "POST", path: "/messages", body: launchMessage, key: "rk_0_0", want: http.StatusUnauthorized},
+		{name: "ingest key ingests", method: "POST", path: "/messages", body: launchMessage, key: keys[models.RoleIngest], want: http.StatusOK},
+		{name: "read key cannot ingest", method: "POST", path: "/messages", body: launchMessage, key: keys[models.RoleRead], want: http.StatusForbidden},
+		{name: "ingest key cannot read", method: "GET", path: "/rockets", key: keys[models.RoleIngest], want: http.StatusForbidden},
+		{name: "read key reads", method: "GET", path: "/rockets", key: keys[models.RoleRead], want: http.StatusOK},
+		{name: "read key cannot change rules", method: "POST", path: "/alerts/rules", body: alertRule, key: keys[models.RoleRead], want: http.StatusForbidden},
+		{name: "admin key changes rules", method: "POST", path: "/alerts/rules", body: alertRule, key: keys[models.RoleAdmin], want: http.StatusOK},
+		{name: "read key cannot list keys", method: "GET", path: "/admin/keys", key: keys[models.RoleRead], want: http.StatusForbidden},
+		{name: "admin key lists keys", method: "GET", path: "/admin/keys", key: keys[models.RoleAdmin], want: http.StatusOK},
+		{name: "admin key ingests", method: "POST", path: "/messages", body: launchMessage, key: keys[models.RoleAdmin], want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.key != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.key)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("%s %s = %d, want %d: %s", tt.method, tt.path, rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+
+	t.Run("X-API-Key header", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/rockets", nil)
+		req.Header.Set("X-API-Key", keys[models.RoleRead])
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		testutil.AssertEqual(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestAuthDisabled(t *testing.T) {
+	svc := service.NewService(log.NewNopLogger(), repository.NewMemoryRocketRepository())
+	handler := http_transport.NewHttpService(transport.MakeEndpoints(svc), metrics.New(), log.NewNopLogger())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/rockets", nil))
+
+	testutil.AssertEqual(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,48 @@
+package http_transport
+
+import (
+	"net/http"
+	"rockets-backend/metrics"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// instrumentingMiddleware records request counts and latency per route. The route
+// template is used as the label, so /rockets/{id} is a single series for all rockets.
+func instrumentingMiddleware(m *metrics.Metrics) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(recorder, r)
+
+			route := routeTemplate(r)
+			m.HTTPRequests.With("method", r.Method, "route", route, "code", strconv.Itoa(recorder.status)).Add(1)
+			m.HTTPRequestDuration.With("method", r.Method, "route", route).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+// routeTemplate returns the path template of the matched route, or "unknown"
+func routeTemplate(r *http.Request) string {
+	if current := mux.CurrentRoute(r); current != nil {
+		if template, err := current.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return "unknown"
+}
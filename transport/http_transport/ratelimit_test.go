@@ -0,0 +1,47 @@
+package http_transport_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"rockets-backend/metrics"
+	"rockets-backend/ratelimit"
+	"rockets-backend/repository"
+	"rockets-backend/service"
+	"rockets-backend/transport"
+	"rockets-backend/transport/http_transport"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+)
+
+func TestRateLimitedIngestion(t *testing.T) {
+	svc := service.NewService(log.NewNopLogger(), repository.NewMemoryRocketRepository(),
+		service.WithIngestLimits(service.IngestLimits{Client: ratelimit.Limit{Rate: 0.5, Burst: 1}}))
+	handler := http_transport.NewHttpService(transport.MakeEndpoints(svc), metrics.New(), log.NewNopLogger())
+
+	post := func(remoteAddr string, number int) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/messages", strings.NewReader(speedMessage(number)))
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := post("10.0.0.1:5000", 1); rec.Code != http.StatusOK {
+		t.Fatalf("POST /messages = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	// Unauthenticated clients are limited by address, whatever port they connect from
+	rec := post("10.0.0.1:5001", 2)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("POST /messages = %d, want %d: %s", rec.Code, http.StatusTooManyRequests, rec.Body.String())
+	}
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want %q", got, "2")
+	}
+
+	if rec := post("10.0.0.2:5000", 3); rec.Code != http.StatusOK {
+		t.Fatalf("POST /messages = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+}
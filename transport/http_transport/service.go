@@ -3,31 +3,88 @@ package http_transport
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"net"
 	"net/http"
+	"net/url"
+	"rockets-backend/metrics"
 	"rockets-backend/models"
 	pkgContext "rockets-backend/pkg/context"
 	"rockets-backend/pkg/response"
+	"rockets-backend/service"
 	"rockets-backend/transport"
 	"strconv"
 
 	goKitHttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-kit/log"
 	"github.com/gorilla/mux"
 )
 
-func NewHttpService(endpoints transport.Endpoints) http.Handler {
+// config holds the optional HTTP layers
+type config struct {
+	authenticate    transport.Authenticator
+	verifySignature SignatureVerifier
+	requireSigned   bool
+}
+
+// Option configures optional HTTP layers
+type Option func(*config)
+
+// WithAuthenticator requires API keys, without it every endpoint is open
+func WithAuthenticator(authenticate transport.Authenticator) Option {
+	return func(c *config) {
+		c.authenticate = authenticate
+	}
+}
+
+// NewHttpService builds the HTTP API
+func NewHttpService(endpoints transport.Endpoints, m *metrics.Metrics, logger log.Logger, opts ...Option) http.Handler {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+
 	r := mux.NewRouter()
 
-	// Apply request ID middleware to all routes
+	// Apply request ID, tracing, metrics, auth and signature middleware to all routes
 	r.Use(requestIDMiddleware)
+	r.Use(tracingMiddleware)
+	r.Use(instrumentingMiddleware(m))
+	if c.authenticate != nil {
+		r.Use(authMiddleware(c.authenticate, logger))
+	}
+	if c.verifySignature != nil {
+		r.Use(signatureMiddleware(c.verifySignature, c.requireSigned, logger))
+	}
+
+	options := []goKitHttp.ServerOption{
+		goKitHttp.ServerBefore(extractRequestID, extractClientAddr),
+		goKitHttp.ServerErrorEncoder(encodeErrorResponse),
+	}
 
 	// Health check endpoint
 	r.Methods("GET").Path("/health").Handler(goKitHttp.NewServer(
 		endpoints.HealthCheck,
 		decodeEmptyRequest,
 		encodeResponse,
-		goKitHttp.ServerBefore(extractRequestID),
-		goKitHttp.ServerErrorEncoder(encodeErrorResponse),
+		options...,
+	))
+
+	// Liveness and readiness probes, readiness answers 503 while a dependency check fails
+	r.Methods("GET").Path("/health/live").Handler(goKitHttp.NewServer(
+		endpoints.Liveness,
+		decodeEmptyRequest,
+		encodeHealthResponse,
+		options...,
+	))
+
+	r.Methods("GET").Path("/health/ready").Handler(goKitHttp.NewServer(
+		endpoints.Readiness,
+		decodeEmptyRequest,
+		encodeHealthResponse,
+		options...,
 	))
 
 	// Message processing endpoint (for rockets test program)
@@ -35,8 +92,31 @@ func NewHttpService(endpoints transport.Endpoints) http.Handler {
 		endpoints.ProcessMessage,
 		decodeMessageRequest,
 		encodeResponse,
-		goKitHttp.ServerBefore(extractRequestID),
-		goKitHttp.ServerErrorEncoder(encodeErrorResponse),
+		options...,
+	))
+
+	// Rocket statistics, registered before /rockets/{id} so "stats" is not taken as an ID
+	r.Methods("GET").Path("/rockets/stats").Handler(goKitHttp.NewServer(
+		endpoints.GetRocketStats,
+		decodeEmptyRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// Rocket status history
+	r.Methods("GET").Path("/rockets/{id}/history").Handler(goKitHttp.NewServer(
+		endpoints.GetRocketHistory,
+		decodeGetRocketRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// Rocket state rebuilt from the event log, optionally as of an earlier message
+	r.Methods("GET").Path("/rockets/{id}/replay").Handler(goKitHttp.NewServer(
+		endpoints.ReplayRocket,
+		decodeReplayRocketRequest,
+		encodeResponse,
+		options...,
 	))
 
 	// Get specific rocket
@@ -44,8 +124,7 @@ func NewHttpService(endpoints transport.Endpoints) http.Handler {
 		endpoints.GetRocket,
 		decodeGetRocketRequest,
 		encodeResponse,
-		goKitHttp.ServerBefore(extractRequestID),
-		goKitHttp.ServerErrorEncoder(encodeErrorResponse),
+		options...,
 	))
 
 	// Get all rockets
@@ -53,8 +132,7 @@ func NewHttpService(endpoints transport.Endpoints) http.Handler {
 		endpoints.GetAllRockets,
 		decodeGetAllRocketsRequest,
 		encodeResponse,
-		goKitHttp.ServerBefore(extractRequestID),
-		goKitHttp.ServerErrorEncoder(encodeErrorResponse),
+		options...,
 	))
 
 	// Get event status
@@ -62,10 +140,114 @@ func NewHttpService(endpoints transport.Endpoints) http.Handler {
 		endpoints.GetEventStatus,
 		decodeGetEventStatusRequest,
 		encodeResponse,
-		goKitHttp.ServerBefore(extractRequestID),
-		goKitHttp.ServerErrorEncoder(encodeErrorResponse),
+		options...,
+	))
+
+
+	registerAlertRoutes(r, endpoints, options)
+
+	// Prometheus metrics
+	r.Methods("GET").Path("/metrics").Handler(m.Handler())
+
+	// Database connection pool statistics
+	r.Methods("GET").Path("/admin/db/stats").Handler(goKitHttp.NewServer(
+		endpoints.GetPoolStats,
+		decodeEmptyRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// Event processor settings and pause/resume, applied without a restart
+	r.Methods("GET").Path("/admin/worker").Handler(goKitHttp.NewServer(
+		endpoints.GetWorkerStatus,
+		decodeEmptyRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods("PUT").Path("/admin/worker").Handler(goKitHttp.NewServer(
+		endpoints.UpdateWorkerSettings,
+		decodeUpdateWorkerSettingsRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// Instances running the singleton background jobs
+	r.Methods("GET").Path("/admin/leader").Handler(goKitHttp.NewServer(
+		endpoints.GetLeaderStatus,
+		decodeEmptyRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// Checks the rocket snapshots against the event log
+	r.Methods("GET").Path("/admin/rockets/{id}/snapshots/verify").Handler(goKitHttp.NewServer(
+		endpoints.VerifySnapshots,
+		decodeGetRocketRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// Archives old events now instead of waiting for the retention job
+	r.Methods("POST").Path("/admin/retention/run").Handler(goKitHttp.NewServer(
+		endpoints.RunRetention,
+		decodeEmptyRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// Daily partitions of rocket_events, and a maintenance run outside the partition job
+	r.Methods("GET").Path("/admin/partitions").Handler(goKitHttp.NewServer(
+		endpoints.GetEventPartitions,
+		decodeEmptyRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods("POST").Path("/admin/partitions/maintain").Handler(goKitHttp.NewServer(
+		endpoints.MaintainPartitions,
+		decodeEmptyRequest,
+		encodeResponse,
+		options...,
+	))
+
+	// API key management and audit log
+	r.Methods("GET").Path("/admin/keys").Handler(goKitHttp.NewServer(
+		endpoints.GetAPIKeys,
+		decodeEmptyRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods("POST").Path("/admin/keys").Handler(goKitHttp.NewServer(
+		endpoints.CreateAPIKey,
+		decodeCreateAPIKeyRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods("DELETE").Path("/admin/keys/{id:[0-9]+}").Handler(goKitHttp.NewServer(
+		endpoints.RevokeAPIKey,
+		decodeAPIKeyIDRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods("PUT", "DELETE").Path("/admin/keys/{id:[0-9]+}/rate-limit").Handler(goKitHttp.NewServer(
+		endpoints.SetAPIKeyRateLimit,
+		decodeSetAPIKeyRateLimitRequest,
+		encodeResponse,
+		options...,
 	))
 
+	r.Methods("GET").Path("/admin/audit").Handler(goKitHttp.NewServer(
+		endpoints.GetAuditLog,
+		decodeGetAuditLogRequest,
+		encodeResponse,
+		options...,
+	))
+
+	registerSourceRoutes(r, endpoints, options)
 
 	return r
 }
@@ -87,9 +269,141 @@ func decodeGetRocketRequest(ctx context.Context, r *http.Request) (interface{},
 	return transport.GetRocketRequest{ID: vars["id"]}, nil
 }
 
+func decodeReplayRocketRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	messageNumber, err := parseIntParam(r.URL.Query(), "messageNumber")
+	if err != nil {
+		return nil, err
+	}
+
+	req := transport.ReplayRocketRequest{ID: mux.Vars(r)["id"]}
+	if messageNumber != nil {
+		if *messageNumber < 1 {
+			return nil, fmt.Errorf("invalid messageNumber: %d", *messageNumber)
+		}
+		req.MessageNumber = *messageNumber
+	}
+	return req, nil
+}
+
 func decodeGetAllRocketsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
-	sortBy := r.URL.Query().Get("sortBy")
-	return transport.GetAllRocketsRequest{SortBy: sortBy}, nil
+	query := r.URL.Query()
+	sortBy := query.Get("sortBy")
+
+	filter := models.RocketFilter{Status: query.Get("status")}
+	var err error
+	if filter.MinAltitude, err = parseIntParam(query, "minAltitude"); err != nil {
+		return nil, err
+	}
+	if filter.MaxAltitude, err = parseIntParam(query, "maxAltitude"); err != nil {
+		return nil, err
+	}
+	if filter.MinLatitude, err = parseFloatParam(query, "minLatitude"); err != nil {
+		return nil, err
+	}
+	if filter.MaxLatitude, err = parseFloatParam(query, "maxLatitude"); err != nil {
+		return nil, err
+	}
+	if filter.MinLongitude, err = parseFloatParam(query, "minLongitude"); err != nil {
+		return nil, err
+	}
+	if filter.MaxLongitude, err = parseFloatParam(query, "maxLongitude"); err != nil {
+		return nil, err
+	}
+	if filter.MinFuelLevel, err = parseFloatParam(query, "minFuelLevel"); err != nil {
+		return nil, err
+	}
+	if filter.MaxFuelLevel, err = parseFloatParam(query, "maxFuelLevel"); err != nil {
+		return nil, err
+	}
+
+	return transport.GetAllRocketsRequest{SortBy: sortBy, Filter: filter}, nil
+}
+
+// parseIntParam returns nil when the query parameter is absent
+func parseIntParam(query url.Values, name string) (*int, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %s", name, raw)
+	}
+	return &value, nil
+}
+
+// parseFloatParam returns nil when the query parameter is absent
+func parseFloatParam(query url.Values, name string) (*float64, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %s", name, raw)
+	}
+	return &value, nil
+}
+
+func decodeCreateAPIKeyRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req transport.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func decodeAPIKeyIDRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	idStr := mux.Vars(r)["id"]
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid API key ID: %s", idStr)
+	}
+
+	return transport.APIKeyIDRequest{ID: id}, nil
+}
+
+// decodeSetAPIKeyRateLimitRequest reads the limit of a PUT, DELETE restores the default
+func decodeSetAPIKeyRateLimitRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	idRequest, err := decodeAPIKeyIDRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := transport.SetAPIKeyRateLimitRequest{ID: idRequest.(transport.APIKeyIDRequest).ID}
+	if r.Method == http.MethodPut {
+		req.Limit = &models.RateLimit{}
+		if err := json.NewDecoder(r.Body).Decode(req.Limit); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+// decodeUpdateWorkerSettingsRequest reads the complete settings, unknown fields are
+// rejected so a misspelled setting is not silently reset
+func decodeUpdateWorkerSettingsRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var settings models.WorkerSettings
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func decodeGetAuditLogRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	limit, err := parseIntParam(r.URL.Query(), "limit")
+	if err != nil {
+		return nil, err
+	}
+
+	req := transport.GetAuditLogRequest{}
+	if limit != nil {
+		req.Limit = *limit
+	}
+	return req, nil
 }
 
 func decodeGetEventStatusRequest(ctx context.Context, r *http.Request) (interface{}, error) {
@@ -133,6 +447,16 @@ func extractRequestID(ctx context.Context, r *http.Request) context.Context {
 	return ctx
 }
 
+// extractClientAddr adds the client IP address to the go-kit context, it identifies
+// unauthenticated clients for rate limiting
+func extractClientAddr(ctx context.Context, r *http.Request) context.Context {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return pkgContext.WithClientAddr(ctx, host)
+}
+
 func encodeResponse(ctx context.Context, w http.ResponseWriter, responseData interface{}) error {
 	w.Header().Set("Content-Type", "application/json")
 	
@@ -142,13 +466,50 @@ func encodeResponse(ctx context.Context, w http.ResponseWriter, responseData int
 	return json.NewEncoder(w).Encode(apiResponse)
 }
 
+// encodeHealthResponse writes a health report, with 503 when the service is not ready
+func encodeHealthResponse(ctx context.Context, w http.ResponseWriter, responseData interface{}) error {
+	if report, ok := responseData.(*models.HealthReport); ok && !report.Ready() {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+
+		requestID := pkgContext.GetRequestID(ctx)
+		return json.NewEncoder(w).Encode(response.New(requestID, report, nil))
+	}
+
+	return encodeResponse(ctx, w, responseData)
+}
+
+// notFoundErrors are the endpoint errors reported as 404
+var notFoundErrors = map[string]bool{
+	"rocket not found":     true,
+	"alert rule not found": true,
+	"alert not found":      true,
+	"api key not found":    true,
+
+	"message source not found": true,
+
+	"database pool stats not available": true,
+	"event processor not available":     true,
+	"leader election not available":     true,
+	"event partitions not available":    true,
+}
+
 func encodeErrorResponse(ctx context.Context, err error, w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "application/json")
 	
 	// Set appropriate HTTP status based on error type
 	statusCode := http.StatusBadRequest
-	if err.Error() == "rocket not found" {
+	var limitErr *service.RateLimitError
+	if errors.As(err, &limitErr) {
+		statusCode = http.StatusTooManyRequests
+		if limitErr.Scope == service.RateLimitScopeQueue {
+			statusCode = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(limitErr.RetryAfter.Seconds()))))
+	} else if notFoundErrors[err.Error()] {
 		statusCode = http.StatusNotFound
+	} else if service.IsChannelRejected(err) {
+		statusCode = http.StatusForbidden
 	}
 	w.WriteHeader(statusCode)
 	
@@ -0,0 +1,142 @@
+package http_transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"rockets-backend/models"
+	pkgContext "rockets-backend/pkg/context"
+	"rockets-backend/service"
+	"rockets-backend/transport"
+	"strconv"
+
+	goKitHttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gorilla/mux"
+)
+
+// Headers of a signed message. X-Signature is the hex HMAC-SHA256 of
+// "<X-Timestamp>.<body>" keyed with the source secret, optionally prefixed with "sha256=".
+const (
+	sourceHeader    = "X-Source"
+	timestampHeader = "X-Timestamp"
+	signatureHeader = "X-Signature"
+)
+
+// maxSignedBodyBytes bounds the message body buffered for signature verification
+const maxSignedBodyBytes = 1 << 20
+
+// SignatureVerifier returns the source that signed body, or an error wrapping
+// service.ErrInvalidSignature if the signature does not verify
+type SignatureVerifier func(ctx context.Context, source, timestamp, signature string, body []byte) (*models.MessageSource, error)
+
+// WithSignatureVerifier verifies signed messages. Unsigned messages are rejected when
+// required is set, otherwise they are accepted as before.
+func WithSignatureVerifier(verify SignatureVerifier, required bool) Option {
+	return func(c *config) {
+		c.verifySignature = verify
+		c.requireSigned = required
+	}
+}
+
+// signatureMiddleware verifies the signature of messages posted to /messages and adds
+// the signing source to the request context
+func signatureMiddleware(verify SignatureVerifier, required bool, logger log.Logger) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || routeTemplate(r) != "/messages" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			requestID := pkgContext.GetRequestID(ctx)
+			source := r.Header.Get(sourceHeader)
+			timestamp := r.Header.Get(timestampHeader)
+			signature := r.Header.Get(signatureHeader)
+
+			if source == "" && timestamp == "" && signature == "" && !required {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if source == "" || timestamp == "" || signature == "" {
+				_ = level.Warn(logger).Log("requestId", requestID, "msg", "message rejected", "source", source,
+					"reason", "missing signature headers")
+				writeAuthError(ctx, w, http.StatusUnauthorized,
+					errors.New("signed messages need X-Source, X-Timestamp and X-Signature headers"))
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSignedBodyBytes))
+			if err != nil {
+				writeAuthError(ctx, w, http.StatusRequestEntityTooLarge, errors.New("message body too large"))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			verified, err := verify(ctx, source, timestamp, signature, body)
+			if errors.Is(err, service.ErrInvalidSignature) {
+				_ = level.Warn(logger).Log("requestId", requestID, "msg", "message rejected", "source", source,
+					"reason", err)
+				writeAuthError(ctx, w, http.StatusUnauthorized, service.ErrInvalidSignature)
+				return
+			}
+			if err != nil {
+				_ = level.Error(logger).Log("requestId", requestID, "msg", "failed to verify message signature",
+					"source", source, "error", err)
+				writeAuthError(ctx, w, http.StatusServiceUnavailable, errors.New("signature verification unavailable"))
+				return
+			}
+
+			ctx = pkgContext.WithSource(ctx, pkgContext.Source{ID: verified.ID, Name: verified.Name})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func registerSourceRoutes(r *mux.Router, endpoints transport.Endpoints, options []goKitHttp.ServerOption) {
+	r.Methods("GET").Path("/admin/sources").Handler(goKitHttp.NewServer(
+		endpoints.GetMessageSources,
+		decodeEmptyRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods("POST").Path("/admin/sources").Handler(goKitHttp.NewServer(
+		endpoints.CreateMessageSource,
+		decodeCreateMessageSourceRequest,
+		encodeResponse,
+		options...,
+	))
+
+	r.Methods("DELETE").Path("/admin/sources/{id:[0-9]+}").Handler(goKitHttp.NewServer(
+		endpoints.RevokeMessageSource,
+		decodeMessageSourceIDRequest,
+		encodeResponse,
+		options...,
+	))
+}
+
+func decodeCreateMessageSourceRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req transport.CreateMessageSourceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func decodeMessageSourceIDRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	idStr := mux.Vars(r)["id"]
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message source ID: %s", idStr)
+	}
+
+	return transport.MessageSourceIDRequest{ID: id}, nil
+}
@@ -0,0 +1,80 @@
+package http_transport_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"rockets-backend/metrics"
+	"rockets-backend/repository"
+	"rockets-backend/service"
+	"rockets-backend/testutil"
+	"rockets-backend/transport"
+	"rockets-backend/transport/http_transport"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+)
+
+func speedMessage(number int) string {
+	return fmt.Sprintf(`{"metadata":{"channel":"193270a9-c9cf-404a-8f83-838e71d9ae67","messageNumber":%d,
+	"messageTime":"2022-02-02T19:39:05Z","messageType":"RocketSpeedIncreased"},"message":{"by":100}}`, number)
+}
+
+func TestSignedMessages(t *testing.T) {
+	svc := service.NewService(log.NewNopLogger(), repository.NewMemoryRocketRepository(),
+		service.WithAuthRepository(repository.NewMemoryAuthRepository()),
+		service.WithMessageSources(repository.NewMemorySourceRepository(), 5*time.Minute, true))
+
+	ctx := context.Background()
+	first, err := svc.CreateMessageSource(ctx, "first")
+	testutil.AssertNoError(t, err)
+	second, err := svc.CreateMessageSource(ctx, "second")
+	testutil.AssertNoError(t, err)
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		required  bool
+		source    string
+		secret    string
+		timestamp string
+		want      int
+	}{
+		{name: "unsigned message on an unbound channel", want: http.StatusOK},
+		{name: "signed message binds the channel", source: "first", secret: first.Secret, timestamp: now, want: http.StatusOK},
+		{name: "unsigned message on a bound channel", want: http.StatusForbidden},
+		{name: "other source on a bound channel", source: "second", secret: second.Secret, timestamp: now, want: http.StatusForbidden},
+		{name: "forged signature", source: "first", secret: second.Secret, timestamp: now, want: http.StatusUnauthorized},
+		{name: "replayed message", source: "first", secret: first.Secret, timestamp: stale, want: http.StatusUnauthorized},
+		{name: "unsigned message when signatures are required", required: true, want: http.StatusUnauthorized},
+		{name: "signed message when signatures are required", required: true, source: "first", secret: first.Secret, timestamp: now, want: http.StatusOK},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http_transport.NewHttpService(transport.MakeEndpoints(svc), metrics.New(), log.NewNopLogger(),
+				http_transport.WithSignatureVerifier(svc.VerifyMessageSignature, tt.required))
+
+			body := speedMessage(i + 1)
+			req := httptest.NewRequest("POST", "/messages", strings.NewReader(body))
+			if tt.source != "" {
+				req.Header.Set("X-Source", tt.source)
+				req.Header.Set("X-Timestamp", tt.timestamp)
+				req.Header.Set("X-Signature", service.SignMessage(tt.secret, tt.timestamp, []byte(body)))
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("POST /messages = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package http_transport_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"rockets-backend/metrics"
+	"rockets-backend/models"
+	"rockets-backend/repository"
+	"rockets-backend/service"
+	"rockets-backend/testutil"
+	"rockets-backend/transport"
+	"rockets-backend/transport/http_transport"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+)
+
+// fakeWorkerController records the settings it is given
+type fakeWorkerController struct {
+	settings models.WorkerSettings
+}
+
+func (c *fakeWorkerController) Status() models.WorkerStatus {
+	return models.WorkerStatus{WorkerSettings: c.settings, Running: true, ActiveWorkers: c.settings.WorkerCount}
+}
+
+func (c *fakeWorkerController) Refresh(ctx context.Context) error {
+	return nil
+}
+
+func (c *fakeWorkerController) Reconfigure(ctx context.Context, settings models.WorkerSettings) (models.WorkerStatus, error) {
+	if settings.WorkerCount <= 0 {
+		return models.WorkerStatus{}, errors.New("worker count must be positive")
+	}
+	c.settings = settings
+	return c.Status(), nil
+}
+
+func TestWorkerSettingsEndpoints(t *testing.T) {
+	controller := &fakeWorkerController{settings: models.WorkerSettings{PollIntervalMs: 1000, BatchSize: 10, WorkerCount: 2}}
+	svc := service.NewService(log.NewNopLogger(), repository.NewMemoryRocketRepository(),
+		service.WithWorkerControl(func() service.WorkerController { return controller }),
+		service.WithLeaderLeases("rockets-1", func(ctx context.Context) ([]models.LeaderLease, error) { return nil, nil }))
+	handler := http_transport.NewHttpService(transport.MakeEndpoints(svc), metrics.New(), log.NewNopLogger())
+
+	serve := func(method, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, "/admin/worker", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := serve("GET", "")
+	testutil.AssertEqual(t, http.StatusOK, rec.Code)
+
+	rec = serve("PUT", `{"pollIntervalMs":250,"batchSize":50,"workerCount":4,"paused":true}`)
+	testutil.AssertEqual(t, http.StatusOK, rec.Code)
+	var resp struct {
+		Data models.WorkerStatus `json:"data"`
+	}
+	testutil.AssertNoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	testutil.AssertEqual(t, 4, resp.Data.WorkerCount)
+	testutil.AssertEqual(t, true, resp.Data.Paused)
+	testutil.AssertEqual(t, "rockets-1", resp.Data.Instance)
+	testutil.AssertEqual(t, int64(250), controller.settings.PollIntervalMs)
+
+	testutil.AssertEqual(t, http.StatusBadRequest, serve("PUT", `{"pollIntervalMs":250,"batchSize":50,"workerCount":0}`).Code)
+	testutil.AssertEqual(t, http.StatusBadRequest, serve("PUT", `{"workers":4}`).Code)
+	testutil.AssertEqual(t, 4, controller.settings.WorkerCount)
+
+	t.Run("without an event processor", func(t *testing.T) {
+		svc := service.NewService(log.NewNopLogger(), repository.NewMemoryRocketRepository())
+		handler := http_transport.NewHttpService(transport.MakeEndpoints(svc), metrics.New(), log.NewNopLogger())
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/admin/worker", nil))
+		testutil.AssertEqual(t, http.StatusNotFound, rec.Code)
+	})
+}
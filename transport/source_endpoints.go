@@ -0,0 +1,44 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"rockets-backend/service"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type CreateMessageSourceRequest struct {
+	Name string `json:"name"`
+}
+
+type MessageSourceIDRequest struct {
+	ID int64 `json:"id"`
+}
+
+func MakeCreateMessageSourceEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(CreateMessageSourceRequest)
+		return svc.CreateMessageSource(ctx, req.Name)
+	}
+}
+
+func MakeGetMessageSourcesEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		return svc.GetMessageSources(ctx)
+	}
+}
+
+func MakeRevokeMessageSourceEndpoint(svc service.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(MessageSourceIDRequest)
+		revoked, err := svc.RevokeMessageSource(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !revoked {
+			return nil, fmt.Errorf("message source not found")
+		}
+		return map[string]interface{}{"status": "revoked", "id": req.ID}, nil
+	}
+}
@@ -0,0 +1,114 @@
+package tcp_transport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MaxFrameLimit is the largest frame a 4-byte length prefix starting with a zero byte
+// can announce, see detectCodec
+const MaxFrameLimit = 1<<24 - 1
+
+var errFrameTooLarge = errors.New("frame too large")
+
+// codec reads the message frames of a connection and writes acks in the same framing
+type codec interface {
+	// readFrame returns the next frame, an empty frame for a keepalive: a blank line or a
+	// zero length
+	readFrame() ([]byte, error)
+	writeAck(ack Ack) error
+}
+
+// detectCodec picks the framing from the first byte the client sends. A length-prefixed
+// frame starts with a 4-byte big-endian length, whose first byte is zero as frames are at
+// most MaxFrameLimit bytes. Anything else is newline-delimited JSON.
+func detectCodec(r *bufio.Reader, w io.Writer, maxFrameBytes int) (codec, error) {
+	first, err := r.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+	if first[0] == 0 {
+		return &lengthCodec{r: r, w: w, maxFrameBytes: maxFrameBytes}, nil
+	}
+	return &lineCodec{r: r, w: w, maxFrameBytes: maxFrameBytes}, nil
+}
+
+// lineCodec frames each message and ack as a line of JSON
+type lineCodec struct {
+	r             *bufio.Reader
+	w             io.Writer
+	maxFrameBytes int
+}
+
+func (c *lineCodec) readFrame() ([]byte, error) {
+	var frame []byte
+	for {
+		chunk, err := c.r.ReadSlice('\n')
+		if len(frame)+len(chunk) > c.maxFrameBytes+1 { // the newline is not part of the frame
+			return nil, errFrameTooLarge
+		}
+		frame = append(frame, chunk...)
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) && len(bytes.TrimSpace(frame)) > 0 {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		return bytes.TrimSpace(frame), nil
+	}
+}
+
+func (c *lineCodec) writeAck(ack Ack) error {
+	data, err := json.Marshal(ack)
+	if err != nil {
+		return fmt.Errorf("failed to encode ack: %w", err)
+	}
+	_, err = c.w.Write(append(data, '\n'))
+	return err
+}
+
+// lengthCodec frames each message and ack with a 4-byte big-endian length prefix
+type lengthCodec struct {
+	r             *bufio.Reader
+	w             io.Writer
+	maxFrameBytes int
+}
+
+func (c *lengthCodec) readFrame() ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(c.r, header[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(header[:])
+	if size > uint32(c.maxFrameBytes) {
+		return nil, errFrameTooLarge
+	}
+	frame := make([]byte, size)
+	if _, err := io.ReadFull(c.r, frame); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return frame, nil
+}
+
+func (c *lengthCodec) writeAck(ack Ack) error {
+	data, err := json.Marshal(ack)
+	if err != nil {
+		return fmt.Errorf("failed to encode ack: %w", err)
+	}
+	frame := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	copy(frame[4:], data)
+	_, err = c.w.Write(frame)
+	return err
+}
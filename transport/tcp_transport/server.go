@@ -0,0 +1,490 @@
+// Package tcp_transport ingests messages sent over persistent TCP connections, for clients
+// such as embedded simulators where the overhead of an HTTP request per message matters.
+//
+// A client sends IncomingMessage JSON frames, either newline-delimited or prefixed with a
+// 4-byte big-endian length, and gets one Ack per message, in order and in the same
+// framing. Messages are ingested one at a time, so a client sending faster than it is
+// acked is slowed down by TCP flow control.
+package tcp_transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"rockets-backend/metrics"
+	"rockets-backend/models"
+	pkgContext "rockets-backend/pkg/context"
+	"rockets-backend/service"
+	"rockets-backend/tracing"
+	"rockets-backend/transport"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultMaxConnections = 100
+	defaultIdleTimeout    = time.Minute
+	defaultMaxFrameBytes  = 1 << 20
+
+	// ackWriteTimeout bounds how long a client that does not read its acks holds a connection
+	ackWriteTimeout = 10 * time.Second
+)
+
+// Ack statuses
+const (
+	StatusAuthenticated = "authenticated" // the API key frame was accepted
+	StatusIngested      = "ingested"      // the message is stored, EventID is set
+	StatusInvalid       = "invalid"       // the frame is not a valid message, do not resend it
+	StatusRejected      = "rejected"      // the message will never be accepted, do not resend it
+	StatusFailed        = "failed"        // the message was not stored, it can be sent again
+	StatusError         = "error"         // the server closes the connection after this ack
+)
+
+// ErrServerClosed is returned by Serve after Shutdown
+var ErrServerClosed = errors.New("tcp: server closed")
+
+// errShuttingDown stops reading from a connection once the server shuts down
+var errShuttingDown = errors.New("server shutting down")
+
+// Ack answers a frame. Seq counts the message frames of the connection from 1, the API
+// key frame is acked with 0.
+type Ack struct {
+	Seq           int64  `json:"seq"`
+	Status        string `json:"status"`
+	EventID       int64  `json:"eventId,omitempty"`
+	Channel       string `json:"channel,omitempty"`
+	MessageNumber int    `json:"messageNumber,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// authFrame is the first frame of a connection when API keys are required
+type authFrame struct {
+	APIKey string `json:"apiKey"`
+}
+
+// Option configures optional server settings
+type Option func(*Server)
+
+// WithAuthenticator requires connections to start with an {"apiKey": "..."} frame for a
+// key with the ingest role
+func WithAuthenticator(authenticate transport.Authenticator) Option {
+	return func(s *Server) {
+		s.authenticate = authenticate
+	}
+}
+
+// WithSignaturesRequired rejects all connections, messages cannot be signed over TCP yet
+func WithSignaturesRequired(required bool) Option {
+	return func(s *Server) {
+		s.requireSigned = required
+	}
+}
+
+// WithLimits sets the number of open connections, how long a connection may be idle
+// before it is closed, and the largest accepted frame, capped at MaxFrameLimit
+func WithLimits(maxConnections int, idleTimeout time.Duration, maxFrameBytes int) Option {
+	return func(s *Server) {
+		s.maxConnections = maxConnections
+		s.idleTimeout = idleTimeout
+		s.maxFrameBytes = min(maxFrameBytes, MaxFrameLimit)
+	}
+}
+
+// Server accepts ingestion connections
+type Server struct {
+	ingest         endpoint.Endpoint
+	m              *metrics.Metrics
+	logger         log.Logger
+	authenticate   transport.Authenticator
+	requireSigned  bool
+	maxConnections int
+	idleTimeout    time.Duration
+	maxFrameBytes  int
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closing   bool
+	shutdown  chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewServer creates a server for the ProcessMessage endpoint
+func NewServer(ingest endpoint.Endpoint, m *metrics.Metrics, logger log.Logger, opts ...Option) *Server {
+	s := &Server{
+		ingest:         ingest,
+		m:              m,
+		logger:         log.With(logger, "transport", "tcp"),
+		maxConnections: defaultMaxConnections,
+		idleTimeout:    defaultIdleTimeout,
+		maxFrameBytes:  defaultMaxFrameBytes,
+		listeners:      make(map[net.Listener]struct{}),
+		conns:          make(map[net.Conn]struct{}),
+		shutdown:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Serve accepts connections on listener until Shutdown, then returns ErrServerClosed
+func (s *Server) Serve(listener net.Listener) error {
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		listener.Close()
+		return ErrServerClosed
+	}
+	s.listeners[listener] = struct{}{}
+	s.mu.Unlock()
+
+	backoff := 5 * time.Millisecond
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case <-s.shutdown:
+				return ErrServerClosed
+			default:
+			}
+			if !errors.Is(err, net.ErrClosed) {
+				// Such as too many open files, keep accepting once it passes
+				_ = level.Error(s.logger).Log("msg", "failed to accept connection", "retryIn", backoff, "error", err)
+				time.Sleep(backoff)
+				backoff = min(2*backoff, time.Second)
+				continue
+			}
+			return err
+		}
+		backoff = 5 * time.Millisecond
+
+		if !s.track(conn) {
+			_ = level.Warn(s.logger).Log("msg", "connection rejected", "client", conn.RemoteAddr(),
+				"reason", "too many connections")
+			conn.Close()
+			continue
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// track registers a new connection, false when the connection limit is reached
+func (s *Server) track(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closing || len(s.conns) >= s.maxConnections {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	s.wg.Add(1)
+	s.setConnections(len(s.conns))
+	return true
+}
+
+func (s *Server) untrack(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, conn)
+	s.setConnections(len(s.conns))
+	s.wg.Done()
+}
+
+// armRead sets the idle deadline for the next frame, false once the server is shutting
+// down. Shutdown sets the deadlines under the same lock, so it cannot be overwritten.
+func (s *Server) armRead(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closing {
+		return false
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(s.idleTimeout))
+	return true
+}
+
+// Shutdown stops accepting connections and lets every connection finish the message it is
+// ingesting, then closes it. A message waiting for a rate limit is acked as failed. When ctx
+// is done first the remaining connections are closed and ctx's error is returned.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closing {
+		s.closing = true
+		close(s.shutdown)
+		for listener := range s.listeners {
+			listener.Close()
+		}
+		// Wake up the connections waiting for a frame
+		for conn := range s.conns {
+			_ = conn.SetReadDeadline(time.Now())
+		}
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		for conn := range s.conns {
+			conn.Close()
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// connection is the state of one client connection
+type connection struct {
+	conn   net.Conn
+	codec  codec
+	ctx    context.Context
+	logger log.Logger
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer s.untrack(conn)
+	defer conn.Close()
+
+	client := conn.RemoteAddr().String()
+	if host, _, err := net.SplitHostPort(client); err == nil {
+		client = host
+	}
+	c := &connection{
+		conn:   conn,
+		ctx:    pkgContext.WithClientAddr(context.Background(), client),
+		logger: log.With(s.logger, "client", conn.RemoteAddr()),
+	}
+	_ = level.Debug(c.logger).Log("msg", "connection opened")
+
+	if !s.armRead(conn) {
+		return
+	}
+	reader := bufio.NewReader(conn)
+	var err error
+	if c.codec, err = detectCodec(reader, conn, s.maxFrameBytes); err != nil {
+		s.logClosed(c, err)
+		return
+	}
+
+	if s.requireSigned {
+		s.closeWithError(c, 0, transport.ErrSignatureRequired)
+		return
+	}
+	if s.authenticate != nil && !s.authenticateConn(c) {
+		return
+	}
+
+	for seq := int64(1); ; seq++ {
+		frame, err := s.nextFrame(c)
+		if err != nil {
+			s.readFailed(c, seq, err)
+			return
+		}
+
+		ack := s.handle(c, seq, frame)
+		if err := s.writeAck(c, ack); err != nil {
+			_ = level.Warn(c.logger).Log("msg", "failed to send ack", "seq", seq, "error", err)
+			return
+		}
+		if ack.Status == StatusError {
+			return
+		}
+	}
+}
+
+// nextFrame waits for the next frame that is not a keepalive. Each frame, keepalives
+// included, extends the idle deadline.
+func (s *Server) nextFrame(c *connection) ([]byte, error) {
+	for {
+		if !s.armRead(c.conn) {
+			return nil, errShuttingDown
+		}
+		frame, err := c.codec.readFrame()
+		if err != nil || len(frame) > 0 {
+			return frame, err
+		}
+	}
+}
+
+// readFailed closes a connection whose next frame could not be read
+func (s *Server) readFailed(c *connection, seq int64, err error) {
+	if errors.Is(err, errFrameTooLarge) {
+		s.closeWithError(c, seq, fmt.Errorf("frame larger than %d bytes", s.maxFrameBytes))
+		return
+	}
+	s.logClosed(c, err)
+}
+
+// authenticateConn checks the API key frame, false when the connection was closed
+func (s *Server) authenticateConn(c *connection) bool {
+	frame, err := s.nextFrame(c)
+	if err != nil {
+		s.readFailed(c, 0, err)
+		return false
+	}
+
+	var auth authFrame
+	if err := json.Unmarshal(frame, &auth); err != nil || auth.APIKey == "" {
+		s.closeWithError(c, 0, errors.New("missing API key, the first frame must be {\"apiKey\": \"...\"}"))
+		return false
+	}
+	ctx, apiKey, err := s.authenticate.Authenticate(c.ctx, auth.APIKey, models.RoleIngest)
+	if errors.Is(err, transport.ErrAuthUnavailable) {
+		_ = level.Error(c.logger).Log("msg", "failed to authenticate connection", "error", err)
+		s.closeWithError(c, 0, transport.ErrAuthUnavailable)
+		return false
+	}
+	if err != nil {
+		s.closeWithError(c, 0, err)
+		return false
+	}
+
+	c.ctx = ctx
+	c.logger = log.With(c.logger, "apiKey", apiKey.Name)
+	_ = level.Debug(c.logger).Log("msg", "connection authenticated", "role", apiKey.Role)
+
+	if err := s.writeAck(c, Ack{Status: StatusAuthenticated}); err != nil {
+		_ = level.Warn(c.logger).Log("msg", "failed to send ack", "seq", 0, "error", err)
+		return false
+	}
+	return true
+}
+
+// handle ingests a message frame. A rate limited message waits for its limit, which keeps
+// the connection from reading further frames.
+func (s *Server) handle(c *connection, seq int64, frame []byte) Ack {
+	ctx := pkgContext.WithRequestID(c.ctx, pkgContext.GenerateRequestID())
+	requestID := pkgContext.GetRequestID(ctx)
+	ack := Ack{Seq: seq}
+
+	var msg models.IncomingMessage
+	if err := json.Unmarshal(frame, &msg); err != nil {
+		_ = level.Warn(c.logger).Log("requestId", requestID, "msg", "invalid frame", "seq", seq, "error", err)
+		ack.Status, ack.Error = StatusInvalid, err.Error()
+		s.count(ack.Status)
+		return ack
+	}
+	ack.Channel, ack.MessageNumber = msg.Metadata.Channel, msg.Metadata.MessageNumber
+
+	for {
+		eventID, err := s.ingestMessage(ctx, seq, msg)
+		if err == nil {
+			ack.Status, ack.EventID = StatusIngested, eventID
+			break
+		}
+
+		var limitErr *service.RateLimitError
+		if !errors.As(err, &limitErr) {
+			ack.Status, ack.Error = StatusFailed, err.Error()
+			if errors.Is(err, service.ErrInvalidMessage) {
+				ack.Status = StatusInvalid
+			} else if service.IsChannelRejected(err) {
+				ack.Status = StatusRejected
+			}
+			_ = level.Warn(c.logger).Log("requestId", requestID, "msg", "message not ingested", "seq", seq,
+				"status", ack.Status, "error", err)
+			break
+		}
+		if !s.wait(limitErr.RetryAfter) {
+			ack.Status, ack.Error = StatusFailed, "server is shutting down"
+			break
+		}
+	}
+	s.count(ack.Status)
+	return ack
+}
+
+// ingestMessage calls the endpoint in a span of its own, which the event processor continues
+func (s *Server) ingestMessage(ctx context.Context, seq int64, msg models.IncomingMessage) (eventID int64, err error) {
+	ctx, span := tracing.StartSpan(ctx, "tcp ingest", trace.WithSpanKind(trace.SpanKindServer))
+	defer func() { tracing.EndSpan(span, err) }()
+	span.SetAttributes(
+		attribute.String("request.id", pkgContext.GetRequestID(ctx)),
+		attribute.Int64("tcp.seq", seq),
+	)
+
+	resp, err := s.ingest(ctx, msg)
+	if err != nil {
+		return 0, err
+	}
+	fields, _ := resp.(map[string]interface{})
+	eventID, ok := fields["event_id"].(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected ingest response: %v", resp)
+	}
+	return eventID, nil
+}
+
+// wait sleeps for d, false when the server shuts down first
+func (s *Server) wait(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-s.shutdown:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (s *Server) writeAck(c *connection, ack Ack) error {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(ackWriteTimeout))
+	return c.codec.writeAck(ack)
+}
+
+// closeWithError sends an error ack before the connection is closed
+func (s *Server) closeWithError(c *connection, seq int64, err error) {
+	_ = level.Warn(c.logger).Log("msg", "connection rejected", "seq", seq, "reason", err)
+	_ = s.writeAck(c, Ack{Seq: seq, Status: StatusError, Error: err.Error()})
+}
+
+// logClosed logs why reading from a connection stopped
+func (s *Server) logClosed(c *connection, err error) {
+	reason := "error"
+	switch {
+	case errors.Is(err, io.EOF):
+		reason = "closed by client"
+	case errors.Is(err, errShuttingDown):
+		reason = "server shutting down"
+	case errors.Is(err, os.ErrDeadlineExceeded):
+		select {
+		case <-s.shutdown:
+			reason = "server shutting down"
+		default:
+			reason = "idle timeout"
+		}
+	}
+	if reason == "error" {
+		_ = level.Warn(c.logger).Log("msg", "connection closed", "reason", reason, "error", err)
+		return
+	}
+	_ = level.Debug(c.logger).Log("msg", "connection closed", "reason", reason)
+}
+
+func (s *Server) count(status string) {
+	if s.m != nil {
+		s.m.TCPMessages.With("result", status).Add(1)
+	}
+}
+
+func (s *Server) setConnections(n int) {
+	if s.m != nil {
+		s.m.TCPConnections.Set(float64(n))
+	}
+}
@@ -0,0 +1,276 @@
+package tcp_transport_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"rockets-backend/metrics"
+	"rockets-backend/models"
+	"rockets-backend/ratelimit"
+	"rockets-backend/repository"
+	"rockets-backend/service"
+	"rockets-backend/testutil"
+	"rockets-backend/transport"
+	"rockets-backend/transport/tcp_transport"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+)
+
+const testChannel = "193270a9-c9cf-404a-8f83-838e71d9ae67"
+
+func speedMessage(number int) string {
+	return fmt.Sprintf(`{"metadata":{"channel":%q,"messageNumber":%d,"messageTime":"2022-02-02T19:39:05Z",`+
+		`"messageType":"RocketSpeedIncreased"},"message":{"by":100}}`, testChannel, number)
+}
+
+// serve starts a server for svc on a local port and returns its address
+func serve(t *testing.T, svc service.Service, opts ...tcp_transport.Option) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	testutil.AssertNoError(t, err)
+	server := tcp_transport.NewServer(transport.MakeEndpoints(svc).ProcessMessage, metrics.New(), log.NewNopLogger(),
+		opts...)
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Shutdown(context.Background()) })
+	return listener.Addr().String()
+}
+
+func dial(t *testing.T, addr string) net.Conn {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	testutil.AssertNoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+func readAck(t *testing.T, reader *bufio.Reader) tcp_transport.Ack {
+	t.Helper()
+	line, err := reader.ReadBytes('\n')
+	testutil.AssertNoError(t, err)
+	var ack tcp_transport.Ack
+	testutil.AssertNoError(t, json.Unmarshal(line, &ack))
+	return ack
+}
+
+// assertClosed checks that the server closed conn
+func assertClosed(t *testing.T, reader io.Reader) {
+	t.Helper()
+	if _, err := reader.Read(make([]byte, 1)); err != io.EOF {
+		t.Fatalf("Expected the connection to be closed, got %v", err)
+	}
+}
+
+func TestNewlineDelimitedFrames(t *testing.T) {
+	repo := repository.NewMemoryRocketRepository()
+	// Every second message of the channel is rate limited at first
+	svc := service.NewService(log.NewNopLogger(), repo,
+		service.WithIngestLimits(service.IngestLimits{Channel: ratelimit.Limit{Rate: 50, Burst: 1}}))
+	conn := dial(t, serve(t, svc))
+	reader := bufio.NewReader(conn)
+
+	poison := strings.Replace(speedMessage(4), testChannel, "not-a-uuid", 1)
+	_, err := io.WriteString(conn, speedMessage(1)+"\n\n{not json}\n"+poison+"\n"+speedMessage(2)+"\n"+
+		speedMessage(3)+"\n")
+	testutil.AssertNoError(t, err)
+
+	first := readAck(t, reader)
+	testutil.AssertEqual(t, int64(1), first.Seq)
+	testutil.AssertEqual(t, tcp_transport.StatusIngested, first.Status)
+	testutil.AssertEqual(t, testChannel, first.Channel)
+	testutil.AssertEqual(t, 1, first.MessageNumber)
+
+	invalid := readAck(t, reader)
+	testutil.AssertEqual(t, int64(2), invalid.Seq)
+	testutil.AssertEqual(t, tcp_transport.StatusInvalid, invalid.Status)
+
+	// A message that can never be stored must not be sent again
+	invalid = readAck(t, reader)
+	testutil.AssertEqual(t, int64(3), invalid.Seq)
+	testutil.AssertEqual(t, tcp_transport.StatusInvalid, invalid.Status)
+
+	for _, number := range []int{2, 3} {
+		ack := readAck(t, reader)
+		testutil.AssertEqual(t, tcp_transport.StatusIngested, ack.Status)
+		testutil.AssertEqual(t, number, ack.MessageNumber)
+		event, err := repo.GetRocketEvent(context.Background(), ack.EventID)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, number, event.MessageNumber)
+	}
+}
+
+func TestLengthPrefixedFrames(t *testing.T) {
+	conn := dial(t, serve(t, service.NewService(log.NewNopLogger(), repository.NewMemoryRocketRepository())))
+
+	frame := func(data string) []byte {
+		return binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	}
+	// A zero length frame is a keepalive
+	message := speedMessage(1)
+	_, err := conn.Write(append(append(frame(""), frame(message)...), message...))
+	testutil.AssertNoError(t, err)
+
+	var header [4]byte
+	_, err = io.ReadFull(conn, header[:])
+	testutil.AssertNoError(t, err)
+	data := make([]byte, binary.BigEndian.Uint32(header[:]))
+	_, err = io.ReadFull(conn, data)
+	testutil.AssertNoError(t, err)
+
+	var ack tcp_transport.Ack
+	testutil.AssertNoError(t, json.Unmarshal(data, &ack))
+	testutil.AssertEqual(t, tcp_transport.StatusIngested, ack.Status)
+	testutil.AssertEqual(t, int64(1), ack.Seq)
+}
+
+func TestTCPAuth(t *testing.T) {
+	svc := service.NewService(log.NewNopLogger(), repository.NewMemoryRocketRepository(),
+		service.WithAuthRepository(repository.NewMemoryAuthRepository()))
+	addr := serve(t, svc, tcp_transport.WithAuthenticator(svc.AuthenticateAPIKey))
+
+	keys := map[string]string{}
+	for _, role := range []string{models.RoleIngest, models.RoleRead} {
+		created, err := svc.CreateAPIKey(context.Background(), role+" key", role)
+		testutil.AssertNoError(t, err)
+		keys[role] = created.Key
+	}
+
+	tests := []struct {
+		name  string
+		first string
+		want  string
+	}{
+		{name: "missing key", first: speedMessage(1), want: tcp_transport.StatusError},
+		{name: "unknown key", first: `{"apiKey":"rk_0_0"}`, want: tcp_transport.StatusError},
+		{name: "read key cannot ingest", first: fmt.Sprintf(`{"apiKey":%q}`, keys[models.RoleRead]), want: tcp_transport.StatusError},
+		{name: "ingest key ingests", first: fmt.Sprintf(`{"apiKey":%q}`, keys[models.RoleIngest]), want: tcp_transport.StatusAuthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := dial(t, addr)
+			reader := bufio.NewReader(conn)
+			_, err := io.WriteString(conn, tt.first+"\n")
+			testutil.AssertNoError(t, err)
+
+			ack := readAck(t, reader)
+			testutil.AssertEqual(t, int64(0), ack.Seq)
+			testutil.AssertEqual(t, tt.want, ack.Status)
+			if ack.Status == tcp_transport.StatusError {
+				assertClosed(t, reader)
+				return
+			}
+
+			_, err = io.WriteString(conn, speedMessage(1)+"\n")
+			testutil.AssertNoError(t, err)
+			testutil.AssertEqual(t, tcp_transport.StatusIngested, readAck(t, reader).Status)
+		})
+	}
+}
+
+func TestConnectionLimits(t *testing.T) {
+	svc := service.NewService(log.NewNopLogger(), repository.NewMemoryRocketRepository())
+
+	t.Run("frames larger than the limit close the connection", func(t *testing.T) {
+		conn := dial(t, serve(t, svc, tcp_transport.WithLimits(1, time.Minute, 64)))
+		reader := bufio.NewReader(conn)
+		_, err := io.WriteString(conn, speedMessage(1)+"\n")
+		testutil.AssertNoError(t, err)
+
+		ack := readAck(t, reader)
+		testutil.AssertEqual(t, tcp_transport.StatusError, ack.Status)
+		testutil.AssertEqual(t, "frame larger than 64 bytes", ack.Error)
+		assertClosed(t, reader)
+	})
+
+	t.Run("keepalives extend the idle timeout", func(t *testing.T) {
+		conn := dial(t, serve(t, svc, tcp_transport.WithLimits(1, 200*time.Millisecond, 1<<20)))
+		reader := bufio.NewReader(conn)
+		for i := 0; i < 8; i++ {
+			_, err := io.WriteString(conn, "\n")
+			testutil.AssertNoError(t, err)
+			time.Sleep(50 * time.Millisecond)
+		}
+
+		_, err := io.WriteString(conn, speedMessage(1)+"\n")
+		testutil.AssertNoError(t, err)
+		ack := readAck(t, reader)
+		testutil.AssertEqual(t, int64(1), ack.Seq)
+		testutil.AssertEqual(t, tcp_transport.StatusIngested, ack.Status)
+	})
+
+	t.Run("connections over the limit are closed", func(t *testing.T) {
+		addr := serve(t, svc, tcp_transport.WithLimits(1, 300*time.Millisecond, 1<<20))
+		first := dial(t, addr)
+		assertClosed(t, dial(t, addr))
+
+		// The first one is closed once it has been idle for too long
+		started := time.Now()
+		assertClosed(t, first)
+		if elapsed := time.Since(started); elapsed > 2*time.Second {
+			t.Fatalf("Expected the idle connection to be closed after 300ms, took %s", elapsed)
+		}
+	})
+}
+
+func TestSignaturesRequired(t *testing.T) {
+	svc := service.NewService(log.NewNopLogger(), repository.NewMemoryRocketRepository())
+	conn := dial(t, serve(t, svc, tcp_transport.WithSignaturesRequired(true)))
+	reader := bufio.NewReader(conn)
+
+	_, err := io.WriteString(conn, speedMessage(1)+"\n")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, tcp_transport.StatusError, readAck(t, reader).Status)
+	assertClosed(t, reader)
+}
+
+func TestUnexpectedIngestResponse(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	testutil.AssertNoError(t, err)
+	ingest := func(ctx context.Context, request interface{}) (interface{}, error) {
+		return map[string]interface{}{"status": "ingested"}, nil
+	}
+	server := tcp_transport.NewServer(ingest, nil, log.NewNopLogger())
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Shutdown(context.Background()) })
+
+	conn := dial(t, listener.Addr().String())
+	reader := bufio.NewReader(conn)
+	_, err = io.WriteString(conn, speedMessage(1)+"\n"+speedMessage(2)+"\n")
+	testutil.AssertNoError(t, err)
+
+	// The connection survives and keeps answering
+	for seq := int64(1); seq <= 2; seq++ {
+		ack := readAck(t, reader)
+		testutil.AssertEqual(t, seq, ack.Seq)
+		testutil.AssertEqual(t, tcp_transport.StatusFailed, ack.Status)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	testutil.AssertNoError(t, err)
+	svc := service.NewService(log.NewNopLogger(), repository.NewMemoryRocketRepository())
+	server := tcp_transport.NewServer(transport.MakeEndpoints(svc).ProcessMessage, nil, log.NewNopLogger())
+	served := make(chan error, 1)
+	go func() { served <- server.Serve(listener) }()
+
+	conn := dial(t, listener.Addr().String())
+	reader := bufio.NewReader(conn)
+	_, err = io.WriteString(conn, speedMessage(1)+"\n")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, tcp_transport.StatusIngested, readAck(t, reader).Status)
+
+	// The idle connection is closed right away instead of after the idle timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	testutil.AssertNoError(t, server.Shutdown(ctx))
+	assertClosed(t, reader)
+	testutil.AssertEqual(t, tcp_transport.ErrServerClosed, <-served)
+}
@@ -2,30 +2,64 @@ package worker
 
 import (
 	"context"
+	"fmt"
+	"rockets-backend/database"
+	"rockets-backend/metrics"
 	"rockets-backend/models"
-	"rockets-backend/pkg"
+	pkgContext "rockets-backend/pkg/context"
 	"rockets-backend/repository"
 	"rockets-backend/service"
-	"strconv"
+	"rockets-backend/tracing"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// EventProcessor handles background processing of rocket events
+// EventProcessor handles background processing of rocket events. Its settings can be
+// changed and processing paused while it runs, see Reconfigure.
 type EventProcessor struct {
-	service      service.Service
-	repository   repository.RocketRepository
-	logger       log.Logger
-	pollInterval time.Duration
-	batchSize    int
-	workerCount  int
-	stopChan     chan struct{}
-	wg           sync.WaitGroup
-	running      bool
-	mu           sync.RWMutex
+	service    service.Service
+	repository repository.RocketRepository
+	settings   database.WorkerSettingsStore
+	logger     log.Logger
+	metrics    *metrics.Metrics
+	config     Config
+	paused     bool
+	stopChan   chan struct{}
+	cancel     context.CancelFunc
+	ctx        context.Context
+	wg         sync.WaitGroup
+	running    bool
+	mu         sync.RWMutex
+
+	// workers holds a quit channel per worker goroutine, the last ones are stopped when scaling down
+	workers      []chan struct{}
+	nextWorkerID int
+	// changed is closed and replaced whenever the settings change, so workers pick up a new poll interval
+	changed chan struct{}
+	active  atomic.Int64
+	// syncMu orders storing settings and applying the stored ones
+	syncMu sync.Mutex
+
+	// inFlight holds the events being processed, reported as abandoned when a shutdown times out
+	inFlight   map[int64]struct{}
+	released   int
+	unreleased []int64
+	flightMu   sync.Mutex
+}
+
+// ShutdownReport tells what a shutdown left unprocessed
+type ShutdownReport struct {
+	// Released counts the claimed events returned to pending for the next start
+	Released int
+	// Abandoned lists the events left in processing, because they were still being
+	// processed when the deadline passed or could not be released
+	Abandoned []int64
 }
 
 // Config holds configuration for the event processor
@@ -33,32 +67,43 @@ type Config struct {
 	PollInterval time.Duration // How often to check for new events
 	BatchSize    int           // How many events to process at once
 	WorkerCount  int           // Number of concurrent workers
+	ClaimTimeout time.Duration // How long a claimed event may stay processing before it is claimed again
 }
 
-// DefaultConfig returns sensible default configuration
-func DefaultConfig() Config {
-	pollInterval, _ := strconv.Atoi(pkg.GetEnv("POLLING_INTEVAL_SECONDS", "1"))
-	batchSize, _ := strconv.Atoi(pkg.GetEnv("POLLING_BATCH_SIZE", "10"))
-	workerCount, _ := strconv.Atoi(pkg.GetEnv("POLLING_WORKER_COUNT", "2"))
-
-	return Config{
-		PollInterval: time.Duration(pollInterval) * time.Second,
-		BatchSize:    batchSize,
-		WorkerCount:  workerCount,
+// Validate reports settings the processor cannot run with
+func (c Config) Validate() error {
+	if c.PollInterval <= 0 {
+		return fmt.Errorf("poll interval must be positive")
+	}
+	if c.BatchSize <= 0 {
+		return fmt.Errorf("batch size must be positive")
 	}
+	if c.WorkerCount <= 0 {
+		return fmt.Errorf("worker count must be positive")
+	}
+	if c.ClaimTimeout <= 0 {
+		return fmt.Errorf("claim timeout must be positive")
+	}
+	return nil
 }
 
-// NewEventProcessor creates a new event processor
-func NewEventProcessor(svc service.Service, repo repository.RocketRepository, logger log.Logger, config Config) *EventProcessor {
-	return &EventProcessor{
-		service:      svc,
-		repository:   repo,
-		logger:       logger,
-		pollInterval: config.PollInterval,
-		batchSize:    config.BatchSize,
-		workerCount:  config.WorkerCount,
-		stopChan:     make(chan struct{}),
+// NewEventProcessor creates a new event processor. It runs with config until settings are
+// stored in settings, shared with the event processors of the other instances.
+func NewEventProcessor(svc service.Service, repo repository.RocketRepository, settings database.WorkerSettingsStore,
+	logger log.Logger, m *metrics.Metrics, config Config) *EventProcessor {
+	p := &EventProcessor{
+		service:    svc,
+		repository: repo,
+		settings:   settings,
+		logger:     logger,
+		metrics:    m,
+		config:     config,
+		stopChan:   make(chan struct{}),
+		changed:    make(chan struct{}),
+		inFlight:   make(map[int64]struct{}),
 	}
+	p.recordSettings()
+	return p
 }
 
 // Start begins processing events in the background
@@ -70,40 +115,82 @@ func (p *EventProcessor) Start(ctx context.Context) error {
 		return nil // Already running
 	}
 
+	// Cancelled on Stop so that in-flight polling queries are aborted
+	p.ctx, p.cancel = context.WithCancel(ctx)
+	p.stopChan = make(chan struct{})
+
 	p.running = true
-	_ = level.Info(p.logger).Log("msg", "starting event processor", "workers", p.workerCount, "pollInterval",
-		p.pollInterval, "batchSize", p.batchSize)
+	_ = level.Info(p.logger).Log("msg", "starting event processor", "workers", p.config.WorkerCount, "pollInterval",
+		p.config.PollInterval, "batchSize", p.config.BatchSize, "paused", p.paused)
 
-	// Start worker goroutines
-	for i := 0; i < p.workerCount; i++ {
-		p.wg.Add(1)
-		go p.worker(ctx, i)
-	}
+	// Start worker goroutines, and follow the settings changed on other instances
+	p.scaleLocked(p.config.WorkerCount)
+	p.wg.Add(1)
+	go p.syncSettings(p.ctx, p.stopChan)
 
 	return nil
 }
 
-// Stop gracefully shuts down the event processor
+// Stop gracefully shuts down the event processor, waiting as long as the current events take
 func (p *EventProcessor) Stop() error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+	_, err := p.Shutdown(context.Background())
+	return err
+}
 
+// Shutdown stops the workers. Each finishes the event it is processing and releases
+// the rest of its claimed batch back to pending. If ctx ends first, Shutdown returns
+// without waiting for the remaining events, which are reported as abandoned.
+func (p *EventProcessor) Shutdown(ctx context.Context) (ShutdownReport, error) {
+	p.mu.Lock()
 	if !p.running {
-		return nil // Already stopped
+		p.mu.Unlock()
+		return ShutdownReport{}, nil // Already stopped
 	}
 
 	_ = level.Info(p.logger).Log("msg", "stopping event processor")
 
-	// Signal all workers to stop
+	// Signal all workers to stop. The lock is released before waiting because
+	// workers read their settings under it.
 	close(p.stopChan)
+	p.cancel()
+	p.workers = nil
+	p.running = false
+	p.mu.Unlock()
+
+	// Only count what this shutdown releases, not earlier pauses and scale downs
+	p.flightMu.Lock()
+	p.released = 0
+	p.flightMu.Unlock()
 
 	// Wait for all workers to finish
-	p.wg.Wait()
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("event processor did not stop in time: %w", ctx.Err())
+	}
 
-	p.running = false
-	_ = level.Info(p.logger).Log("msg", "event processor stopped")
+	p.flightMu.Lock()
+	report := ShutdownReport{Released: p.released, Abandoned: p.unreleased}
+	for id := range p.inFlight {
+		report.Abandoned = append(report.Abandoned, id)
+	}
+	p.unreleased = nil
+	p.flightMu.Unlock()
+
+	if err != nil || len(report.Abandoned) > 0 {
+		_ = level.Error(p.logger).Log("msg", "event processor stopped with abandoned events", "released",
+			report.Released, "abandoned", fmt.Sprint(report.Abandoned), "error", err)
+	} else {
+		_ = level.Info(p.logger).Log("msg", "event processor stopped", "released", report.Released)
+	}
 
-	return nil
+	return report, err
 }
 
 // IsRunning returns whether the processor is currently running
@@ -113,35 +200,239 @@ func (p *EventProcessor) IsRunning() bool {
 	return p.running
 }
 
+// Status returns the current settings and activity of the processor
+func (p *EventProcessor) Status() models.WorkerStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return models.WorkerStatus{
+		WorkerSettings: models.WorkerSettings{
+			PollIntervalMs: p.config.PollInterval.Milliseconds(),
+			BatchSize:      p.config.BatchSize,
+			WorkerCount:    p.config.WorkerCount,
+			Paused:         p.paused,
+		},
+		Running:       p.running,
+		ActiveWorkers: int(p.active.Load()),
+		InFlight:      p.inFlightCount(),
+	}
+}
+
+// Reconfigure stores new settings for every instance and applies them here without a
+// restart, other instances pick them up at their next poll, see Refresh. A new poll
+// interval and batch size are used from the next poll. Removed workers finish the event
+// they are processing and release the rest of their batch to pending. Paused workers stop
+// polling, an event being processed is finished, which Status reports as in flight. The
+// claim timeout is kept.
+func (p *EventProcessor) Reconfigure(ctx context.Context, settings models.WorkerSettings) (models.WorkerStatus, error) {
+	config, err := p.settingsConfig(settings)
+	if err != nil {
+		return models.WorkerStatus{}, err
+	}
+
+	// A refresh must not apply the settings it read before these are saved
+	p.syncMu.Lock()
+	defer p.syncMu.Unlock()
+	if err := p.settings.Save(ctx, settings); err != nil {
+		return models.WorkerStatus{}, err
+	}
+	p.apply(config, settings.Paused)
+
+	return p.Status(), nil
+}
+
+// Refresh applies the settings stored by Reconfigure on any instance, if they differ from
+// the current ones. Until settings are stored the configured ones are kept.
+func (p *EventProcessor) Refresh(ctx context.Context) error {
+	p.syncMu.Lock()
+	defer p.syncMu.Unlock()
+
+	settings, err := p.settings.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if settings == nil || *settings == p.Status().WorkerSettings {
+		return nil
+	}
+
+	config, err := p.settingsConfig(*settings)
+	if err != nil {
+		return fmt.Errorf("invalid stored worker settings: %w", err)
+	}
+	p.apply(config, settings.Paused)
+	return nil
+}
+
+// settingsConfig returns the processor config for settings, keeping the claim timeout
+func (p *EventProcessor) settingsConfig(settings models.WorkerSettings) (Config, error) {
+	config := Config{
+		PollInterval: time.Duration(settings.PollIntervalMs) * time.Millisecond,
+		BatchSize:    settings.BatchSize,
+		WorkerCount:  settings.WorkerCount,
+		ClaimTimeout: p.claimTimeout(),
+	}
+	return config, config.Validate()
+}
+
+// apply switches to config and scales the workers to it
+func (p *EventProcessor) apply(config Config, paused bool) {
+	p.mu.Lock()
+	previous, wasPaused := p.config, p.paused
+	p.config, p.paused = config, paused
+	if p.running {
+		p.scaleLocked(config.WorkerCount)
+	}
+	close(p.changed)
+	p.changed = make(chan struct{})
+	p.mu.Unlock()
+
+	p.recordSettings()
+	_ = level.Info(p.logger).Log("msg", "event processor reconfigured",
+		"workers", previous.WorkerCount, "newWorkers", config.WorkerCount,
+		"pollInterval", previous.PollInterval, "newPollInterval", config.PollInterval,
+		"batchSize", previous.BatchSize, "newBatchSize", config.BatchSize,
+		"paused", wasPaused, "newPaused", paused)
+}
+
+// syncSettings refreshes the settings right away and then every poll interval, so a change
+// made on another instance applies here within one poll
+func (p *EventProcessor) syncSettings(ctx context.Context, stop <-chan struct{}) {
+	defer p.wg.Done()
+
+	refresh := func() {
+		if err := p.Refresh(ctx); err != nil && ctx.Err() == nil {
+			_ = level.Error(p.logger).Log("msg", "failed to refresh worker settings", "error", err)
+		}
+	}
+	refresh()
+
+	interval, changed := p.pollSettings()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-changed:
+			var newInterval time.Duration
+			newInterval, changed = p.pollSettings()
+			if newInterval != interval {
+				interval = newInterval
+				ticker.Reset(interval)
+			}
+		case <-ticker.C:
+			refresh()
+		}
+	}
+}
+
+// scaleLocked starts or stops workers until count are running, the caller holds mu
+func (p *EventProcessor) scaleLocked(count int) {
+	for len(p.workers) < count {
+		quit := make(chan struct{})
+		p.workers = append(p.workers, quit)
+		p.wg.Add(1)
+		go p.worker(p.ctx, p.nextWorkerID, p.stopChan, quit)
+		p.nextWorkerID++
+	}
+	for len(p.workers) > count {
+		last := len(p.workers) - 1
+		close(p.workers[last])
+		p.workers = p.workers[:last]
+	}
+}
+
+// recordSettings exports the current settings as metrics
+func (p *EventProcessor) recordSettings() {
+	p.mu.RLock()
+	config, paused := p.config, p.paused
+	p.mu.RUnlock()
+
+	p.metrics.WorkerPollInterval.Set(config.PollInterval.Seconds())
+	p.metrics.WorkerBatchSize.Set(float64(config.BatchSize))
+	p.metrics.WorkerCount.Set(float64(config.WorkerCount))
+	pausedValue := 0.0
+	if paused {
+		pausedValue = 1
+	}
+	p.metrics.WorkerPaused.Set(pausedValue)
+}
+
+// pollSettings returns what a worker needs for its next poll
+func (p *EventProcessor) pollSettings() (interval time.Duration, changed <-chan struct{}) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.config.PollInterval, p.changed
+}
+
+// batchSettings returns the batch size, or 0 when processing is paused
+func (p *EventProcessor) batchSettings() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.paused {
+		return 0
+	}
+	return p.config.BatchSize
+}
+
+// claimTimeout returns how long claimed events stay processing before they are claimed again
+func (p *EventProcessor) claimTimeout() time.Duration {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.config.ClaimTimeout
+}
+
 // worker is the main processing loop for each worker goroutine
-func (p *EventProcessor) worker(ctx context.Context, workerID int) {
+func (p *EventProcessor) worker(ctx context.Context, workerID int, stop <-chan struct{}, quit <-chan struct{}) {
 	defer p.wg.Done()
 
+	p.metrics.WorkersActive.Set(float64(p.active.Add(1)))
+	defer func() { p.metrics.WorkersActive.Set(float64(p.active.Add(-1))) }()
+
 	_ = level.Debug(p.logger).Log("msg", "worker started", "worker_id", workerID)
 
-	ticker := time.NewTicker(p.pollInterval)
+	interval, changed := p.pollSettings()
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-p.stopChan:
+		case <-stop:
 			_ = level.Debug(p.logger).Log("msg", "worker stopping", "worker_id", workerID)
 			return
+		case <-quit:
+			_ = level.Debug(p.logger).Log("msg", "worker removed", "worker_id", workerID)
+			return
 		case <-ctx.Done():
 			_ = level.Debug(p.logger).Log("msg", "worker context cancelled", "worker_id", workerID)
 			return
+		case <-changed:
+			var newInterval time.Duration
+			newInterval, changed = p.pollSettings()
+			if newInterval != interval {
+				interval = newInterval
+				ticker.Reset(interval)
+			}
 		case <-ticker.C:
-			p.processEvents(ctx, workerID)
+			p.processEvents(ctx, workerID, quit)
 		}
 	}
 }
 
-// processEvents fetches and processes a batch of pending events
-func (p *EventProcessor) processEvents(ctx context.Context, workerID int) {
-	// Get pending events from repository
-	events, err := p.repository.GetPendingEvents(p.batchSize)
+// processEvents claims and processes a batch of pending events
+func (p *EventProcessor) processEvents(ctx context.Context, workerID int, quit <-chan struct{}) {
+	batchSize := p.batchSettings()
+	if batchSize == 0 {
+		return // paused
+	}
+
+	// Claim pending events, other workers and instances skip them until the claim times out
+	events, err := p.repository.ClaimPendingEvents(ctx, batchSize, p.claimTimeout())
 	if err != nil {
-		_ = level.Error(p.logger).Log("msg", "failed to get pending events", "worker_id", workerID, "error", err)
+		_ = level.Error(p.logger).Log("msg", "failed to claim pending events", "worker_id", workerID, "error", err)
 		return
 	}
 
@@ -152,11 +443,20 @@ func (p *EventProcessor) processEvents(ctx context.Context, workerID int) {
 
 	_ = level.Debug(p.logger).Log("msg", "processing events", "worker_id", workerID, "count", len(events))
 
-	// Process each event
-	for _, event := range events {
-		if err := p.processEvent(ctx, &event, workerID); err != nil {
+	// Process each event, releasing the rest of the batch on shutdown, when the worker
+	// is removed or when processing is paused
+	for i := range events {
+		if ctx.Err() != nil || isClosed(quit) || p.batchSettings() == 0 {
+			p.releaseEvents(ctx, workerID, events[i:])
+			return
+		}
+
+		p.startEvent(events[i].ID)
+		err := p.processEvent(ctx, &events[i], workerID)
+		p.finishEvent(events[i].ID)
+		if err != nil {
 			_ = level.Error(p.logger).Log("msg", "failed to process event", "worker_id", workerID, "event_id",
-				event.ID, "error", err)
+				events[i].ID, "error", err)
 			continue
 		}
 	}
@@ -164,17 +464,91 @@ func (p *EventProcessor) processEvents(ctx context.Context, workerID int) {
 	_ = level.Debug(p.logger).Log("msg", "finished processing batch", "worker_id", workerID, "processed", len(events))
 }
 
+// releaseEvents returns claimed events that will not be processed to pending
+func (p *EventProcessor) releaseEvents(ctx context.Context, workerID int, events []models.RocketEvent) {
+	ids := make([]int64, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+
+	// Released on shutdown too, the query is bounded by the repository deadline
+	released, err := p.repository.ReleaseEvents(context.WithoutCancel(ctx), ids)
+
+	p.flightMu.Lock()
+	p.released += released
+	if err != nil {
+		p.unreleased = append(p.unreleased, ids...)
+	}
+	p.flightMu.Unlock()
+
+	if err != nil {
+		_ = level.Error(p.logger).Log("msg", "failed to release claimed events", "worker_id", workerID, "event_ids",
+			fmt.Sprint(ids), "error", err)
+		return
+	}
+	_ = level.Debug(p.logger).Log("msg", "released claimed events", "worker_id", workerID, "count", released)
+}
+
+func (p *EventProcessor) startEvent(id int64) {
+	p.flightMu.Lock()
+	defer p.flightMu.Unlock()
+	p.inFlight[id] = struct{}{}
+}
+
+func (p *EventProcessor) finishEvent(id int64) {
+	p.flightMu.Lock()
+	defer p.flightMu.Unlock()
+	delete(p.inFlight, id)
+}
+
+func (p *EventProcessor) inFlightCount() int {
+	p.flightMu.Lock()
+	defer p.flightMu.Unlock()
+	return len(p.inFlight)
+}
+
+// isClosed tells whether a quit channel has been closed
+func isClosed(quit <-chan struct{}) bool {
+	select {
+	case <-quit:
+		return true
+	default:
+		return false
+	}
+}
+
 // processEvent processes a single event
 func (p *EventProcessor) processEvent(ctx context.Context, event *models.RocketEvent, workerID int) error {
 	start := time.Now()
 
+	// A started event is not cancelled on shutdown, so it is never left half applied.
+	// Its queries are still bounded by the repository deadlines.
+	ctx = context.WithoutCancel(ctx)
+
+	// Continue the request and trace that ingested the event, events stored before
+	// they were recorded get a fresh request ID and trace
+	requestID := pkgContext.GenerateRequestID()
+	if event.RequestID != nil {
+		requestID = *event.RequestID
+	}
+	ctx = pkgContext.WithRequestID(ctx, requestID)
+	if event.TraceParent != nil {
+		ctx = tracing.WithTraceparent(ctx, *event.TraceParent)
+	}
+
+	ctx, span := tracing.StartSpan(ctx, "worker.ProcessEvent", trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attribute.Int("worker.id", workerID), attribute.Int64("rocket.event_id", event.ID)))
+
 	_ = level.Debug(p.logger).Log("msg", "processing event", "worker_id", workerID, "event_id", event.ID, "type",
-		event.MessageType, "channel", event.Channel)
+		event.MessageType, "channel", event.Channel, "requestId", requestID)
 
 	// Process the event using the service
-	err := p.service.ProcessEvent(ctx, event)
+	outcome, err := p.service.ProcessEvent(ctx, event)
+	tracing.EndSpan(span, err)
 
 	duration := time.Since(start)
+	p.metrics.EventsProcessed.With("outcome", string(outcome)).Add(1)
+	p.metrics.EventProcessingDuration.With("outcome", string(outcome)).Observe(duration.Seconds())
 
 	if err != nil {
 		_ = level.Error(p.logger).Log("msg", "event processing failed", "worker_id", workerID, "event_id", event.ID,
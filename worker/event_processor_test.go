@@ -0,0 +1,216 @@
+package worker_test
+
+import (
+	"context"
+	"encoding/json"
+	"rockets-backend/database"
+	"rockets-backend/metrics"
+	"rockets-backend/models"
+	"rockets-backend/repository"
+	"rockets-backend/service"
+	"rockets-backend/testutil"
+	"rockets-backend/worker"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+)
+
+const launchMessage = `{"metadata":{"channel":"193270a9-c9cf-404a-8f83-838e71d9ae67","messageNumber":1,
+	"messageTime":"2022-02-02T19:39:05Z","messageType":"RocketLaunched"},
+	"message":{"type":"Falcon-9","launchSpeed":500,"mission":"ARTEMIS"}}`
+
+func newTestProcessor(t *testing.T, config worker.Config) (*worker.EventProcessor, service.Service) {
+	t.Helper()
+	repo := repository.NewMemoryRocketRepository()
+	svc := service.NewService(log.NewNopLogger(), repo)
+	processor := worker.NewEventProcessor(svc, repo, database.NewMemoryWorkerSettingsStore(), log.NewNopLogger(), metrics.New(), config)
+	testutil.AssertNoError(t, processor.Start(context.Background()))
+	t.Cleanup(func() { _ = processor.Stop() })
+	return processor, svc
+}
+
+// eventually polls check until it holds or a second has passed
+func eventually(t *testing.T, what string, check func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !check() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestReconfigureScalesWorkers(t *testing.T) {
+	processor, _ := newTestProcessor(t, worker.Config{PollInterval: time.Hour, BatchSize: 10, WorkerCount: 2,
+		ClaimTimeout: time.Minute})
+	eventually(t, "2 workers", func() bool { return processor.Status().ActiveWorkers == 2 })
+
+	status, err := processor.Reconfigure(context.Background(), models.WorkerSettings{PollIntervalMs: 10, BatchSize: 5, WorkerCount: 4})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, int64(10), status.PollIntervalMs)
+	testutil.AssertEqual(t, 5, status.BatchSize)
+	eventually(t, "4 workers", func() bool { return processor.Status().ActiveWorkers == 4 })
+
+	_, err = processor.Reconfigure(context.Background(), models.WorkerSettings{PollIntervalMs: 10, BatchSize: 5, WorkerCount: 1})
+	testutil.AssertNoError(t, err)
+	eventually(t, "1 worker", func() bool { return processor.Status().ActiveWorkers == 1 })
+
+	testutil.AssertNoError(t, processor.Stop())
+	testutil.AssertEqual(t, 0, processor.Status().ActiveWorkers)
+}
+
+func TestReconfigureRejectsInvalidSettings(t *testing.T) {
+	processor, _ := newTestProcessor(t, worker.Config{PollInterval: time.Hour, BatchSize: 10, WorkerCount: 1,
+		ClaimTimeout: time.Minute})
+
+	for _, settings := range []models.WorkerSettings{
+		{PollIntervalMs: 0, BatchSize: 10, WorkerCount: 1},
+		{PollIntervalMs: 100, BatchSize: 0, WorkerCount: 1},
+		{PollIntervalMs: 100, BatchSize: 10, WorkerCount: -1},
+	} {
+		if _, err := processor.Reconfigure(context.Background(), settings); err == nil {
+			t.Errorf("Reconfigure(%+v) succeeded, want an error", settings)
+		}
+	}
+
+	status := processor.Status()
+	testutil.AssertEqual(t, time.Hour.Milliseconds(), status.PollIntervalMs)
+	testutil.AssertEqual(t, 1, status.WorkerCount)
+}
+
+func TestPauseAndResume(t *testing.T) {
+	processor, svc := newTestProcessor(t, worker.Config{PollInterval: 10 * time.Millisecond, BatchSize: 10, WorkerCount: 1,
+		ClaimTimeout: time.Minute})
+	paused := models.WorkerSettings{PollIntervalMs: 10, BatchSize: 10, WorkerCount: 1, Paused: true}
+	_, err := processor.Reconfigure(context.Background(), paused)
+	testutil.AssertNoError(t, err)
+
+	var msg models.IncomingMessage
+	testutil.AssertNoError(t, json.Unmarshal([]byte(launchMessage), &msg))
+	event, err := svc.IngestMessage(context.Background(), msg)
+	testutil.AssertNoError(t, err)
+
+	eventStatus := func() string {
+		stored, err := svc.GetEventStatus(context.Background(), event.ID)
+		testutil.AssertNoError(t, err)
+		return stored.Status
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	testutil.AssertEqual(t, models.EventStatusPending, eventStatus())
+
+	paused.Paused = false
+	_, err = processor.Reconfigure(context.Background(), paused)
+	testutil.AssertNoError(t, err)
+	eventually(t, "the event to be processed", func() bool { return eventStatus() == models.EventStatusProcessed })
+}
+
+func TestSettingsAreSharedByProcessors(t *testing.T) {
+	repo := repository.NewMemoryRocketRepository()
+	svc := service.NewService(log.NewNopLogger(), repo)
+	store := database.NewMemoryWorkerSettingsStore()
+	config := worker.Config{PollInterval: 10 * time.Millisecond, BatchSize: 10, WorkerCount: 1, ClaimTimeout: time.Minute}
+	first := worker.NewEventProcessor(svc, repo, store, log.NewNopLogger(), metrics.New(), config)
+	second := worker.NewEventProcessor(svc, repo, store, log.NewNopLogger(), metrics.New(), config)
+	for _, processor := range []*worker.EventProcessor{first, second} {
+		testutil.AssertNoError(t, processor.Start(context.Background()))
+		t.Cleanup(func() { _ = processor.Stop() })
+	}
+
+	// Pausing one instance pauses the other at its next poll
+	paused := models.WorkerSettings{PollIntervalMs: 10, BatchSize: 5, WorkerCount: 3, Paused: true}
+	_, err := first.Reconfigure(context.Background(), paused)
+	testutil.AssertNoError(t, err)
+	eventually(t, "the second processor to pause", func() bool { return second.Status().Paused })
+	testutil.AssertEqual(t, paused, second.Status().WorkerSettings)
+	eventually(t, "3 workers", func() bool { return second.Status().ActiveWorkers == 3 })
+
+	// A refresh picks up the stored settings right away
+	resumed := models.WorkerSettings{PollIntervalMs: time.Hour.Milliseconds(), BatchSize: 10, WorkerCount: 1}
+	testutil.AssertNoError(t, store.Save(context.Background(), resumed))
+	testutil.AssertNoError(t, second.Refresh(context.Background()))
+	testutil.AssertEqual(t, resumed, second.Status().WorkerSettings)
+
+	// A processor started later begins with the stored settings
+	third := worker.NewEventProcessor(svc, repo, store, log.NewNopLogger(), metrics.New(), config)
+	testutil.AssertNoError(t, third.Start(context.Background()))
+	t.Cleanup(func() { _ = third.Stop() })
+	eventually(t, "the stored settings", func() bool { return third.Status().WorkerSettings == resumed })
+}
+
+// blockingService holds ProcessEvent until release is closed
+type blockingService struct {
+	service.Service
+	started chan int64
+	release chan struct{}
+}
+
+func (s blockingService) ProcessEvent(ctx context.Context, event *models.RocketEvent) (service.ProcessOutcome, error) {
+	s.started <- event.ID
+	<-s.release
+	return s.Service.ProcessEvent(ctx, event)
+}
+
+func newBlockedProcessor(t *testing.T, events int) (*worker.EventProcessor, repository.RocketRepository, blockingService) {
+	t.Helper()
+	repo := repository.NewMemoryRocketRepository()
+	svc := blockingService{
+		Service: service.NewService(log.NewNopLogger(), repo),
+		started: make(chan int64, events),
+		release: make(chan struct{}),
+	}
+
+	var msg models.IncomingMessage
+	testutil.AssertNoError(t, json.Unmarshal([]byte(launchMessage), &msg))
+	for i := 1; i <= events; i++ {
+		msg.Metadata.MessageNumber = i
+		_, err := svc.IngestMessage(context.Background(), msg)
+		testutil.AssertNoError(t, err)
+	}
+
+	processor := worker.NewEventProcessor(svc, repo, database.NewMemoryWorkerSettingsStore(), log.NewNopLogger(), metrics.New(),
+		worker.Config{PollInterval: 10 * time.Millisecond, BatchSize: 10, WorkerCount: 1,
+			ClaimTimeout: time.Minute})
+	testutil.AssertNoError(t, processor.Start(context.Background()))
+	return processor, repo, svc
+}
+
+func TestShutdownReleasesClaimedEvents(t *testing.T) {
+	processor, repo, svc := newBlockedProcessor(t, 5)
+	<-svc.started
+
+	done := make(chan worker.ShutdownReport)
+	go func() {
+		report, err := processor.Shutdown(context.Background())
+		testutil.AssertNoError(t, err)
+		done <- report
+	}()
+	eventually(t, "the processor to stop", func() bool { return !processor.IsRunning() })
+	close(svc.release)
+
+	report := <-done
+	testutil.AssertEqual(t, 4, report.Released)
+	testutil.AssertEqual(t, 0, len(report.Abandoned))
+
+	// The current event was finished, the rest is pending again
+	pending, err := repo.GetPendingEvents(context.Background(), 10)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 4, len(pending))
+}
+
+func TestShutdownDeadlineReportsAbandonedEvents(t *testing.T) {
+	processor, _, svc := newBlockedProcessor(t, 1)
+	eventID := <-svc.started
+	defer close(svc.release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	report, err := processor.Shutdown(ctx)
+	if err == nil {
+		t.Fatal("expected a deadline error")
+	}
+	testutil.AssertEqual(t, 1, len(report.Abandoned))
+	testutil.AssertEqual(t, eventID, report.Abandoned[0])
+}
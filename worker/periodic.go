@@ -0,0 +1,164 @@
+package worker
+
+import (
+	"context"
+	pkgContext "rockets-backend/pkg/context"
+	"rockets-backend/repository"
+	"rockets-backend/service"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// PeriodicJob calls a function every interval in the background
+type PeriodicJob struct {
+	name       string
+	run        func(ctx context.Context)
+	logger     log.Logger
+	interval   time.Duration
+	runOnStart bool
+	stopChan   chan struct{}
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+	running    bool
+	mu         sync.RWMutex
+}
+
+// NewPeriodicJob creates a job calling run every interval, and right away on start if
+// runOnStart is set. Each call gets a new request ID.
+func NewPeriodicJob(name string, logger log.Logger, interval time.Duration, runOnStart bool,
+	run func(ctx context.Context)) *PeriodicJob {
+	return &PeriodicJob{
+		name:       name,
+		run:        run,
+		logger:     logger,
+		interval:   interval,
+		runOnStart: runOnStart,
+		stopChan:   make(chan struct{}),
+	}
+}
+
+// StaleDetectorConfig holds configuration for the stale rocket detector
+type StaleDetectorConfig struct {
+	CheckInterval time.Duration // How often to look for stale rockets
+	Threshold     time.Duration // How long a rocket may stay silent before losing contact
+}
+
+// NewStaleDetectorJob creates a job flagging active rockets that stopped reporting as lost contact
+func NewStaleDetectorJob(svc service.Service, logger log.Logger, config StaleDetectorConfig) *PeriodicJob {
+	return NewPeriodicJob("stale rocket detector", logger, config.CheckInterval, false, func(ctx context.Context) {
+		flagged, err := svc.DetectStaleRockets(ctx, config.Threshold)
+		if err != nil {
+			_ = level.Error(logger).Log("msg", "stale rocket check failed", "error", err)
+			return
+		}
+		if flagged > 0 {
+			_ = level.Info(logger).Log("msg", "flagged stale rockets", "count", flagged)
+		}
+	})
+}
+
+// NewRetentionJob creates a job applying the service retention policy every interval
+func NewRetentionJob(svc service.Service, logger log.Logger, interval time.Duration) *PeriodicJob {
+	return NewPeriodicJob("event retention", logger, interval, false, func(ctx context.Context) {
+		// The service logs the outcome
+		_, _ = svc.RunRetention(ctx)
+	})
+}
+
+// NewPartitionJob creates a job maintaining the event partitions every interval, starting
+// right away so the partitions of the coming days exist before events arrive
+func NewPartitionJob(svc service.Service, logger log.Logger, interval time.Duration) *PeriodicJob {
+	return NewPeriodicJob("partition maintenance", logger, interval, true, func(ctx context.Context) {
+		// The service logs the outcome
+		_, _ = svc.MaintainPartitions(ctx)
+	})
+}
+
+// NewReclaimJob creates a job returning events whose claim timed out to pending, so the
+// events an instance left processing when it died are picked up again. It starts right away
+// to reclaim the events of the previous run.
+func NewReclaimJob(repo repository.RocketRepository, logger log.Logger, claimTimeout time.Duration) *PeriodicJob {
+	return NewPeriodicJob("event reclaim", logger, claimTimeout, true, func(ctx context.Context) {
+		reclaimed, err := repo.ReclaimEvents(ctx, claimTimeout)
+		if err != nil {
+			_ = level.Error(logger).Log("msg", "event reclaim failed", "error", err)
+			return
+		}
+		if reclaimed > 0 {
+			_ = level.Warn(logger).Log("msg", "reclaimed events whose claim timed out", "count", reclaimed)
+		}
+	})
+}
+
+// Start runs the job in the background
+func (j *PeriodicJob) Start(ctx context.Context) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.running {
+		return nil // Already running
+	}
+
+	// Cancelled on Stop so that a running call is aborted
+	ctx, j.cancel = context.WithCancel(ctx)
+	j.stopChan = make(chan struct{})
+
+	j.running = true
+	_ = level.Info(j.logger).Log("msg", "starting "+j.name, "interval", j.interval)
+
+	j.wg.Add(1)
+	go j.loop(ctx)
+
+	return nil
+}
+
+// Stop shuts down the job and waits for a running call to return
+func (j *PeriodicJob) Stop() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if !j.running {
+		return nil // Already stopped
+	}
+
+	close(j.stopChan)
+	j.cancel()
+	j.wg.Wait()
+
+	j.running = false
+	_ = level.Info(j.logger).Log("msg", j.name+" stopped")
+
+	return nil
+}
+
+// IsRunning returns whether the job is currently running
+func (j *PeriodicJob) IsRunning() bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.running
+}
+
+func (j *PeriodicJob) loop(ctx context.Context) {
+	defer j.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	if j.runOnStart {
+		j.run(pkgContext.WithRequestID(ctx, pkgContext.GenerateRequestID()))
+	}
+
+	for {
+		select {
+		case <-j.stopChan:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.run(pkgContext.WithRequestID(ctx, pkgContext.GenerateRequestID()))
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package worker
+
+import (
+	"context"
+	"rockets-backend/database"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// Job is a background job that can be started and stopped repeatedly
+type Job interface {
+	Start(ctx context.Context) error
+	Stop() error
+	IsRunning() bool
+}
+
+// Singleton runs a job on one instance at a time, the holder of the job's lease.
+// Every instance tries to take the lease a few times per TTL. The holder renews it
+// and runs the job; when it dies its lease expires and another instance takes over.
+type Singleton struct {
+	name     string
+	job      Job
+	leases   database.LeaseStore
+	instance string
+	ttl      time.Duration
+	logger   log.Logger
+	stopChan chan struct{}
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	running  bool
+	leader   bool
+	mu       sync.RWMutex
+}
+
+// NewSingleton elects instance to run job under the lease name, held for ttl between renewals
+func NewSingleton(name string, job Job, leases database.LeaseStore, instance string, ttl time.Duration,
+	logger log.Logger) *Singleton {
+	return &Singleton{
+		name:     name,
+		job:      job,
+		leases:   leases,
+		instance: instance,
+		ttl:      ttl,
+		logger:   log.With(logger, "job", name),
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start begins competing for the lease in the background
+func (s *Singleton) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return nil // Already running
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.stopChan = make(chan struct{})
+
+	s.running = true
+	_ = level.Info(s.logger).Log("msg", "starting leader election", "instance", s.instance, "ttl", s.ttl)
+
+	s.wg.Add(1)
+	go s.run(ctx)
+
+	return nil
+}
+
+// Stop stops the job if this instance leads and releases the lease, so another
+// instance takes over without waiting for it to expire
+func (s *Singleton) Stop() error {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return nil // Already stopped
+	}
+	close(s.stopChan)
+	s.cancel()
+	s.running = false
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	_ = level.Info(s.logger).Log("msg", "leader election stopped")
+
+	return nil
+}
+
+// IsRunning returns whether the instance takes part in the election, leader or not
+func (s *Singleton) IsRunning() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.running
+}
+
+// IsLeader returns whether this instance holds the lease and runs the job
+func (s *Singleton) IsLeader() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.leader
+}
+
+func (s *Singleton) run(ctx context.Context) {
+	defer s.wg.Done()
+
+	// Renew well before expiry, so a slow query or a missed tick does not lose the lease
+	ticker := time.NewTicker(s.ttl / 3)
+	defer ticker.Stop()
+
+	var renewedAt time.Time
+	for {
+		s.campaign(ctx, &renewedAt)
+
+		select {
+		case <-s.stopChan:
+			s.resign()
+			return
+		case <-ctx.Done():
+			s.resign()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// campaign takes or renews the lease and starts or stops the job to match
+func (s *Singleton) campaign(ctx context.Context, renewedAt *time.Time) {
+	attemptedAt := time.Now()
+	acquired, err := s.leases.TryAcquire(ctx, s.name, s.instance, s.ttl)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		_ = level.Error(s.logger).Log("msg", "failed to renew leadership", "error", err)
+		// Another instance may take over once the lease expires, stop before it can
+		acquired = s.IsLeader() && time.Since(*renewedAt) < s.ttl
+	} else if acquired {
+		*renewedAt = attemptedAt
+	}
+
+	switch {
+	case acquired && !s.IsLeader():
+		_ = level.Info(s.logger).Log("msg", "acquired leadership, starting job", "instance", s.instance)
+		if err := s.job.Start(ctx); err != nil {
+			_ = level.Error(s.logger).Log("msg", "failed to start job", "error", err)
+			return
+		}
+		s.setLeader(true)
+	case !acquired && s.IsLeader():
+		_ = level.Warn(s.logger).Log("msg", "lost leadership, stopping job", "instance", s.instance)
+		s.stopJob()
+	}
+}
+
+// resign stops the job and releases the lease when this instance leads
+func (s *Singleton) resign() {
+	if !s.IsLeader() {
+		return
+	}
+	s.stopJob()
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.ttl)
+	defer cancel()
+	if err := s.leases.Release(ctx, s.name, s.instance); err != nil {
+		_ = level.Error(s.logger).Log("msg", "failed to release leadership, it passes on when the lease expires",
+			"error", err)
+		return
+	}
+	_ = level.Info(s.logger).Log("msg", "released leadership", "instance", s.instance)
+}
+
+func (s *Singleton) stopJob() {
+	if err := s.job.Stop(); err != nil {
+		_ = level.Error(s.logger).Log("msg", "failed to stop job", "error", err)
+	}
+	s.setLeader(false)
+}
+
+func (s *Singleton) setLeader(leader bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leader = leader
+}
@@ -0,0 +1,66 @@
+package worker_test
+
+import (
+	"context"
+	"rockets-backend/database"
+	"rockets-backend/testutil"
+	"rockets-backend/worker"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+)
+
+// fakeJob records whether it runs
+type fakeJob struct {
+	mu      sync.Mutex
+	running bool
+}
+
+func (j *fakeJob) Start(ctx context.Context) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.running = true
+	return nil
+}
+
+func (j *fakeJob) Stop() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.running = false
+	return nil
+}
+
+func (j *fakeJob) IsRunning() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.running
+}
+
+func TestSingletonRunsOnOneInstance(t *testing.T) {
+	leases := database.NewMemoryLeaseStore()
+	jobA, jobB := &fakeJob{}, &fakeJob{}
+	a := worker.NewSingleton("job", jobA, leases, "a", time.Second, log.NewNopLogger())
+	b := worker.NewSingleton("job", jobB, leases, "b", time.Second, log.NewNopLogger())
+
+	testutil.AssertNoError(t, a.Start(context.Background()))
+	eventually(t, "a to lead", a.IsLeader)
+	testutil.AssertNoError(t, b.Start(context.Background()))
+	defer b.Stop()
+
+	time.Sleep(50 * time.Millisecond)
+	testutil.AssertEqual(t, true, jobA.IsRunning())
+	testutil.AssertEqual(t, false, b.IsLeader())
+	testutil.AssertEqual(t, false, jobB.IsRunning())
+
+	// Stopping releases the lease, b takes over on its next attempt
+	testutil.AssertNoError(t, a.Stop())
+	testutil.AssertEqual(t, false, jobA.IsRunning())
+	eventually(t, "b to lead", b.IsLeader)
+	testutil.AssertEqual(t, true, jobB.IsRunning())
+
+	current, err := leases.Leases(context.Background())
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, "b", current[0].Holder)
+}